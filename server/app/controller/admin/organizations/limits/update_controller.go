@@ -0,0 +1,59 @@
+package limits
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	quotaService *service.QuotaService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(quotaService *service.QuotaService) *UpdateController {
+	return &UpdateController{
+		quotaService: quotaService,
+	}
+}
+
+// Handle sets the size of the organization's shared storage, members draw from it once it exists
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+
+	// Get request body
+	var request dto.UpdateLimitDTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	limit, err := uc.quotaService.SetOrganizationLimit(ctx, orgID, request.BytesLimit)
+	if err != nil {
+		switch err.Error() {
+		case "organization not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "organization usage exceeds new limit":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update organization limit", nil, nil)
+		}
+		return
+	}
+
+	// Send success response
+	helper.FormatResponse(ctx, "success", http.StatusOK, "organization limit updated successfully", gin.H{
+		"limit": limit.BytesLimit,
+		"used":  limit.BytesUsage,
+	}, nil)
+}
+
+func (uc *UpdateController) validatePayload(ctx *gin.Context, request *dto.UpdateLimitDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package subscription
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(subscriptionService *service.SubscriptionService) *UpdateController {
+	return &UpdateController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle moves an organization to a plan or along the subscription lifecycle,
+// the plan's storage becomes the organization's shared pool
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	var request dto.UpdateSubscriptionDTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	subscription, err := uc.subscriptionService.AssignOrganizationPlan(ctx.Request.Context(), ctx.Param("orgID"), request.PlanID, request.Status)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid plan ID":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "organization not found", err.Error() == "plan not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case strings.HasPrefix(err.Error(), "invalid subscription status transition"):
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update subscription", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "subscription updated successfully", subscription, nil)
+}
+
+func (uc *UpdateController) validatePayload(ctx *gin.Context, request *dto.UpdateSubscriptionDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
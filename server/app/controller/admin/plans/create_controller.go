@@ -0,0 +1,53 @@
+package plans
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewCreateController initializes a new CreateController
+func NewCreateController(subscriptionService *service.SubscriptionService) *CreateController {
+	return &CreateController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle adds a plan to the catalog
+func (cc *CreateController) Handle(ctx *gin.Context) {
+	var request dto.CreateSubscriptionPlanDTO
+	if err := cc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	plan, err := cc.subscriptionService.CreatePlan(ctx.Request.Context(), &request)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid rate limit"):
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "plan already exists":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create plan", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "plan created successfully", plan, nil)
+}
+
+func (cc *CreateController) validatePayload(ctx *gin.Context, request *dto.CreateSubscriptionPlanDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
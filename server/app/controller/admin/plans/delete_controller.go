@@ -0,0 +1,40 @@
+package plans
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeleteController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewDeleteController initializes a new DeleteController
+func NewDeleteController(subscriptionService *service.SubscriptionService) *DeleteController {
+	return &DeleteController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle removes a plan nobody is subscribed to
+func (dc *DeleteController) Handle(ctx *gin.Context) {
+	err := dc.subscriptionService.DeletePlan(ctx.Request.Context(), ctx.Param("planID"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid plan ID", "default plan cannot be deleted":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "plan not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "plan is in use":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to delete plan", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "plan deleted successfully", nil, nil)
+}
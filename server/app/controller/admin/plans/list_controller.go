@@ -0,0 +1,31 @@
+package plans
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewListController initializes a new ListController
+func NewListController(subscriptionService *service.SubscriptionService) *ListController {
+	return &ListController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle lists the plan catalog
+func (lc *ListController) Handle(ctx *gin.Context) {
+	plans, err := lc.subscriptionService.ListPlans(ctx.Request.Context())
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch plans", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, plans, nil)
+}
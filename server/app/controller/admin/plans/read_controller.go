@@ -0,0 +1,38 @@
+package plans
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewReadController initializes a new ReadController
+func NewReadController(subscriptionService *service.SubscriptionService) *ReadController {
+	return &ReadController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle returns a plan of the catalog
+func (rc *ReadController) Handle(ctx *gin.Context) {
+	plan, err := rc.subscriptionService.ReadPlan(ctx.Request.Context(), ctx.Param("planID"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid plan ID":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "plan not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch plan", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, plan, nil)
+}
@@ -0,0 +1,55 @@
+package plans
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	subscriptionService *service.SubscriptionService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(subscriptionService *service.SubscriptionService) *UpdateController {
+	return &UpdateController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle changes a plan, a new storage size reaches the plan's subscribers right away
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	var request dto.UpdateSubscriptionPlanDTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	plan, err := uc.subscriptionService.UpdatePlan(ctx.Request.Context(), ctx.Param("planID"), &request)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid plan ID", err.Error() == "default plan cannot be renamed", strings.HasPrefix(err.Error(), "invalid rate limit"):
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "plan not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case err.Error() == "plan already exists":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update plan", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "plan updated successfully", plan, nil)
+}
+
+func (uc *UpdateController) validatePayload(ctx *gin.Context, request *dto.UpdateSubscriptionPlanDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package policies
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateController struct {
+	policyService *service.PolicyService
+}
+
+// NewCreateController initializes a new CreateController
+func NewCreateController(policyService *service.PolicyService) *CreateController {
+	return &CreateController{
+		policyService: policyService,
+	}
+}
+
+// Handle creates a policy document
+func (cc *CreateController) Handle(ctx *gin.Context) {
+	var request dto.CreatePolicyDTO
+	if err := cc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	policy, err := cc.policyService.CreatePolicy(ctx.Request.Context(), &request)
+	if err != nil {
+		if err.Error() == "policy already exists" {
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid policy statement") {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create policy", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "policy created successfully", policy, nil)
+}
+
+func (cc *CreateController) validatePayload(ctx *gin.Context, request *dto.CreatePolicyDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
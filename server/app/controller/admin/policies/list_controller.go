@@ -0,0 +1,31 @@
+package policies
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListController struct {
+	policyService *service.PolicyService
+}
+
+// NewListController initializes a new ListController
+func NewListController(policyService *service.PolicyService) *ListController {
+	return &ListController{
+		policyService: policyService,
+	}
+}
+
+// Handle lists all policies
+func (lc *ListController) Handle(ctx *gin.Context) {
+	policies, err := lc.policyService.ListPolicies(ctx.Request.Context())
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch policies", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, policies, nil)
+}
@@ -0,0 +1,43 @@
+package policies
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadController struct {
+	policyService *service.PolicyService
+}
+
+// NewReadController initializes a new ReadController
+func NewReadController(policyService *service.PolicyService) *ReadController {
+	return &ReadController{
+		policyService: policyService,
+	}
+}
+
+// Handle returns a policy document and its linked permissions
+func (rc *ReadController) Handle(ctx *gin.Context) {
+	policyID := ctx.Param("policyID")
+
+	policy, permissions, err := rc.policyService.ReadPolicy(ctx.Request.Context(), policyID)
+	if err != nil {
+		switch err.Error() {
+		case "invalid policy ID":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "policy not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch policy", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"policy":      policy,
+		"permissions": permissions,
+	}, nil)
+}
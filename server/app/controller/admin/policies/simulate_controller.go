@@ -0,0 +1,74 @@
+package policies
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SimulateController struct {
+	policyService *service.PolicyService
+}
+
+// NewSimulateController initializes a new SimulateController
+func NewSimulateController(policyService *service.PolicyService) *SimulateController {
+	return &SimulateController{
+		policyService: policyService,
+	}
+}
+
+// Handle evaluates a hypothetical request against a policy without performing it
+func (sc *SimulateController) Handle(ctx *gin.Context) {
+	policyID := ctx.Param("policyID")
+
+	var request dto.SimulatePolicyDTO
+	if err := sc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	// Default to the current time and the caller's address
+	evaluatedAt := time.Now()
+	if request.Time != nil {
+		evaluatedAt = *request.Time
+	}
+	sourceIP := request.SourceIP
+	if sourceIP == "" {
+		sourceIP = ctx.ClientIP()
+	}
+
+	decision, err := sc.policyService.Evaluate(ctx.Request.Context(), policyID, &service.PolicyRequest{
+		Action:      request.Action,
+		UserID:      request.UserID,
+		DirectoryID: request.DirectoryID,
+		FileID:      request.FileID,
+		SourceIP:    sourceIP,
+		Time:        evaluatedAt,
+		ObjectSize:  request.ObjectSize,
+		IsEncrypted: request.IsEncrypted,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "invalid policy ID":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "policy not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to evaluate policy", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, decision, nil)
+}
+
+func (sc *SimulateController) validatePayload(ctx *gin.Context, request *dto.SimulatePolicyDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
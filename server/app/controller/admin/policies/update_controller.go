@@ -0,0 +1,57 @@
+package policies
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	policyService *service.PolicyService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(policyService *service.PolicyService) *UpdateController {
+	return &UpdateController{
+		policyService: policyService,
+	}
+}
+
+// Handle renames a policy or replaces its statements
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	policyID := ctx.Param("policyID")
+
+	var request dto.UpdatePolicyDTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	policy, err := uc.policyService.UpdatePolicy(ctx.Request.Context(), policyID, &request)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid policy ID", err.Error() == "default policy cannot be renamed", strings.HasPrefix(err.Error(), "invalid policy statement"):
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "policy not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case err.Error() == "policy already exists":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update policy", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "policy updated successfully", policy, nil)
+}
+
+func (uc *UpdateController) validatePayload(ctx *gin.Context, request *dto.UpdatePolicyDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
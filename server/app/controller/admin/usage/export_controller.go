@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController struct {
+	usageService *service.UsageService
+}
+
+// NewExportController initializes a new ExportController
+func NewExportController(usageService *service.UsageService) *ExportController {
+	return &ExportController{
+		usageService: usageService,
+	}
+}
+
+// Handle exports every user's or organization's usage totals between the from and to days, both included,
+// as JSON or as a CSV download with format=csv
+func (ec *ExportController) Handle(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid format", nil, nil)
+		return
+	}
+
+	from, to := ctx.Query("from"), ctx.Query("to")
+	summaries, err := ec.usageService.ExportUsage(ctx.Request.Context(), ctx.DefaultQuery("type", "user"), from, to)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid subject type", err.Error() == "invalid period", strings.HasPrefix(err.Error(), "period too long"):
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to export usage", nil, nil)
+		}
+		return
+	}
+
+	if format == "json" {
+		helper.FormatResponse(ctx, "success", http.StatusOK, "usage exported successfully", summaries, nil)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=usage_%s_%s.csv", from, to))
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	_ = writer.Write([]string{"subject_type", "subject_id", "name", "from", "to", "days", "peak_bytes_stored", "gb_months", "bytes_uploaded", "bytes_downloaded", "file_count", "api_calls"})
+	for _, summary := range summaries {
+		_ = writer.Write([]string{
+			summary.SubjectType,
+			summary.SubjectID,
+			summary.Name,
+			summary.From,
+			summary.To,
+			strconv.Itoa(summary.Days),
+			strconv.FormatInt(summary.PeakBytesStored, 10),
+			strconv.FormatFloat(summary.GBMonths, 'f', 6, 64),
+			strconv.FormatInt(summary.BytesUploaded, 10),
+			strconv.FormatInt(summary.BytesDownloaded, 10),
+			strconv.FormatInt(summary.FileCount, 10),
+			strconv.FormatInt(summary.APICalls, 10),
+		})
+	}
+	writer.Flush()
+}
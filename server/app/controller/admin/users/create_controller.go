@@ -6,6 +6,7 @@ import (
 	"bongaquino/server/app/dto"
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/service"
+	"bongaquino/server/core/logger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,13 +15,15 @@ type CreateController struct {
 	userService  *service.UserService
 	tokenService *service.TokenService
 	emailService *service.EmailService
+	orgService   *service.OrganizationService
 }
 
-func NewCreateController(userService *service.UserService, tokenService *service.TokenService, emailService *service.EmailService) *CreateController {
+func NewCreateController(userService *service.UserService, tokenService *service.TokenService, emailService *service.EmailService, orgService *service.OrganizationService) *CreateController {
 	return &CreateController{
 		userService:  userService,
 		tokenService: tokenService,
 		emailService: emailService,
+		orgService:   orgService,
 	}
 }
 
@@ -61,6 +64,11 @@ func (cc *CreateController) Handle(ctx *gin.Context) {
 		return
 	}
 
+	// Users created by an admin are already verified, so domain auto-join applies right away
+	if err := cc.orgService.HandleDomainJoin(ctx.Request.Context(), user.ID.Hex()); err != nil {
+		logger.Log.Error("failed to join organization by email domain", logger.Error(err), logger.String("user_id", user.ID.Hex()))
+	}
+
 	helper.FormatResponse(ctx, "success", http.StatusCreated, "user created successfully", gin.H{
 		"user": gin.H{
 			"email": user.Email,
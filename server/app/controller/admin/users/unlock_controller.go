@@ -0,0 +1,39 @@
+package users
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UnlockController struct {
+	lockoutService *service.LockoutService
+}
+
+func NewUnlockController(lockoutService *service.LockoutService) *UnlockController {
+	return &UnlockController{
+		lockoutService: lockoutService,
+	}
+}
+
+// Handle lifts any lock on the user and clears their failed login attempts
+func (uc *UnlockController) Handle(ctx *gin.Context) {
+	userID := ctx.Param("userID")
+
+	if err := uc.lockoutService.Unlock(ctx.Request.Context(), userID); err != nil {
+		switch err.Error() {
+		case "user not found", "error fetching user":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, "user not found", nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "user unlocked successfully", gin.H{
+		"user_id": userID,
+	}, nil)
+}
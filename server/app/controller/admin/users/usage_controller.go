@@ -0,0 +1,39 @@
+package users
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageController struct {
+	usageService *service.UsageService
+}
+
+// NewUsageController initializes a new UsageController
+func NewUsageController(usageService *service.UsageService) *UsageController {
+	return &UsageController{
+		usageService: usageService,
+	}
+}
+
+// Handle returns a user's daily usage between the from and to days, both included, with its totals
+func (uc *UsageController) Handle(ctx *gin.Context) {
+	report, err := uc.usageService.ReadUserUsage(ctx.Request.Context(), ctx.Param("userID"), ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		switch {
+		case err.Error() == "invalid user ID", err.Error() == "invalid period", strings.HasPrefix(err.Error(), "period too long"):
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "user not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch usage", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "usage fetched successfully", report, nil)
+}
@@ -11,17 +11,17 @@ import (
 )
 
 type DeleteController struct {
-	fsService   *service.FSService
-	ipfsService *service.IPFSService
-	userService *service.UserService
+	fsService    *service.FSService
+	ipfsService  *service.IPFSService
+	quotaService *service.QuotaService
 }
 
 // NewDeleteController initializes a new DeleteController
-func NewDeleteController(fsService *service.FSService, ipfsService *service.IPFSService, userService *service.UserService) *DeleteController {
+func NewDeleteController(fsService *service.FSService, ipfsService *service.IPFSService, quotaService *service.QuotaService) *DeleteController {
 	return &DeleteController{
-		fsService:   fsService,
-		ipfsService: ipfsService,
-		userService: userService,
+		fsService:    fsService,
+		ipfsService:  ipfsService,
+		quotaService: quotaService,
 	}
 }
 
@@ -74,21 +74,8 @@ func (dc *DeleteController) Handle(ctx *gin.Context) {
 		return
 	}
 
-	// Get user limits (usage)
-	userLimit, err := dc.userService.GetUserLimits(ctx, userID.(string))
-	if err != nil {
-		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to get user limits", nil, nil)
-		return
-	}
-
-	// Compute the new usage
-	newUsage := userLimit.BytesUsage - file.Size
-	if newUsage < 0 {
-		newUsage = 0
-	}
-
-	// Update user usage
-	err = dc.userService.UpdateUserUsage(ctx, userID.(string), newUsage)
+	// Give the file's size back to the user and their organization's shared storage
+	err = dc.quotaService.ReleaseStorage(ctx, userID.(string), file.Size)
 	if err != nil {
 		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update user usage", nil, nil)
 		return
@@ -17,20 +17,26 @@ import (
 )
 
 type UploadController struct {
-	fsService   *service.FSService
-	ipfsService *service.IPFSService
-	userService *service.UserService
+	fsService           *service.FSService
+	ipfsService         *service.IPFSService
+	quotaService        *service.QuotaService
+	subscriptionService *service.SubscriptionService
+	usageService        *service.UsageService
 }
 
 // NewUploadController initializes a new UploadController
 func NewUploadController(fsService *service.FSService,
 	ipfsService *service.IPFSService,
-	userService *service.UserService,
+	quotaService *service.QuotaService,
+	subscriptionService *service.SubscriptionService,
+	usageService *service.UsageService,
 ) *UploadController {
 	return &UploadController{
-		fsService:   fsService,
-		ipfsService: ipfsService,
-		userService: userService,
+		fsService:           fsService,
+		ipfsService:         ipfsService,
+		quotaService:        quotaService,
+		subscriptionService: subscriptionService,
+		usageService:        usageService,
 	}
 }
 
@@ -114,6 +120,21 @@ func (uc *UploadController) Handle(ctx *gin.Context) {
 	fileSize := file.Size
 	fileType := file.Header.Get("Content-Type")
 
+	// Check the file against the uploader's plan, in a team drive that is the member rather than the drive
+	planHolderID := userID.(string)
+	if actorID := ctx.GetString("actorID"); actorID != "" {
+		planHolderID = actorID
+	}
+	if err := uc.subscriptionService.CheckUpload(ctx, planHolderID, fileSize, isEncrypted); err != nil {
+		switch err.Error() {
+		case "file size exceeds the plan limit", "file size exceeds the limit for encrypted uploads":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to get subscription plan", nil, nil)
+		}
+		return
+	}
+
 	// Open the uploaded file
 	src, err := file.Open()
 	if err != nil {
@@ -139,11 +160,6 @@ func (uc *UploadController) Handle(ctx *gin.Context) {
 			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "stream mode is not supported for encrypted uploads", nil, nil)
 			return
 		}
-		// Check file size against the default encrypted size limit
-		if fileSize > fileConfig.DefaultEncryptedSize {
-			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "file size exceeds the limit for encrypted uploads", nil, nil)
-			return
-		}
 		// Encrypt file using FSService
 		var encErr error
 		encryptedFileBytes, salt, nonce, encErr = uc.fsService.EncryptFileForUpload(fileBytes, passphrase)
@@ -180,18 +196,23 @@ func (uc *UploadController) Handle(ctx *gin.Context) {
 		isTrimmed = true
 	}
 
-	// Get user limits
-	userLimit, err := uc.userService.GetUserLimits(ctx, userID.(string))
-	if err != nil {
-		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to get user limits", nil, nil)
-		return
-	}
-
-	// Check if the user has reached their upload limit
-	if userLimit.BytesUsage+fileSize > userLimit.BytesLimit {
-		helper.FormatResponse(ctx, "error", http.StatusForbidden, "upload limit reached", nil, nil)
+	// Reserve the space against the user's limit and their organization's shared storage
+	if err := uc.quotaService.ReserveStorage(ctx, userID.(string), fileSize); err != nil {
+		switch err.Error() {
+		case "upload limit reached", "member storage limit reached", "organization storage limit reached":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to get user limits", nil, nil)
+		}
 		return
 	}
+	stored := false
+	defer func() {
+		// Hand the reserved space back when the file was not saved
+		if !stored {
+			_ = uc.quotaService.ReleaseStorage(ctx, userID.(string), fileSize)
+		}
+	}()
 
 	var cid string
 	var uploadErr error
@@ -241,6 +262,10 @@ func (uc *UploadController) Handle(ctx *gin.Context) {
 		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to save file metadata", nil, nil)
 		return
 	}
+	stored = true
+
+	// Bill the upload to the storage it was counted against
+	uc.usageService.RecordUpload(ctx, userID.(string), fileSize)
 
 	err = uc.fsService.RecalculateDirectorySizeAndParents(ctx, directoryID, userID.(string))
 	if err != nil {
@@ -248,16 +273,6 @@ func (uc *UploadController) Handle(ctx *gin.Context) {
 		return
 	}
 
-	// Compute the new usage
-	newUsage := userLimit.BytesUsage + fileSize
-
-	// Update user usage
-	err = uc.userService.UpdateUserUsage(ctx, userID.(string), newUsage)
-	if err != nil {
-		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update user usage", nil, nil)
-		return
-	}
-
 	if isTrimmed {
 		meta := map[string]any{
 			"is_trimmed": true,
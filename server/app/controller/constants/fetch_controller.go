@@ -51,7 +51,7 @@ func (fc *FetchController) Handle(ctx *gin.Context) {
 		"notifications_frequency_options":       userConfig.NotificationsFrequencyOptions,
 		"recovery_priority_order_options":       userConfig.RecoveryPriorityOrderOptions,
 		"recovery_custom_order_options":         userConfig.RecoveryCustomOrderOptions,
-		"default_bytes_limit":                   userConfig.DefaultBytesLimit,
+		"default_bytes_limit":                   fc.userService.DefaultBytesLimit(ctx),
 		"default_backup_cycle":                  userConfig.DefaultBackupCycle,
 		"default_backup_custom_day":             userConfig.DefaultBackupCustomDay,
 		"default_notifications_frequency":       userConfig.DefaultNotificationsFrequency,
@@ -0,0 +1,129 @@
+package invitations
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"bongaquino/server/core/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AcceptController struct {
+	orgService   *service.OrganizationService
+	userService  *service.UserService
+	tokenService *service.TokenService
+}
+
+func NewAcceptController(orgService *service.OrganizationService, userService *service.UserService, tokenService *service.TokenService) *AcceptController {
+	return &AcceptController{
+		orgService:   orgService,
+		userService:  userService,
+		tokenService: tokenService,
+	}
+}
+
+// Handle accepts an organization invitation, registering the invitee first when they have no account
+func (ac *AcceptController) Handle(ctx *gin.Context) {
+	var request dto.AcceptInvitationDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	invitation, err := ac.orgService.ReadInvitationByToken(ctx.Request.Context(), request.Token)
+	if err != nil {
+		ac.respondInvitationError(ctx, err)
+		return
+	}
+
+	// Match the account however its email was cased, AcceptInvitation compares case-insensitively too
+	user, err := ac.userService.GetUserByEmailCaseInsensitive(ctx.Request.Context(), invitation.Email)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	// Register the invitee, the emailed token already proves they own the address
+	registered := false
+	if user == nil {
+		if request.FirstName == "" || request.LastName == "" || request.Password == "" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "first_name, last_name and password are required to create an account", nil, nil)
+			return
+		}
+		if request.Password != request.ConfirmPassword {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "passwords do not match", nil, nil)
+			return
+		}
+		isValid, validationErr := helper.ValidatePassword(request.Password)
+		if !isValid || validationErr != nil {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, validationErr.Error(), nil, nil)
+			return
+		}
+
+		user, _, _, _, err = ac.userService.CreateUser(ctx.Request.Context(), &dto.CreateUserDTO{
+			FirstName:       request.FirstName,
+			MiddleName:      request.MiddleName,
+			LastName:        request.LastName,
+			Suffix:          request.Suffix,
+			Email:           invitation.Email,
+			Password:        request.Password,
+			ConfirmPassword: request.ConfirmPassword,
+			Role:            "system_user",
+			IsVerified:      true,
+		})
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+			return
+		}
+		registered = true
+	}
+
+	// Join the organization
+	invitation, err = ac.orgService.AcceptInvitation(ctx.Request.Context(), request.Token, user.ID.Hex())
+	if err != nil {
+		// Don't leave behind an account the invitee never got to use
+		if registered {
+			if discardErr := ac.userService.DiscardUser(ctx.Request.Context(), user); discardErr != nil {
+				logger.Log.Error("failed to discard invited user", logger.Error(discardErr), logger.String("email", user.Email))
+			}
+		}
+		ac.respondInvitationError(ctx, err)
+		return
+	}
+
+	response := gin.H{
+		"organization_id": invitation.OrganizationID.Hex(),
+		"email":           invitation.Email,
+	}
+
+	// Sign in newly registered users right away
+	if registered {
+		accessToken, refreshToken, err := ac.tokenService.AuthenticateUser(ctx.Request.Context(), user.Email, request.Password, ctx.ClientIP())
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+			return
+		}
+		response["tokens"] = gin.H{
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+		}
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "invitation accepted successfully", response, nil)
+}
+
+func (ac *AcceptController) respondInvitationError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "invitation not found":
+		helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+	case "invitation expired", "invitation is no longer pending":
+		helper.FormatResponse(ctx, "error", http.StatusGone, err.Error(), nil, nil)
+	case "invitation does not match user", "user is already a member", "user is already a member of another organization":
+		helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+	default:
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to accept invitation", nil, nil)
+	}
+}
@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"net/http"
+	"strings"
+
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenController implements the OAuth2 token endpoint for service accounts
+type TokenController struct {
+	tokenService *service.TokenService
+}
+
+// NewTokenController initializes a new TokenController
+func NewTokenController(tokenService *service.TokenService) *TokenController {
+	return &TokenController{
+		tokenService: tokenService,
+	}
+}
+
+// Handle exchanges client credentials for a short-lived access token.
+// Requests and responses follow RFC 6749 so standard OAuth2 clients work unchanged.
+func (tc *TokenController) Handle(ctx *gin.Context) {
+	var request struct {
+		GrantType           string `form:"grant_type" json:"grant_type"`
+		ClientID            string `form:"client_id" json:"client_id"`
+		ClientSecret        string `form:"client_secret" json:"client_secret"`
+		ClientAssertionType string `form:"client_assertion_type" json:"client_assertion_type"`
+		ClientAssertion     string `form:"client_assertion" json:"client_assertion"`
+		Scope               string `form:"scope" json:"scope"`
+	}
+
+	// Accept both form-encoded and JSON bodies
+	if err := ctx.ShouldBind(&request); err != nil {
+		tc.respondError(ctx, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+
+	// Client credentials may also be sent using HTTP Basic authentication
+	if clientID, clientSecret, ok := ctx.Request.BasicAuth(); ok {
+		request.ClientID = clientID
+		request.ClientSecret = clientSecret
+	}
+
+	if request.GrantType != "client_credentials" {
+		tc.respondError(ctx, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials is supported")
+		return
+	}
+
+	// Key accounts authenticate with a signed JWT as per RFC 7523
+	if request.ClientAssertion != "" {
+		if request.ClientAssertionType != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" {
+			tc.respondError(ctx, http.StatusBadRequest, "invalid_request", "unsupported client_assertion_type")
+			return
+		}
+	} else if request.ClientID == "" || request.ClientSecret == "" {
+		tc.respondError(ctx, http.StatusBadRequest, "invalid_request", "client_id and client_secret or client_assertion are required")
+		return
+	}
+
+	// Authenticate the client and issue an access token
+	accessToken, expiresIn, scopes, err := tc.tokenService.AuthenticateClient(ctx.Request.Context(), request.ClientID, request.ClientSecret, request.ClientAssertion, request.Scope)
+	if err != nil {
+		if err.Error() == "invalid client credentials" || err.Error() == "service account is disabled" {
+			tc.respondError(ctx, http.StatusUnauthorized, "invalid_client", err.Error())
+			return
+		}
+		if err.Error() == "invalid scope" {
+			tc.respondError(ctx, http.StatusBadRequest, "invalid_scope", "requested scope exceeds the service account policy")
+			return
+		}
+		tc.respondError(ctx, http.StatusInternalServerError, "server_error", "failed to issue token")
+		return
+	}
+
+	response := gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(expiresIn.Seconds()),
+	}
+	if len(scopes) > 0 {
+		response["scope"] = strings.Join(scopes, " ")
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, response)
+}
+
+// respondError writes an RFC 6749 error response
+func (tc *TokenController) respondError(ctx *gin.Context, httpStatus int, code, description string) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(httpStatus, gin.H{
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request structure for configuring domain auto-join
+type UpdateAutoJoinRequest struct {
+	Mode   string `json:"mode" binding:"required,oneof=disabled auto_join request"`
+	RoleID string `json:"role_id"`
+}
+
+type AutoJoinController struct {
+	orgService *service.OrganizationService
+}
+
+// NewAutoJoinController initializes a new AutoJoinController
+func NewAutoJoinController(orgService *service.OrganizationService) *AutoJoinController {
+	return &AutoJoinController{
+		orgService: orgService,
+	}
+}
+
+// Handle sets whether users on the verified domain join automatically or request to join
+func (ac *AutoJoinController) Handle(ctx *gin.Context) {
+	orgRole, _ := ctx.Get("orgRole")
+
+	var request UpdateAutoJoinRequest
+	if err := ac.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	org, err := ac.orgService.UpdateAutoJoin(ctx, ctx.Param("orgID"), orgRole.(string), request.Mode, request.RoleID)
+	if err != nil {
+		switch err.Error() {
+		case "organization not found", "role not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "invalid auto-join mode", "domain is not verified", "role is not an organization role":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "cannot assign a role above your own":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update auto-join", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "auto-join updated successfully", gin.H{
+		"domain":  org.Domain,
+		"mode":    org.AutoJoinMode,
+		"role_id": org.AutoJoinRoleID,
+	}, nil)
+}
+
+func (ac *AutoJoinController) validatePayload(ctx *gin.Context, request *UpdateAutoJoinRequest) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChallengeController struct {
+	orgService *service.OrganizationService
+}
+
+// NewChallengeController initializes a new ChallengeController
+func NewChallengeController(orgService *service.OrganizationService) *ChallengeController {
+	return &ChallengeController{
+		orgService: orgService,
+	}
+}
+
+// Handle issues the DNS TXT record that proves ownership of the organization's domain
+func (cc *ChallengeController) Handle(ctx *gin.Context) {
+	recordName, recordValue, err := cc.orgService.CreateDomainChallenge(ctx, ctx.Param("orgID"))
+	if err != nil {
+		switch err.Error() {
+		case "organization not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "organization has no domain":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create domain challenge", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "publish this TXT record, then verify the domain", gin.H{
+		"record_type":  "TXT",
+		"record_name":  recordName,
+		"record_value": recordValue,
+	}, nil)
+}
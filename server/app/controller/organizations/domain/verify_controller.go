@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerifyController struct {
+	orgService *service.OrganizationService
+}
+
+// NewVerifyController initializes a new VerifyController
+func NewVerifyController(orgService *service.OrganizationService) *VerifyController {
+	return &VerifyController{
+		orgService: orgService,
+	}
+}
+
+// Handle checks the published TXT record and marks the domain as verified
+func (vc *VerifyController) Handle(ctx *gin.Context) {
+	org, err := vc.orgService.VerifyDomain(ctx, ctx.Param("orgID"))
+	if err != nil {
+		switch err.Error() {
+		case "organization not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "organization has no domain", "domain challenge not started", "domain verification record not found":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "domain is already verified by another organization":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to verify domain", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "domain verified successfully", gin.H{
+		"domain":      org.Domain,
+		"verified_at": org.DomainVerifiedAt,
+	}, nil)
+}
@@ -0,0 +1,62 @@
+package drives
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateController struct {
+	teamDriveService *service.TeamDriveService
+}
+
+// NewCreateController initializes a new CreateController
+func NewCreateController(teamDriveService *service.TeamDriveService) *CreateController {
+	return &CreateController{
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle creates a team drive owned by the organization
+func (cc *CreateController) Handle(ctx *gin.Context) {
+	actorID, _ := ctx.Get("userID")
+
+	var request dto.CreateTeamDriveDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	drive, err := cc.teamDriveService.CreateDrive(ctx, ctx.Param("orgID"), actorID.(string), request.Name)
+	if err != nil {
+		switch err.Error() {
+		case "organization has no shared storage":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "team drive name already in use":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create team drive", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusCreated, "team drive created successfully", gin.H{
+		"drive": formatDrive(drive),
+	}, nil)
+}
+
+// formatDrive shapes a team drive for responses
+func formatDrive(drive *model.TeamDrive) gin.H {
+	return gin.H{
+		"id":              drive.ID.Hex(),
+		"organization_id": drive.OrganizationID.Hex(),
+		"name":            drive.Name,
+		"created_by":      drive.CreatedBy.Hex(),
+		"created_at":      drive.CreatedAt,
+		"updated_at":      drive.UpdatedAt,
+	}
+}
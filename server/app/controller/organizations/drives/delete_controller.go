@@ -0,0 +1,37 @@
+package drives
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeleteController struct {
+	teamDriveService *service.TeamDriveService
+}
+
+// NewDeleteController initializes a new DeleteController
+func NewDeleteController(teamDriveService *service.TeamDriveService) *DeleteController {
+	return &DeleteController{
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle deletes an empty team drive
+func (dc *DeleteController) Handle(ctx *gin.Context) {
+	if err := dc.teamDriveService.DeleteDrive(ctx, ctx.Param("orgID"), ctx.Param("driveID")); err != nil {
+		switch err.Error() {
+		case "team drive not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "team drive is not empty":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to delete team drive", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "team drive deleted successfully", nil, nil)
+}
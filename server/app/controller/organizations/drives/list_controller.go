@@ -0,0 +1,39 @@
+package drives
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListController struct {
+	teamDriveService *service.TeamDriveService
+}
+
+// NewListController initializes a new ListController
+func NewListController(teamDriveService *service.TeamDriveService) *ListController {
+	return &ListController{
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle lists the organization's team drives with their usage
+func (lc *ListController) Handle(ctx *gin.Context) {
+	drives, err := lc.teamDriveService.ListDrives(ctx, ctx.Param("orgID"))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch team drives", nil, nil)
+		return
+	}
+
+	drivesData := make([]gin.H, len(drives))
+	for i, drive := range drives {
+		drivesData[i] = formatDrive(&drive.Drive)
+		drivesData[i]["used"] = drive.BytesUsage
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"drives": drivesData,
+	}, nil)
+}
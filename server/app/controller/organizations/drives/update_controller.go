@@ -0,0 +1,47 @@
+package drives
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	teamDriveService *service.TeamDriveService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(teamDriveService *service.TeamDriveService) *UpdateController {
+	return &UpdateController{
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle renames a team drive
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	var request dto.UpdateTeamDriveDTO
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	drive, err := uc.teamDriveService.RenameDrive(ctx, ctx.Param("orgID"), ctx.Param("driveID"), request.Name)
+	if err != nil {
+		switch err.Error() {
+		case "team drive not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "team drive name already in use":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update team drive", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "team drive updated successfully", gin.H{
+		"drive": formatDrive(drive),
+	}, nil)
+}
@@ -0,0 +1,36 @@
+package invitations
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListController struct {
+	orgService *service.OrganizationService
+}
+
+// NewListController initializes a new ListController
+func NewListController(orgService *service.OrganizationService) *ListController {
+	return &ListController{
+		orgService: orgService,
+	}
+}
+
+// Handle lists the invitations of the caller's organization
+func (lc *ListController) Handle(ctx *gin.Context) {
+	invitations, err := lc.orgService.ListInvitations(ctx, ctx.Param("orgID"))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch invitations", nil, nil)
+		return
+	}
+
+	// Redact sensitive information
+	for i := range invitations {
+		invitations[i].TokenHash = "REDACTED"
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, invitations, nil)
+}
@@ -0,0 +1,49 @@
+package invitations
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResendController struct {
+	orgService   *service.OrganizationService
+	emailService *service.EmailService
+}
+
+// NewResendController initializes a new ResendController
+func NewResendController(orgService *service.OrganizationService, emailService *service.EmailService) *ResendController {
+	return &ResendController{
+		orgService:   orgService,
+		emailService: emailService,
+	}
+}
+
+// Handle emails a fresh link for a pending or expired invitation
+func (rc *ResendController) Handle(ctx *gin.Context) {
+	invitation, org, token, err := rc.orgService.ResendInvitation(ctx, ctx.Param("orgID"), ctx.Param("invitationID"))
+	if err != nil {
+		switch err.Error() {
+		case "invitation not found", "organization not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "invitation is no longer pending":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to resend invitation", nil, nil)
+		}
+		return
+	}
+
+	// Send the invitation link via email
+	if err := rc.emailService.SendOrganizationInvitation(invitation.Email, org.Name, token); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to send invitation email", nil, nil)
+		return
+	}
+
+	// Redact sensitive information
+	invitation.TokenHash = "REDACTED"
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "invitation resent successfully", invitation, nil)
+}
@@ -0,0 +1,37 @@
+package invitations
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevokeController struct {
+	orgService *service.OrganizationService
+}
+
+// NewRevokeController initializes a new RevokeController
+func NewRevokeController(orgService *service.OrganizationService) *RevokeController {
+	return &RevokeController{
+		orgService: orgService,
+	}
+}
+
+// Handle cancels a pending invitation
+func (rc *RevokeController) Handle(ctx *gin.Context) {
+	if err := rc.orgService.RevokeInvitation(ctx, ctx.Param("orgID"), ctx.Param("invitationID")); err != nil {
+		switch err.Error() {
+		case "invitation not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "invitation is no longer pending":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to revoke invitation", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "invitation revoked successfully", nil, nil)
+}
@@ -0,0 +1,40 @@
+package joinrequests
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApproveController struct {
+	orgService *service.OrganizationService
+}
+
+// NewApproveController initializes a new ApproveController
+func NewApproveController(orgService *service.OrganizationService) *ApproveController {
+	return &ApproveController{
+		orgService: orgService,
+	}
+}
+
+// Handle adds the requesting user to the organization
+func (ac *ApproveController) Handle(ctx *gin.Context) {
+	actorID, _ := ctx.Get("userID")
+
+	err := ac.orgService.ApproveJoinRequest(ctx, ctx.Param("orgID"), ctx.Param("requestID"), actorID.(string))
+	if err != nil {
+		switch err.Error() {
+		case "join request not found", "organization not found", "user not found", "role not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "join request is no longer pending", "user is already a member", "user is already a member of another organization":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to approve join request", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "join request approved successfully", nil, nil)
+}
@@ -0,0 +1,31 @@
+package joinrequests
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ListController struct {
+	orgService *service.OrganizationService
+}
+
+// NewListController initializes a new ListController
+func NewListController(orgService *service.OrganizationService) *ListController {
+	return &ListController{
+		orgService: orgService,
+	}
+}
+
+// Handle lists the join requests of the caller's organization, optionally filtered by ?status=
+func (lc *ListController) Handle(ctx *gin.Context) {
+	joinRequests, err := lc.orgService.ListJoinRequests(ctx, ctx.Param("orgID"), ctx.Query("status"))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch join requests", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, joinRequests, nil)
+}
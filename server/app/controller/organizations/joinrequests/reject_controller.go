@@ -0,0 +1,40 @@
+package joinrequests
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RejectController struct {
+	orgService *service.OrganizationService
+}
+
+// NewRejectController initializes a new RejectController
+func NewRejectController(orgService *service.OrganizationService) *RejectController {
+	return &RejectController{
+		orgService: orgService,
+	}
+}
+
+// Handle declines a pending join request
+func (rc *RejectController) Handle(ctx *gin.Context) {
+	actorID, _ := ctx.Get("userID")
+
+	err := rc.orgService.RejectJoinRequest(ctx, ctx.Param("orgID"), ctx.Param("requestID"), actorID.(string))
+	if err != nil {
+		switch err.Error() {
+		case "join request not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "join request is no longer pending":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to reject join request", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "join request rejected successfully", nil, nil)
+}
@@ -0,0 +1,76 @@
+package members
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request structure for inviting a member
+type InviteMemberRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	RoleID string `json:"role_id" binding:"required"`
+}
+
+type InviteController struct {
+	orgService   *service.OrganizationService
+	emailService *service.EmailService
+}
+
+// NewInviteController initializes a new InviteController
+func NewInviteController(orgService *service.OrganizationService, emailService *service.EmailService) *InviteController {
+	return &InviteController{
+		orgService:   orgService,
+		emailService: emailService,
+	}
+}
+
+// Handle emails an invitation to join the caller's organization
+func (ic *InviteController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+	actorID, _ := ctx.Get("userID")
+	orgRole, _ := ctx.Get("orgRole")
+
+	var request InviteMemberRequest
+	if err := ic.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	invitation, org, token, err := ic.orgService.CreateInvitation(ctx, orgID, actorID.(string), orgRole.(string), request.Email, request.RoleID)
+	if err != nil {
+		switch err.Error() {
+		case "organization not found", "role not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "role is not an organization role":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "cannot assign a role above your own":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		case "user is already a member", "user is already a member of another organization", "invitation already pending":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to invite member", nil, nil)
+		}
+		return
+	}
+
+	// Send the invitation link via email
+	if err := ic.emailService.SendOrganizationInvitation(invitation.Email, org.Name, token); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to send invitation email", nil, nil)
+		return
+	}
+
+	// Redact sensitive information
+	invitation.TokenHash = "REDACTED"
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "invitation sent successfully", invitation, nil)
+}
+
+func (ic *InviteController) validatePayload(ctx *gin.Context, request *InviteMemberRequest) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package members
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RemoveController struct {
+	orgService *service.OrganizationService
+}
+
+// NewRemoveController initializes a new RemoveController
+func NewRemoveController(orgService *service.OrganizationService) *RemoveController {
+	return &RemoveController{
+		orgService: orgService,
+	}
+}
+
+// Handle removes a member from the caller's organization
+func (rc *RemoveController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+	userID := ctx.Param("userID")
+	actorID, _ := ctx.Get("userID")
+	orgRole, _ := ctx.Get("orgRole")
+
+	err := rc.orgService.RemoveOrgMember(ctx, orgID, actorID.(string), orgRole.(string), userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "user is not a member":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "cannot remove yourself":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "cannot manage a member above your own role":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to remove member", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "member removed successfully", gin.H{
+		"org_id":  orgID,
+		"user_id": userID,
+	}, nil)
+}
@@ -0,0 +1,67 @@
+package members
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request structure for changing a member's role
+type UpdateMemberRoleRequest struct {
+	RoleID string `json:"role_id" binding:"required"`
+}
+
+type UpdateRoleController struct {
+	orgService *service.OrganizationService
+}
+
+// NewUpdateRoleController initializes a new UpdateRoleController
+func NewUpdateRoleController(orgService *service.OrganizationService) *UpdateRoleController {
+	return &UpdateRoleController{
+		orgService: orgService,
+	}
+}
+
+// Handle changes the role of a member in the caller's organization
+func (uc *UpdateRoleController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+	userID := ctx.Param("userID")
+	actorID, _ := ctx.Get("userID")
+	orgRole, _ := ctx.Get("orgRole")
+
+	var request UpdateMemberRoleRequest
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	err := uc.orgService.ChangeMemberRole(ctx, orgID, actorID.(string), orgRole.(string), userID, request.RoleID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "role not found", "user is not a member":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "role is not an organization role", "cannot change your own role":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "cannot assign a role above your own", "cannot manage a member above your own role":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update member role", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "member role updated successfully", gin.H{
+		"org_id":  orgID,
+		"user_id": userID,
+		"role_id": request.RoleID,
+	}, nil)
+}
+
+func (uc *UpdateRoleController) validatePayload(ctx *gin.Context, request *UpdateMemberRoleRequest) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
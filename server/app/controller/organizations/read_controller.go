@@ -0,0 +1,42 @@
+package organizations
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadController struct {
+	orgService *service.OrganizationService
+}
+
+// NewReadController initializes a new ReadController
+func NewReadController(orgService *service.OrganizationService) *ReadController {
+	return &ReadController{
+		orgService: orgService,
+	}
+}
+
+// Handle returns the caller's organization and its members
+func (rc *ReadController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+
+	org, members, err := rc.orgService.ReadOrg(ctx, orgID)
+	if err != nil {
+		if err.Error() == "organization not found" {
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, "organization not found", nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch organization", nil, nil)
+		return
+	}
+
+	orgRole, _ := ctx.Get("orgRole")
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"organization": org,
+		"members":      members,
+		"role":         orgRole,
+	}, nil)
+}
@@ -0,0 +1,39 @@
+package serviceaccounts
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BrowseController struct {
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewBrowseController(serviceAccountService *service.ServiceAccountService) *BrowseController {
+	return &BrowseController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (bc *BrowseController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+
+	// Get the list of service accounts for the organization
+	serviceAccounts, err := bc.serviceAccountService.ListOrganizationServiceAccounts(ctx, orgID)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve service accounts", nil, nil)
+		return
+	}
+
+	// Redact sensitive information
+	for _, account := range serviceAccounts {
+		account.ClientSecret = "REDACTED"
+	}
+
+	// Respond with the list of service accounts
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, serviceAccounts, nil)
+}
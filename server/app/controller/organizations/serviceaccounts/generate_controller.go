@@ -0,0 +1,109 @@
+package serviceaccounts
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenerateController struct {
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewGenerateController(serviceAccountService *service.ServiceAccountService) *GenerateController {
+	return &GenerateController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (gc *GenerateController) Handle(ctx *gin.Context) {
+	var request dto.GenerateServiceAccountDTO
+
+	if err := gc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	// Generate client credentials
+	clientID, clientSecret, err := service.GenerateClientCredentials()
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate client credentials", nil, nil)
+		return
+	}
+
+	// The account belongs to the organization and acts as the team drive named in the request,
+	// the creating administrator is only recorded
+	userIDStr := userID.(string)
+	orgID := ctx.Param("orgID")
+	request.UserID = &userIDStr
+	request.OrganizationID = &orgID
+	request.ClientID = &clientID
+
+	// Key accounts get a key pair instead of a secret, only the public key is kept
+	var keyFile *service.ServiceAccountKeyFile
+	if request.CredentialType == "key" {
+		var publicKey string
+		keyFile, publicKey, err = service.GenerateServiceAccountKey(clientID)
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate client credentials", nil, nil)
+			return
+		}
+		request.KeyID = &keyFile.KeyID
+		request.PublicKey = &publicKey
+	} else {
+		request.ClientSecret = &clientSecret
+	}
+
+	// Create a new service account
+	serviceAccount, err := gc.serviceAccountService.CreateServiceAccount(ctx, &request)
+	if err != nil {
+		if err.Error() == "policy not found" || err.Error() == "invalid policy ID" || err.Error() == "directory not found" || err.Error() == "drive ID is required" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		if err.Error() == "team drive not found" {
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		if err.Error() == "service account limit reached" {
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create service account", nil, nil)
+		return
+	}
+
+	// Respond with success
+	data := gin.H{
+		"client_id":       clientID,
+		"policy_id":       serviceAccount.PolicyID.Hex(),
+		"organization_id": orgID,
+		"drive_id":        serviceAccount.UserID.Hex(),
+	}
+	if keyFile != nil {
+		// The key file holds the only copy of the private key
+		data["key_file"] = keyFile
+	} else {
+		data["client_secret"] = clientSecret
+		data["expires_at"] = serviceAccount.Secrets[0].ExpiresAt
+	}
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, data, nil)
+}
+
+func (gc *GenerateController) validatePayload(ctx *gin.Context, request *dto.GenerateServiceAccountDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package sso
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeleteController struct {
+	ssoService *service.SSOService
+}
+
+// NewDeleteController initializes a new DeleteController
+func NewDeleteController(ssoService *service.SSOService) *DeleteController {
+	return &DeleteController{
+		ssoService: ssoService,
+	}
+}
+
+// Handle removes the organization's identity provider, members keep their accounts
+func (dc *DeleteController) Handle(ctx *gin.Context) {
+	if err := dc.ssoService.DeleteConfig(ctx, ctx.Param("orgID")); err != nil {
+		switch err.Error() {
+		case "SSO is not configured":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to delete SSO configuration", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "SSO configuration deleted successfully", nil, nil)
+}
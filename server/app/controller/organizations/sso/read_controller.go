@@ -0,0 +1,54 @@
+package sso
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadController struct {
+	ssoService *service.SSOService
+}
+
+// NewReadController initializes a new ReadController
+func NewReadController(ssoService *service.SSOService) *ReadController {
+	return &ReadController{
+		ssoService: ssoService,
+	}
+}
+
+// Handle returns the organization's identity provider without its client secret
+func (rc *ReadController) Handle(ctx *gin.Context) {
+	sso, err := rc.ssoService.ReadConfig(ctx, ctx.Param("orgID"))
+	if err != nil {
+		switch err.Error() {
+		case "SSO is not configured":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to read SSO configuration", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "SSO configuration read successfully", formatSSO(sso), nil)
+}
+
+// formatSSO shapes the configuration for responses, the client secret never leaves the server
+func formatSSO(sso *model.OrganizationSSO) gin.H {
+	var defaultRoleID any
+	if !sso.DefaultRoleID.IsZero() {
+		defaultRoleID = sso.DefaultRoleID.Hex()
+	}
+	return gin.H{
+		"organization_id": sso.OrganizationID.Hex(),
+		"issuer":          sso.Issuer,
+		"client_id":       sso.ClientID,
+		"allowed_domains": sso.AllowedDomains,
+		"default_role_id": defaultRoleID,
+		"is_enabled":      sso.IsEnabled,
+		"updated_at":      sso.UpdatedAt,
+	}
+}
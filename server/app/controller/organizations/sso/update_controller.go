@@ -0,0 +1,52 @@
+package sso
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateController struct {
+	ssoService *service.SSOService
+}
+
+// NewUpdateController initializes a new UpdateController
+func NewUpdateController(ssoService *service.SSOService) *UpdateController {
+	return &UpdateController{
+		ssoService: ssoService,
+	}
+}
+
+// Handle creates or replaces the organization's identity provider
+func (uc *UpdateController) Handle(ctx *gin.Context) {
+	var request dto.UpdateOrganizationSSODTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	sso, err := uc.ssoService.UpdateConfig(ctx, ctx.Param("orgID"), &request)
+	if err != nil {
+		switch err.Error() {
+		case "organization not found", "role not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "issuer must use https on a public host", "issuer discovery failed", "role is not an organization role", "client secret is required", "allowed domains are required":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update SSO configuration", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "SSO configuration updated successfully", formatSSO(sso), nil)
+}
+
+func (uc *UpdateController) validatePayload(ctx *gin.Context, request *dto.UpdateOrganizationSSODTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateMemberLimitController struct {
+	quotaService *service.QuotaService
+}
+
+// NewUpdateMemberLimitController initializes a new UpdateMemberLimitController
+func NewUpdateMemberLimitController(quotaService *service.QuotaService) *UpdateMemberLimitController {
+	return &UpdateMemberLimitController{
+		quotaService: quotaService,
+	}
+}
+
+// Handle caps how much of the organization's shared storage a member can use
+func (uc *UpdateMemberLimitController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+	userID := ctx.Param("userID")
+
+	var request dto.UpdateMemberLimitDTO
+	if err := uc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	if err := uc.quotaService.SetMemberLimit(ctx, orgID, userID, request.BytesLimit); err != nil {
+		switch err.Error() {
+		case "user is not a member":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "organization has no shared storage", "member limit exceeds organization limit", "member usage exceeds new limit":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update member limit", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "member limit updated successfully", gin.H{
+		"org_id":      orgID,
+		"user_id":     userID,
+		"bytes_limit": request.BytesLimit,
+	}, nil)
+}
+
+func (uc *UpdateMemberLimitController) validatePayload(ctx *gin.Context, request *dto.UpdateMemberLimitDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageController struct {
+	quotaService     *service.QuotaService
+	teamDriveService *service.TeamDriveService
+}
+
+// NewUsageController initializes a new UsageController
+func NewUsageController(quotaService *service.QuotaService, teamDriveService *service.TeamDriveService) *UsageController {
+	return &UsageController{
+		quotaService:     quotaService,
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle returns the organization's shared storage and how much each member and team drive uses
+func (uc *UsageController) Handle(ctx *gin.Context) {
+	orgID := ctx.Param("orgID")
+
+	pool, members, err := uc.quotaService.GetOrganizationUsage(ctx, orgID)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	drives, err := uc.teamDriveService.ListDrives(ctx, orgID)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+	drivesData := make([]gin.H, len(drives))
+	for i, drive := range drives {
+		drivesData[i] = gin.H{
+			"drive_id":    drive.Drive.ID.Hex(),
+			"name":        drive.Drive.Name,
+			"bytes_usage": drive.BytesUsage,
+		}
+	}
+
+	// Organizations without shared storage only get the breakdown
+	var storage gin.H
+	if pool != nil {
+		storage = gin.H{
+			"limit": pool.BytesLimit,
+			"used":  pool.BytesUsage,
+		}
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"storage": storage,
+		"members": members,
+		"drives":  drivesData,
+	}, nil)
+}
@@ -0,0 +1,44 @@
+package profile
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelDeletionController handles cancelling a scheduled account deletion
+type CancelDeletionController struct {
+	accountDeletionService *service.AccountDeletionService
+}
+
+// NewCancelDeletionController initializes a new CancelDeletionController
+func NewCancelDeletionController(accountDeletionService *service.AccountDeletionService) *CancelDeletionController {
+	return &CancelDeletionController{
+		accountDeletionService: accountDeletionService,
+	}
+}
+
+// Handle keeps the account
+func (cdc *CancelDeletionController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+		return
+	}
+
+	if err := cdc.accountDeletionService.CancelDeletion(ctx.Request.Context(), userID.(string)); err != nil {
+		if err.Error() == "no account deletion scheduled" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to cancel account deletion", nil, nil)
+		return
+	}
+
+	// Respond with success
+	helper.FormatResponse(ctx, "success", http.StatusOK, "account deletion cancelled", nil, nil)
+}
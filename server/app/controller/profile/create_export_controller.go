@@ -0,0 +1,58 @@
+package profile
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateExportController handles requests for a copy of the user's data
+type CreateExportController struct {
+	dataExportService *service.DataExportService
+}
+
+// NewCreateExportController initializes a new CreateExportController
+func NewCreateExportController(dataExportService *service.DataExportService) *CreateExportController {
+	return &CreateExportController{
+		dataExportService: dataExportService,
+	}
+}
+
+// Handle queues an export, a download link is emailed once it is built
+func (cec *CreateExportController) Handle(ctx *gin.Context) {
+	var request dto.CreateDataExportDTO
+
+	// The body is optional, an empty one exports metadata only
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+			return
+		}
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+		return
+	}
+
+	export, err := cec.dataExportService.RequestExport(ctx.Request.Context(), userID.(string), request.IncludeFiles)
+	if err != nil {
+		if err.Error() == "data export already in progress" {
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to request data export", nil, nil)
+		return
+	}
+
+	// Respond with the queued export
+	helper.FormatResponse(ctx, "success", http.StatusAccepted, "data export requested, a download link will be emailed", gin.H{
+		"export": export,
+	}, nil)
+}
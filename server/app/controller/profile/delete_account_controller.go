@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteAccountController handles account deletion requests
+type DeleteAccountController struct {
+	accountDeletionService *service.AccountDeletionService
+}
+
+// NewDeleteAccountController initializes a new DeleteAccountController
+func NewDeleteAccountController(accountDeletionService *service.AccountDeletionService) *DeleteAccountController {
+	return &DeleteAccountController{
+		accountDeletionService: accountDeletionService,
+	}
+}
+
+// Handle schedules the account for deletion after the grace period
+func (dac *DeleteAccountController) Handle(ctx *gin.Context) {
+	var request dto.DeleteAccountDTO
+
+	// Validate the payload
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+		return
+	}
+
+	deleteAt, err := dac.accountDeletionService.ScheduleDeletion(ctx.Request.Context(), userID.(string), request.Password)
+	if err != nil {
+		switch err.Error() {
+		case "password is incorrect":
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+		case "account deletion already scheduled":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to schedule account deletion", nil, nil)
+		}
+		return
+	}
+
+	// Respond with the deletion date
+	helper.FormatResponse(ctx, "success", http.StatusOK, "account scheduled for deletion", gin.H{
+		"delete_at": deleteAt,
+	}, nil)
+}
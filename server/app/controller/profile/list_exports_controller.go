@@ -0,0 +1,54 @@
+package profile
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListExportsController lists the user's data exports
+type ListExportsController struct {
+	dataExportService *service.DataExportService
+}
+
+// NewListExportsController initializes a new ListExportsController
+func NewListExportsController(dataExportService *service.DataExportService) *ListExportsController {
+	return &ListExportsController{
+		dataExportService: dataExportService,
+	}
+}
+
+// Handle returns the user's exports, newest first, with the download link of the ones still available
+func (lec *ListExportsController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+		return
+	}
+
+	exports, err := lec.dataExportService.ListExports(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	exportsData := make([]gin.H, len(exports))
+	for i := range exports {
+		exportsData[i] = gin.H{
+			"export":       exports[i],
+			"download_url": nil,
+		}
+		if exports[i].IsDownloadable() {
+			exportsData[i]["download_url"] = lec.dataExportService.DownloadURL(&exports[i])
+		}
+	}
+
+	// Respond with the exports
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"exports": exportsData,
+	}, nil)
+}
@@ -0,0 +1,52 @@
+package profile
+
+import (
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginHistoryController lists the signed-in user's login attempts
+type LoginHistoryController struct {
+	loginEventService *service.LoginEventService
+}
+
+// NewLoginHistoryController initializes a new LoginHistoryController
+func NewLoginHistoryController(loginEventService *service.LoginEventService) *LoginHistoryController {
+	return &LoginHistoryController{
+		loginEventService: loginEventService,
+	}
+}
+
+// Handle returns the user's login history, newest first
+func (lc *LoginHistoryController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+		return
+	}
+
+	// Get pagination parameters from query params
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid page parameter", nil, nil)
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid limit parameter", nil, nil)
+		return
+	}
+
+	events, err := lc.loginEventService.ListLoginHistory(ctx.Request.Context(), userID.(string), page, limit)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "login history fetched successfully", events, nil)
+}
@@ -38,9 +38,10 @@ func (hc *MeController) Handle(ctx *gin.Context) {
 
 	// Sanitize the user object by removing sensitive fields
 	sanitizedUser := gin.H{
-		"id":          user.ID,
-		"email":       user.Email,
-		"is_verified": user.IsVerified,
+		"id":                    user.ID,
+		"email":                 user.Email,
+		"is_verified":           user.IsVerified,
+		"deletion_scheduled_at": user.DeletionScheduledAt,
 	}
 
 	// Sanitize the profile object by removing sensitive fields
@@ -0,0 +1,49 @@
+package exports
+
+import (
+	"net/http"
+	"strconv"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadController serves data export archives through their signed links
+type DownloadController struct {
+	dataExportService *service.DataExportService
+}
+
+// NewDownloadController initializes a new DownloadController
+func NewDownloadController(dataExportService *service.DataExportService) *DownloadController {
+	return &DownloadController{
+		dataExportService: dataExportService,
+	}
+}
+
+// Handle sends the archive the signed token points to
+func (dc *DownloadController) Handle(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "token is required", nil, nil)
+		return
+	}
+
+	export, content, err := dc.dataExportService.DownloadExport(ctx.Request.Context(), token)
+	if err != nil {
+		if err.Error() == "invalid or expired download link" {
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=export-"+export.CreatedAt.UTC().Format("2006-01-02")+".zip")
+	ctx.Header("Content-Length", strconv.Itoa(len(content)))
+	ctx.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	ctx.Header("Pragma", "no-cache")
+	ctx.Header("Expires", "0")
+	ctx.Data(http.StatusOK, "application/zip", content)
+}
@@ -14,15 +14,17 @@ import (
 )
 
 type DownloadController struct {
-	fsService   *service.FSService
-	ipfsService *service.IPFSService
+	fsService    *service.FSService
+	ipfsService  *service.IPFSService
+	usageService *service.UsageService
 }
 
 // NewDownloadController initializes a new DownloadController
-func NewDownloadController(fsService *service.FSService, ipfsService *service.IPFSService) *DownloadController {
+func NewDownloadController(fsService *service.FSService, ipfsService *service.IPFSService, usageService *service.UsageService) *DownloadController {
 	return &DownloadController{
-		fsService:   fsService,
-		ipfsService: ipfsService,
+		fsService:    fsService,
+		ipfsService:  ipfsService,
+		usageService: usageService,
 	}
 }
 
@@ -102,6 +104,13 @@ func (dc *DownloadController) Handle(ctx *gin.Context) {
 		}
 	}
 
+	// Bill the bytes served to the storage the file is kept in
+	defer func() {
+		if ctx.Writer.Status() == http.StatusOK && ctx.Writer.Size() > 0 {
+			dc.usageService.RecordDownload(ctx.Request.Context(), file.UserID.Hex(), int64(ctx.Writer.Size()))
+		}
+	}()
+
 	// Check if file is encrypted
 	isEncrypted := file.IsEncrypted
 
@@ -45,20 +45,50 @@ func (gc *GenerateController) Handle(ctx *gin.Context) {
 	userIDStr := userID.(string)
 	request.UserID = &userIDStr
 	request.ClientID = &clientID
-	request.ClientSecret = &clientSecret
+
+	// Key accounts get a key pair instead of a secret, only the public key is kept
+	var keyFile *service.ServiceAccountKeyFile
+	if request.CredentialType == "key" {
+		var publicKey string
+		keyFile, publicKey, err = service.GenerateServiceAccountKey(clientID)
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate client credentials", nil, nil)
+			return
+		}
+		request.KeyID = &keyFile.KeyID
+		request.PublicKey = &publicKey
+	} else {
+		request.ClientSecret = &clientSecret
+	}
 
 	// Create a new service account
-	_, err = gc.serviceAccountService.CreateServiceAccount(ctx, &request)
+	serviceAccount, err := gc.serviceAccountService.CreateServiceAccount(ctx, &request)
 	if err != nil {
+		if err.Error() == "policy not found" || err.Error() == "invalid policy ID" || err.Error() == "directory not found" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		if err.Error() == "service account limit reached" {
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+			return
+		}
 		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create service account", nil, nil)
 		return
 	}
 
 	// Respond with success
-	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
-		"client_id":     clientID,
-		"client_secret": clientSecret,
-	}, nil)
+	data := gin.H{
+		"client_id": clientID,
+		"policy_id": serviceAccount.PolicyID.Hex(),
+	}
+	if keyFile != nil {
+		// The key file holds the only copy of the private key
+		data["key_file"] = keyFile
+	} else {
+		data["client_secret"] = clientSecret
+		data["expires_at"] = serviceAccount.Secrets[0].ExpiresAt
+	}
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, data, nil)
 }
 
 func (rc *GenerateController) validatePayload(ctx *gin.Context, request *dto.GenerateServiceAccountDTO) error {
@@ -0,0 +1,82 @@
+package serviceaccounts
+
+import (
+	"net/http"
+	"time"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"bongaquino/server/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RotateController struct {
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewRotateController(serviceAccountService *service.ServiceAccountService) *RotateController {
+	return &RotateController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// Handle issues a new client secret or key file, the previous one keeps working for the grace period
+func (rc *RotateController) Handle(ctx *gin.Context) {
+	var request dto.RotateServiceAccountSecretDTO
+
+	// Validate the payload
+	if err := rc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	gracePeriod := config.LoadServiceAccountConfig().RotationGracePeriod
+	if request.GracePeriodHours != nil {
+		gracePeriod = time.Duration(*request.GracePeriodHours) * time.Hour
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	// Issue the new secret
+	credentials, err := rc.serviceAccountService.RotateServiceAccountSecret(ctx.Request.Context(), userID.(string), request.ClientID, gracePeriod)
+	if err != nil {
+		if err.Error() == "service account not found" {
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	// Respond with the new key file for key accounts
+	if credentials.KeyFile != nil {
+		helper.FormatResponse(ctx, "success", http.StatusOK, "key rotated successfully", gin.H{
+			"client_id": request.ClientID,
+			"key_file":  credentials.KeyFile,
+		}, nil)
+		return
+	}
+
+	// Respond with the new secret, it is not shown again
+	helper.FormatResponse(ctx, "success", http.StatusOK, "client secret rotated successfully", gin.H{
+		"client_id":     request.ClientID,
+		"client_secret": credentials.ClientSecret,
+		"secret_id":     credentials.ID,
+		"expires_at":    credentials.ExpiresAt,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (rc *RotateController) validatePayload(ctx *gin.Context, request *dto.RotateServiceAccountSecretDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
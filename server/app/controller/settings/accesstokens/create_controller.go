@@ -0,0 +1,68 @@
+package accesstokens
+
+import (
+	"net/http"
+	"strings"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateController issues personal access tokens
+type CreateController struct {
+	personalAccessTokenService *service.PersonalAccessTokenService
+}
+
+// NewCreateController initializes a new CreateController
+func NewCreateController(personalAccessTokenService *service.PersonalAccessTokenService) *CreateController {
+	return &CreateController{
+		personalAccessTokenService: personalAccessTokenService,
+	}
+}
+
+// Handle creates a token, its secret is only ever shown in this response
+func (cc *CreateController) Handle(ctx *gin.Context) {
+	var request dto.CreatePersonalAccessTokenDTO
+
+	// Validate the payload
+	if err := cc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	token, pat, err := cc.personalAccessTokenService.CreateToken(ctx.Request.Context(), userID.(string), &request)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid scope"), err.Error() == "expiry is too long":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case err.Error() == "personal access token limit reached":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusCreated, "personal access token created successfully", gin.H{
+		"token":                 token,
+		"personal_access_token": pat,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (cc *CreateController) validatePayload(ctx *gin.Context, request *dto.CreatePersonalAccessTokenDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
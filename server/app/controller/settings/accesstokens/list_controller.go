@@ -0,0 +1,40 @@
+package accesstokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListController lists the user's personal access tokens
+type ListController struct {
+	personalAccessTokenService *service.PersonalAccessTokenService
+}
+
+// NewListController initializes a new ListController
+func NewListController(personalAccessTokenService *service.PersonalAccessTokenService) *ListController {
+	return &ListController{
+		personalAccessTokenService: personalAccessTokenService,
+	}
+}
+
+// Handle returns the user's tokens with their scopes and last use, never the secrets
+func (lc *ListController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	tokens, err := lc.personalAccessTokenService.ListTokens(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, tokens, nil)
+}
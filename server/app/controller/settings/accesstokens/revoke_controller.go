@@ -0,0 +1,39 @@
+package accesstokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeController deletes personal access tokens
+type RevokeController struct {
+	personalAccessTokenService *service.PersonalAccessTokenService
+}
+
+// NewRevokeController initializes a new RevokeController
+func NewRevokeController(personalAccessTokenService *service.PersonalAccessTokenService) *RevokeController {
+	return &RevokeController{
+		personalAccessTokenService: personalAccessTokenService,
+	}
+}
+
+// Handle revokes the token named in the path, it stops working immediately
+func (rc *RevokeController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	if err := rc.personalAccessTokenService.RevokeToken(ctx.Request.Context(), userID.(string), ctx.Param("tokenID")); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "personal access token revoked successfully", nil, nil)
+}
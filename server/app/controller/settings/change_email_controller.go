@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeEmailController handles requests to change the login email
+type ChangeEmailController struct {
+	emailChangeService *service.EmailChangeService
+}
+
+// NewChangeEmailController initializes a new ChangeEmailController
+func NewChangeEmailController(emailChangeService *service.EmailChangeService) *ChangeEmailController {
+	return &ChangeEmailController{
+		emailChangeService: emailChangeService,
+	}
+}
+
+// Handle sends a confirmation code to the new address and a cancel link to the current one
+func (cec *ChangeEmailController) Handle(ctx *gin.Context) {
+	var request dto.ChangeEmailDTO
+
+	// Validate the payload
+	if err := cec.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	// Start the email change
+	err := cec.emailChangeService.RequestEmailChange(ctx.Request.Context(), userID.(string), request.NewEmail, request.Password)
+	if err != nil {
+		switch err.Error() {
+		case "password is incorrect":
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+		case "new email must be different from the current email":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "email already in use":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "email change request failed", nil, nil)
+		}
+		return
+	}
+
+	// Return success response
+	helper.FormatResponse(ctx, "success", http.StatusOK, "confirmation code sent to the new email", nil, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (cec *ChangeEmailController) validatePayload(ctx *gin.Context, request *dto.ChangeEmailDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
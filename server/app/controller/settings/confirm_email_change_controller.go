@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmEmailChangeController handles confirming a login email change
+type ConfirmEmailChangeController struct {
+	emailChangeService *service.EmailChangeService
+}
+
+// NewConfirmEmailChangeController initializes a new ConfirmEmailChangeController
+func NewConfirmEmailChangeController(emailChangeService *service.EmailChangeService) *ConfirmEmailChangeController {
+	return &ConfirmEmailChangeController{
+		emailChangeService: emailChangeService,
+	}
+}
+
+// Handle swaps the login email using the code sent to the new address, every session is signed out
+func (cecc *ConfirmEmailChangeController) Handle(ctx *gin.Context) {
+	var request dto.ConfirmEmailChangeDTO
+
+	// Validate the payload
+	if err := cecc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	// Confirm the email change
+	email, err := cecc.emailChangeService.ConfirmEmailChange(ctx.Request.Context(), userID.(string), request.Code)
+	if err != nil {
+		switch err.Error() {
+		case "no pending email change", "invalid confirmation code":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "email already in use":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "email change failed", nil, nil)
+		}
+		return
+	}
+
+	// Return success response
+	helper.FormatResponse(ctx, "success", http.StatusOK, "email changed successfully, please sign in again", gin.H{
+		"email": email,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (cecc *ConfirmEmailChangeController) validatePayload(ctx *gin.Context, request *dto.ConfirmEmailChangeDTO) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BeginPasskeyRegistrationController starts registering a passkey
+type BeginPasskeyRegistrationController struct {
+	webauthnService *service.WebAuthnService
+}
+
+// NewBeginPasskeyRegistrationController initializes a new BeginPasskeyRegistrationController
+func NewBeginPasskeyRegistrationController(webauthnService *service.WebAuthnService) *BeginPasskeyRegistrationController {
+	return &BeginPasskeyRegistrationController{
+		webauthnService: webauthnService,
+	}
+}
+
+// Handle returns the creation options to pass to navigator.credentials.create
+func (bprc *BeginPasskeyRegistrationController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	options, err := bprc.webauthnService.BeginRegistration(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err.Error() == "user not found" {
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create passkey challenge", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"options": options,
+	}, nil)
+}
@@ -0,0 +1,39 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeletePasskeyController removes one of the user's passkeys
+type DeletePasskeyController struct {
+	webauthnService *service.WebAuthnService
+}
+
+// NewDeletePasskeyController initializes a new DeletePasskeyController
+func NewDeletePasskeyController(webauthnService *service.WebAuthnService) *DeletePasskeyController {
+	return &DeletePasskeyController{
+		webauthnService: webauthnService,
+	}
+}
+
+// Handle deletes the passkey named in the path
+func (dpc *DeletePasskeyController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	if err := dpc.webauthnService.DeleteCredential(ctx.Request.Context(), userID.(string), ctx.Param("passkeyID")); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "passkey deleted successfully", nil, nil)
+}
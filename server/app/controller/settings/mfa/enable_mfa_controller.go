@@ -55,12 +55,14 @@ func (voc *EnableMFAController) Handle(ctx *gin.Context) {
 	}
 
 	// Enable MFA for the user
-	err = voc.mfaService.EnableMFA(ctx.Request.Context(), userID.(string))
+	recoveryCodes, err := voc.mfaService.EnableMFA(ctx.Request.Context(), userID.(string))
 	if err != nil {
 		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
 		return
 	}
 
-	// Respond with success
-	helper.FormatResponse(ctx, "success", http.StatusOK, "MFA enabled successfully", nil, nil)
+	// Respond with success, the recovery codes are only shown once
+	helper.FormatResponse(ctx, "success", http.StatusOK, "MFA enabled successfully", gin.H{
+		"recovery_codes": recoveryCodes,
+	}, nil)
 }
@@ -0,0 +1,65 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FinishPasskeyRegistrationController stores a newly created passkey
+type FinishPasskeyRegistrationController struct {
+	webauthnService *service.WebAuthnService
+}
+
+// NewFinishPasskeyRegistrationController initializes a new FinishPasskeyRegistrationController
+func NewFinishPasskeyRegistrationController(webauthnService *service.WebAuthnService) *FinishPasskeyRegistrationController {
+	return &FinishPasskeyRegistrationController{
+		webauthnService: webauthnService,
+	}
+}
+
+// Handle verifies the registration response and saves the passkey
+func (fprc *FinishPasskeyRegistrationController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	var request struct {
+		Name       string                     `json:"name" binding:"max=64"`
+		Credential dto.WebAuthnAttestationDTO `json:"credential" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	credential, err := fprc.webauthnService.FinishRegistration(ctx.Request.Context(), userID.(string), request.Name, &request.Credential)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "passkey is already registered":
+			helper.FormatResponse(ctx, "error", http.StatusConflict, err.Error(), nil, nil)
+		case "error fetching user", "error fetching passkey", "error saving passkey":
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to register passkey", nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusCreated, "passkey registered successfully", gin.H{
+		"id":            credential.ID,
+		"name":          credential.Name,
+		"credential_id": credential.CredentialID,
+		"transports":    credential.Transports,
+		"created_at":    credential.CreatedAt,
+	}, nil)
+}
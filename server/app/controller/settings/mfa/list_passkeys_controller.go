@@ -0,0 +1,52 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPasskeysController lists the user's passkeys
+type ListPasskeysController struct {
+	webauthnService *service.WebAuthnService
+}
+
+// NewListPasskeysController initializes a new ListPasskeysController
+func NewListPasskeysController(webauthnService *service.WebAuthnService) *ListPasskeysController {
+	return &ListPasskeysController{
+		webauthnService: webauthnService,
+	}
+}
+
+// Handle returns the user's passkeys without their public keys
+func (lpc *ListPasskeysController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	credentials, err := lpc.webauthnService.ListCredentials(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch passkeys", nil, nil)
+		return
+	}
+
+	passkeys := make([]gin.H, len(credentials))
+	for i, credential := range credentials {
+		passkeys[i] = gin.H{
+			"id":            credential.ID,
+			"name":          credential.Name,
+			"credential_id": credential.CredentialID,
+			"transports":    credential.Transports,
+			"last_used_at":  credential.LastUsedAt,
+			"created_at":    credential.CreatedAt,
+		}
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, passkeys, nil)
+}
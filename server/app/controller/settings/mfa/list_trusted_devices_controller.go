@@ -0,0 +1,40 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrustedDevicesController lists the browsers that skip the second factor
+type ListTrustedDevicesController struct {
+	mfaService *service.MFAService
+}
+
+// NewListTrustedDevicesController initializes a new ListTrustedDevicesController
+func NewListTrustedDevicesController(mfaService *service.MFAService) *ListTrustedDevicesController {
+	return &ListTrustedDevicesController{
+		mfaService: mfaService,
+	}
+}
+
+// Handle returns the user's unexpired trusted devices
+func (ltc *ListTrustedDevicesController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	devices, err := ltc.mfaService.ListTrustedDevices(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, devices, nil)
+}
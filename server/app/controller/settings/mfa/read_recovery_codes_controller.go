@@ -0,0 +1,42 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadRecoveryCodesController reports how many recovery codes the user has left
+type ReadRecoveryCodesController struct {
+	mfaService *service.MFAService
+}
+
+// NewReadRecoveryCodesController initializes a new ReadRecoveryCodesController
+func NewReadRecoveryCodesController(mfaService *service.MFAService) *ReadRecoveryCodesController {
+	return &ReadRecoveryCodesController{
+		mfaService: mfaService,
+	}
+}
+
+// Handle returns the number of unused recovery codes
+func (rrc *ReadRecoveryCodesController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	remaining, err := rrc.mfaService.CountRecoveryCodes(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to fetch recovery codes", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"remaining": remaining,
+	}, nil)
+}
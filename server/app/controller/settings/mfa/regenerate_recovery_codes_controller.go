@@ -0,0 +1,69 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegenerateRecoveryCodesController replaces the user's recovery codes
+type RegenerateRecoveryCodesController struct {
+	mfaService  *service.MFAService
+	userService *service.UserService
+}
+
+// NewRegenerateRecoveryCodesController initializes a new RegenerateRecoveryCodesController
+func NewRegenerateRecoveryCodesController(mfaService *service.MFAService, userService *service.UserService) *RegenerateRecoveryCodesController {
+	return &RegenerateRecoveryCodesController{
+		mfaService:  mfaService,
+		userService: userService,
+	}
+}
+
+// Handle confirms the password and issues a new set of recovery codes
+func (rrc *RegenerateRecoveryCodesController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	// Parse the password from the request body
+	var request struct {
+		Password string `json:"password" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	// Validate the password
+	isValid, err := rrc.userService.ValidatePassword(ctx.Request.Context(), userID.(string), request.Password)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to validate password", nil, nil)
+		return
+	}
+	if !isValid {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid password", nil, nil)
+		return
+	}
+
+	recoveryCodes, err := rrc.mfaService.RegenerateRecoveryCodes(ctx.Request.Context(), userID.(string))
+	if err != nil {
+		if err.Error() == "MFA is not enabled" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to regenerate recovery codes", nil, nil)
+		return
+	}
+
+	// Respond with the new codes, they are only shown once
+	helper.FormatResponse(ctx, "success", http.StatusOK, "recovery codes regenerated successfully", gin.H{
+		"recovery_codes": recoveryCodes,
+	}, nil)
+}
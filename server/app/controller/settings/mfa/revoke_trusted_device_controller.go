@@ -0,0 +1,39 @@
+package mfa
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeTrustedDeviceController forgets one of the user's trusted devices
+type RevokeTrustedDeviceController struct {
+	mfaService *service.MFAService
+}
+
+// NewRevokeTrustedDeviceController initializes a new RevokeTrustedDeviceController
+func NewRevokeTrustedDeviceController(mfaService *service.MFAService) *RevokeTrustedDeviceController {
+	return &RevokeTrustedDeviceController{
+		mfaService: mfaService,
+	}
+}
+
+// Handle revokes the trusted device named in the path
+func (rtc *RevokeTrustedDeviceController) Handle(ctx *gin.Context) {
+	// Extract user ID from the context
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "user ID not found in context", nil, nil)
+		return
+	}
+
+	if err := rtc.mfaService.RevokeTrustedDevice(ctx.Request.Context(), userID.(string), ctx.Param("deviceID")); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "trusted device revoked successfully", nil, nil)
+}
@@ -0,0 +1,53 @@
+package sso
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BeginController starts a sign-in with an organization's identity provider
+type BeginController struct {
+	ssoService *service.SSOService
+}
+
+// NewBeginController initializes a new BeginController
+func NewBeginController(ssoService *service.SSOService) *BeginController {
+	return &BeginController{
+		ssoService: ssoService,
+	}
+}
+
+// Handle returns the IdP authorization URL for the organization or the email's domain
+func (bc *BeginController) Handle(ctx *gin.Context) {
+	var request struct {
+		OrganizationID string `json:"organization_id"`
+		Email          string `json:"email" binding:"omitempty,email"`
+	}
+
+	// Validate the payload
+	if err := ctx.ShouldBindJSON(&request); err != nil || (request.OrganizationID == "" && request.Email == "") {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	authorizationURL, err := bc.ssoService.Begin(ctx.Request.Context(), request.OrganizationID, request.Email)
+	if err != nil {
+		switch err.Error() {
+		case "SSO is not available":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "identity provider unavailable":
+			helper.FormatResponse(ctx, "error", http.StatusBadGateway, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to start SSO", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "SSO started successfully", gin.H{
+		"authorization_url": authorizationURL,
+	}, nil)
+}
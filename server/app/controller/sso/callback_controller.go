@@ -0,0 +1,98 @@
+package sso
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CallbackController completes a sign-in with an organization's identity provider and issues tokens
+type CallbackController struct {
+	ssoService  *service.SSOService
+	userService *service.UserService
+	mfaService  *service.MFAService
+}
+
+// NewCallbackController initializes a new CallbackController
+func NewCallbackController(ssoService *service.SSOService, userService *service.UserService, mfaService *service.MFAService) *CallbackController {
+	return &CallbackController{
+		ssoService:  ssoService,
+		userService: userService,
+		mfaService:  mfaService,
+	}
+}
+
+// Handle redeems the authorization code the frontend received from the IdP, returning a login code instead of tokens
+// when the user still has to pass MFA
+func (cc *CallbackController) Handle(ctx *gin.Context) {
+	var request struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+
+	// Validate the payload
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	user, accessToken, refreshToken, err := cc.ssoService.Complete(ctx.Request.Context(), request.Code, request.State)
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired state", "authorization code exchange failed", "identity provider returned no ID token", "invalid ID token", "email is not verified by the identity provider":
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+		case "SSO is not available":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case "email domain is not allowed", "email domain is not verified by the organization", "account is not a member of the organization", "account locked due to multiple failed login attempts":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		case "identity provider unavailable":
+			helper.FormatResponse(ctx, "error", http.StatusBadGateway, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to complete SSO", nil, nil)
+		}
+		return
+	}
+
+	// Get user settings
+	_, settings, err := cc.userService.GetUserSettingsByEmail(ctx, user.Email)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user settings", nil, nil)
+		return
+	}
+
+	// The IdP replaces the password only, a registered second factor is still required
+	mfaMethods, err := cc.mfaService.LoginMethods(ctx.Request.Context(), user.ID.Hex(), settings.IsMFAEnabled)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user settings", nil, nil)
+		return
+	}
+
+	// A remembered browser skips the second factor
+	isDeviceTrusted := len(mfaMethods) > 0 && cc.mfaService.IsDeviceTrusted(ctx.Request.Context(), user.ID.Hex(), helper.TrustedDeviceToken(ctx))
+	if len(mfaMethods) > 0 && !isDeviceTrusted {
+		// Generate login code
+		loginCode, err := cc.mfaService.GenerateLoginCode(ctx.Request.Context(), user.ID.Hex())
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate login code", nil, nil)
+			return
+		}
+
+		helper.FormatResponse(ctx, "success", http.StatusOK, "login code requested successfully", gin.H{
+			"is_mfa_enabled": true,
+			"mfa_methods":    mfaMethods,
+			"login_code":     loginCode,
+		}, nil)
+		return
+	}
+
+	// Respond with tokens
+	helper.FormatResponse(ctx, "success", http.StatusOK, "SSO completed successfully", gin.H{
+		"is_mfa_enabled":    false,
+		"is_device_trusted": isDeviceTrusted,
+		"access_token":      accessToken,
+		"refresh_token":     refreshToken,
+	}, nil)
+}
@@ -0,0 +1,36 @@
+package tokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BeginPasskeyController starts a passwordless sign-in
+type BeginPasskeyController struct {
+	tokenService *service.TokenService
+}
+
+// NewBeginPasskeyController initializes a new BeginPasskeyController
+func NewBeginPasskeyController(tokenService *service.TokenService) *BeginPasskeyController {
+	return &BeginPasskeyController{
+		tokenService: tokenService,
+	}
+}
+
+// Handle returns a session ID and the request options for a discoverable passkey
+func (bc *BeginPasskeyController) Handle(ctx *gin.Context) {
+	sessionID, options, err := bc.tokenService.BeginPasskeyAuthentication(ctx.Request.Context())
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create passkey challenge", nil, nil)
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"session_id": sessionID,
+		"options":    options,
+	}, nil)
+}
@@ -0,0 +1,57 @@
+package tokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FinishPasskeyController completes a passwordless sign-in and issues tokens
+type FinishPasskeyController struct {
+	tokenService *service.TokenService
+}
+
+// NewFinishPasskeyController initializes a new FinishPasskeyController
+func NewFinishPasskeyController(tokenService *service.TokenService) *FinishPasskeyController {
+	return &FinishPasskeyController{
+		tokenService: tokenService,
+	}
+}
+
+// Handle verifies the passkey assertion and issues tokens
+func (fc *FinishPasskeyController) Handle(ctx *gin.Context) {
+	var request struct {
+		SessionID  string                   `json:"session_id" binding:"required"`
+		Credential dto.WebAuthnAssertionDTO `json:"credential" binding:"required"`
+	}
+
+	// Validate the payload
+	if err := fc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	accessToken, refreshToken, err := fc.tokenService.AuthenticatePasskey(ctx.Request.Context(), request.SessionID, &request.Credential)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+		return
+	}
+
+	// Respond with tokens
+	helper.FormatResponse(ctx, "success", http.StatusOK, "passkey verified successfully", gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (fc *FinishPasskeyController) validatePayload(ctx *gin.Context, request any) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package tokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MagicLinkController emails passwordless sign-in links
+type MagicLinkController struct {
+	tokenService *service.TokenService
+	emailService *service.EmailService
+}
+
+// NewMagicLinkController initializes a new MagicLinkController
+func NewMagicLinkController(tokenService *service.TokenService, emailService *service.EmailService) *MagicLinkController {
+	return &MagicLinkController{
+		tokenService: tokenService,
+		emailService: emailService,
+	}
+}
+
+// Handle emails a sign-in link, the response is the same whether or not the account exists
+func (mc *MagicLinkController) Handle(ctx *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	// Validate the payload
+	if err := mc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	token, err := mc.tokenService.GenerateMagicLink(ctx.Request.Context(), request.Email)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	if token != "" {
+		if err := mc.emailService.SendMagicLink(request.Email, token, config.LoadMagicLinkConfig().Expiry); err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to send magic link", nil, nil)
+			return
+		}
+		// Only a delivered link holds back the next request
+		if err := mc.tokenService.MarkMagicLinkSent(ctx.Request.Context(), token); err != nil {
+			logger.Log.Error("failed to mark magic link sent", logger.Error(err))
+		}
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, "if the account exists, a sign-in link has been sent", nil, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (mc *MagicLinkController) validatePayload(ctx *gin.Context, request any) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
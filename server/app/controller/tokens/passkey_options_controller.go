@@ -0,0 +1,58 @@
+package tokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyOptionsController issues the passkey challenge for a pending MFA login
+type PasskeyOptionsController struct {
+	mfaService *service.MFAService
+}
+
+// NewPasskeyOptionsController initializes a new PasskeyOptionsController
+func NewPasskeyOptionsController(mfaService *service.MFAService) *PasskeyOptionsController {
+	return &PasskeyOptionsController{
+		mfaService: mfaService,
+	}
+}
+
+// Handle returns the request options to pass to navigator.credentials.get
+func (pc *PasskeyOptionsController) Handle(ctx *gin.Context) {
+	var request struct {
+		LoginCode string `json:"login_code" binding:"required"`
+	}
+
+	// Validate the payload
+	if err := pc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	options, err := pc.mfaService.BeginPasskeyLogin(ctx.Request.Context(), request.LoginCode)
+	if err != nil {
+		switch err.Error() {
+		case "invalid login code", "no passkeys registered":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to create passkey challenge", nil, nil)
+		}
+		return
+	}
+
+	helper.FormatResponse(ctx, "success", http.StatusOK, nil, gin.H{
+		"options": options,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (pc *PasskeyOptionsController) validatePayload(ctx *gin.Context, request any) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
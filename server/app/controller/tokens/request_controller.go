@@ -38,8 +38,12 @@ func (rc *RequestController) Handle(ctx *gin.Context) {
 	}
 
 	// Authenticate user and generate tokens
-	accessToken, refreshToken, err := rc.tokenService.AuthenticateUser(ctx.Request.Context(), request.Email, request.Password)
+	accessToken, refreshToken, err := rc.tokenService.AuthenticateUser(ctx.Request.Context(), request.Email, request.Password, ctx.ClientIP())
 	if err != nil {
+		if err.Error() == "too many login attempts, try again later" {
+			helper.FormatResponse(ctx, "error", http.StatusTooManyRequests, err.Error(), nil, nil)
+			return
+		}
 		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
 		return
 	}
@@ -51,25 +55,36 @@ func (rc *RequestController) Handle(ctx *gin.Context) {
 		return
 	}
 
-	// Check if MFA is enabled
-	if settings.IsMFAEnabled {
+	// Check if MFA is enabled, a registered passkey counts as a second factor
+	mfaMethods, err := rc.mfaService.LoginMethods(ctx.Request.Context(), user.ID.Hex(), settings.IsMFAEnabled)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user settings", nil, nil)
+		return
+	}
+
+	// A remembered browser skips the second factor
+	isDeviceTrusted := len(mfaMethods) > 0 && rc.mfaService.IsDeviceTrusted(ctx.Request.Context(), user.ID.Hex(), helper.TrustedDeviceToken(ctx))
+	if len(mfaMethods) > 0 && !isDeviceTrusted {
 		// Generate login code
 		loginCode, err := rc.mfaService.GenerateLoginCode(ctx.Request.Context(), user.ID.Hex())
 		if err != nil {
 			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate login code", nil, nil)
+			return
 		}
 
 		// Respond with boolean flag indicating MFA is enabled
 		helper.FormatResponse(ctx, "success", http.StatusOK, "login code requested successfully", gin.H{
 			"is_mfa_enabled": true,
+			"mfa_methods":    mfaMethods,
 			"login_code":     loginCode,
 		}, nil)
 	} else {
 		// Respond with tokens
 		helper.FormatResponse(ctx, "success", http.StatusOK, "token requested successfully", gin.H{
-			"is_mfa_enabled": false,
-			"access_token":   accessToken,
-			"refresh_token":  refreshToken,
+			"is_mfa_enabled":    false,
+			"is_device_trusted": isDeviceTrusted,
+			"access_token":      accessToken,
+			"refresh_token":     refreshToken,
 		}, nil)
 	}
 
@@ -0,0 +1,100 @@
+package tokens
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyMagicLinkController exchanges a magic link for tokens
+type VerifyMagicLinkController struct {
+	tokenService *service.TokenService
+	userService  *service.UserService
+	mfaService   *service.MFAService
+}
+
+// NewVerifyMagicLinkController initializes a new VerifyMagicLinkController
+func NewVerifyMagicLinkController(tokenService *service.TokenService, userService *service.UserService, mfaService *service.MFAService) *VerifyMagicLinkController {
+	return &VerifyMagicLinkController{
+		tokenService: tokenService,
+		userService:  userService,
+		mfaService:   mfaService,
+	}
+}
+
+// Handle consumes the link and returns tokens, or a login code when the user still has to pass MFA
+func (vc *VerifyMagicLinkController) Handle(ctx *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	// Validate the payload
+	if err := vc.validatePayload(ctx, &request); err != nil {
+		return
+	}
+
+	user, err := vc.tokenService.AuthenticateMagicLink(ctx.Request.Context(), request.Token)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+		return
+	}
+
+	// Get user settings
+	_, settings, err := vc.userService.GetUserSettingsByEmail(ctx, user.Email)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user settings", nil, nil)
+		return
+	}
+
+	// The link replaces the password only, a registered second factor is still required
+	mfaMethods, err := vc.mfaService.LoginMethods(ctx.Request.Context(), user.ID.Hex(), settings.IsMFAEnabled)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user settings", nil, nil)
+		return
+	}
+
+	// A remembered browser skips the second factor
+	isDeviceTrusted := len(mfaMethods) > 0 && vc.mfaService.IsDeviceTrusted(ctx.Request.Context(), user.ID.Hex(), helper.TrustedDeviceToken(ctx))
+	if len(mfaMethods) > 0 && !isDeviceTrusted {
+		// Generate login code
+		loginCode, err := vc.mfaService.GenerateLoginCode(ctx.Request.Context(), user.ID.Hex())
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to generate login code", nil, nil)
+			return
+		}
+
+		helper.FormatResponse(ctx, "success", http.StatusOK, "login code requested successfully", gin.H{
+			"is_mfa_enabled": true,
+			"mfa_methods":    mfaMethods,
+			"login_code":     loginCode,
+		}, nil)
+		return
+	}
+
+	// Tokens are only minted once no second factor is outstanding, so the link alone cannot end the current session
+	accessToken, refreshToken, err := vc.tokenService.IssueMagicLinkTokens(ctx.Request.Context(), user)
+	if err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	// Respond with tokens
+	helper.FormatResponse(ctx, "success", http.StatusOK, "token requested successfully", gin.H{
+		"is_mfa_enabled":    false,
+		"is_device_trusted": isDeviceTrusted,
+		"access_token":      accessToken,
+		"refresh_token":     refreshToken,
+	}, nil)
+}
+
+// validatePayload validates the incoming request payload
+func (vc *VerifyMagicLinkController) validatePayload(ctx *gin.Context, request any) error {
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return err
+	}
+	return nil
+}
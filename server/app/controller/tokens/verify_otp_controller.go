@@ -3,8 +3,11 @@ package tokens
 import (
 	"net/http"
 
+	"bongaquino/server/app/dto"
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/service"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,8 +29,11 @@ func NewVerifyOTPController(tokenService *service.TokenService, mfaService *serv
 // Handle verifies the OTP and issues tokens
 func (vc *VerifyOTPController) Handle(ctx *gin.Context) {
 	var request struct {
-		LoginCode string `json:"login_code" binding:"required"`
-		OTP       string `json:"otp" binding:"required"`
+		LoginCode      string                    `json:"login_code" binding:"required"`
+		OTP            string                    `json:"otp" binding:"required_without_all=RecoveryCode Passkey"`
+		RecoveryCode   string                    `json:"recovery_code"`
+		Passkey        *dto.WebAuthnAssertionDTO `json:"passkey"`
+		RememberDevice bool                      `json:"remember_device"`
 	}
 
 	// Validate the payload
@@ -35,18 +41,39 @@ func (vc *VerifyOTPController) Handle(ctx *gin.Context) {
 		return
 	}
 
-	// Verify the OTP
-	accessToken, refreshToken, err := vc.tokenService.AuthenticateLoginCode(ctx.Request.Context(), request.LoginCode, request.OTP)
+	// Verify the OTP, a recovery code or passkey may stand in for it
+	user, accessToken, refreshToken, err := vc.tokenService.AuthenticateLoginCode(ctx.Request.Context(), request.LoginCode, service.LoginFactor{
+		OTP:          request.OTP,
+		RecoveryCode: request.RecoveryCode,
+		Passkey:      request.Passkey,
+	})
 	if err != nil {
 		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid login code or OTP", nil, nil)
 		return
 	}
 
-	// Respond with tokens
-	helper.FormatResponse(ctx, "success", http.StatusOK, "OTP verified successfully", gin.H{
+	response := gin.H{
 		"access_token":  accessToken,
 		"refresh_token": refreshToken,
-	}, nil)
+	}
+
+	// Remember the browser so its next logins skip the second factor, failing to do so does not fail the login
+	if request.RememberDevice {
+		mfaConfig := config.LoadMFAConfig()
+
+		token, device, err := vc.mfaService.TrustDevice(ctx.Request.Context(), user.ID.Hex())
+		if err != nil {
+			logger.Log.Error("failed to trust device", logger.Error(err))
+		} else {
+			ctx.SetSameSite(http.SameSiteStrictMode)
+			ctx.SetCookie(mfaConfig.TrustedDeviceCookie, token, int(mfaConfig.TrustedDeviceExpiry.Seconds()), "/tokens", "", true, true)
+			response["trusted_device_token"] = token
+			response["trusted_device_expires_at"] = device.ExpiresAt
+		}
+	}
+
+	// Respond with tokens
+	helper.FormatResponse(ctx, "success", http.StatusOK, "OTP verified successfully", response, nil)
 }
 
 // validatePayload validates the incoming request payload
@@ -0,0 +1,47 @@
+package users
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelEmailChangeController handles the cancel link sent to the current address when an email change is requested
+type CancelEmailChangeController struct {
+	emailChangeService *service.EmailChangeService
+}
+
+// NewCancelEmailChangeController initializes a new CancelEmailChangeController
+func NewCancelEmailChangeController(emailChangeService *service.EmailChangeService) *CancelEmailChangeController {
+	return &CancelEmailChangeController{
+		emailChangeService: emailChangeService,
+	}
+}
+
+// Handle drops the pending email change tied to the emailed token
+func (cecc *CancelEmailChangeController) Handle(ctx *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	// Validate the request payload
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	if err := cecc.emailChangeService.CancelEmailChange(ctx.Request.Context(), request.Token); err != nil {
+		if err.Error() == "invalid or expired cancel token" {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to cancel email change", nil, nil)
+		return
+	}
+
+	// Respond with success
+	helper.FormatResponse(ctx, "success", http.StatusOK, "email change cancelled", nil, nil)
+}
@@ -68,7 +68,7 @@ func (rc *RegisterController) Handle(ctx *gin.Context) {
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := rc.tokenService.AuthenticateUser(ctx.Request.Context(), user.Email, request.Password)
+	accessToken, refreshToken, err := rc.tokenService.AuthenticateUser(ctx.Request.Context(), user.Email, request.Password, ctx.ClientIP())
 	if err != nil {
 		helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
 		return
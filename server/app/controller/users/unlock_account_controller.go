@@ -0,0 +1,50 @@
+package users
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UnlockAccountController handles the unlock link sent when an account is locked
+type UnlockAccountController struct {
+	lockoutService *service.LockoutService
+}
+
+// NewUnlockAccountController initializes a new UnlockAccountController
+func NewUnlockAccountController(lockoutService *service.LockoutService) *UnlockAccountController {
+	return &UnlockAccountController{
+		lockoutService: lockoutService,
+	}
+}
+
+// Handle lifts the lockout tied to the emailed token
+func (uac *UnlockAccountController) Handle(ctx *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	// Validate the request payload
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid request body", nil, nil)
+		return
+	}
+
+	if err := uac.lockoutService.UnlockWithToken(ctx.Request.Context(), request.Token); err != nil {
+		switch err.Error() {
+		case "invalid or expired unlock token":
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, err.Error(), nil, nil)
+		case "account locked by an administrator":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to unlock account", nil, nil)
+		}
+		return
+	}
+
+	// Respond with success
+	helper.FormatResponse(ctx, "success", http.StatusOK, "account unlocked successfully", nil, nil)
+}
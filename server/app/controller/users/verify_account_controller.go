@@ -6,6 +6,7 @@ import (
 
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/service"
+	"bongaquino/server/core/logger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +14,14 @@ import (
 // VerifyAccountController handles verifying user accounts
 type VerifyAccountController struct {
 	userService *service.UserService
+	orgService  *service.OrganizationService
 }
 
 // NewVerifyAccountController initializes a new VerifyAccountController
-func NewVerifyAccountController(userService *service.UserService) *VerifyAccountController {
+func NewVerifyAccountController(userService *service.UserService, orgService *service.OrganizationService) *VerifyAccountController {
 	return &VerifyAccountController{
 		userService: userService,
+		orgService:  orgService,
 	}
 }
 
@@ -47,6 +50,11 @@ func (vac *VerifyAccountController) Handle(ctx *gin.Context) {
 		return
 	}
 
+	// Join the organization that verified the user's email domain, a failure here does not undo the verification
+	if err := vac.orgService.HandleDomainJoin(ctx.Request.Context(), userID.(string)); err != nil {
+		logger.Log.Error("failed to join organization by email domain", logger.Error(err), logger.String("user_id", userID.(string)))
+	}
+
 	// Respond with success
 	helper.FormatResponse(ctx, "success", http.StatusOK, "account verified successfully", nil, nil)
 }
@@ -0,0 +1,11 @@
+package dto
+
+type AcceptInvitationDTO struct {
+	Token           string  `json:"token" binding:"required"`
+	FirstName       string  `json:"first_name"`
+	MiddleName      *string `json:"middle_name"`
+	LastName        string  `json:"last_name"`
+	Suffix          *string `json:"suffix"`
+	Password        string  `json:"password"`
+	ConfirmPassword string  `json:"confirm_password"`
+}
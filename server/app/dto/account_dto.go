@@ -0,0 +1,9 @@
+package dto
+
+type CreateDataExportDTO struct {
+	IncludeFiles bool `json:"include_files"` // Also bundle the contents of every file
+}
+
+type DeleteAccountDTO struct {
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type ChangeEmailDTO struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ConfirmEmailChangeDTO struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type CreatePersonalAccessTokenDTO struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "bongaquino/server/app/model"
+
+type CreatePolicyDTO struct {
+	Name       string                  `json:"name" binding:"required"`
+	Statements []model.PolicyStatement `json:"statements"`
+}
@@ -1,8 +1,21 @@
 package dto
 
 type GenerateServiceAccountDTO struct {
-	UserID       *string `json:"user_id"`
-	Name         string  `json:"name" binding:"required"`
-	ClientID     *string `json:"client_id"`
-	ClientSecret *string `json:"client_secret"`
+	UserID          *string             `json:"user_id"`
+	OrganizationID  *string             `json:"organization_id"`
+	DriveID         string              `json:"drive_id"` // The team drive an organization account acts as
+	Name            string              `json:"name" binding:"required"`
+	PolicyID        string              `json:"policy_id"`
+	CredentialType  string              `json:"credential_type" binding:"omitempty,oneof=secret key"` // "key" issues a JSON key file instead of a client secret
+	DirectoryScopes []DirectoryScopeDTO `json:"directory_scopes" binding:"omitempty,dive"`
+	ClientID        *string             `json:"client_id"`
+	ClientSecret    *string             `json:"client_secret"`
+	KeyID           *string             `json:"-"`
+	PublicKey       *string             `json:"-"`
+}
+
+// DirectoryScopeDTO binds a service account to a directory subtree
+type DirectoryScopeDTO struct {
+	DirectoryID string `json:"directory_id" binding:"required"`
+	Access      string `json:"access" binding:"omitempty,oneof=read_write read_only write_only"` // Defaults to "read_write"
 }
@@ -0,0 +1,6 @@
+package dto
+
+type RotateServiceAccountSecretDTO struct {
+	ClientID         string `json:"client_id" binding:"required"`
+	GracePeriodHours *int   `json:"grace_period_hours" binding:"omitempty,min=0"` // How long the previous secret keeps working, defaults to a day
+}
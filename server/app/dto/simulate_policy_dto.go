@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type SimulatePolicyDTO struct {
+	Action      string     `json:"action" binding:"required"`
+	UserID      string     `json:"user_id"`
+	DirectoryID string     `json:"directory_id"`
+	FileID      string     `json:"file_id"`
+	SourceIP    string     `json:"source_ip"`
+	Time        *time.Time `json:"time"`
+	ObjectSize  int64      `json:"object_size"`
+	IsEncrypted bool       `json:"is_encrypted"`
+}
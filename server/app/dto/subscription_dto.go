@@ -0,0 +1,28 @@
+package dto
+
+import "bongaquino/server/app/model"
+
+type CreateSubscriptionPlanDTO struct {
+	Name                 string                         `json:"name" binding:"required"`
+	Description          string                         `json:"description"`
+	BytesLimit           int64                          `json:"bytes_limit" binding:"required,min=1"`
+	MaxFileSize          int64                          `json:"max_file_size" binding:"min=0"`           // 0 for no limit
+	MaxEncryptedFileSize int64                          `json:"max_encrypted_file_size" binding:"min=0"` // 0 for the file configuration default
+	MaxServiceAccounts   int64                          `json:"max_service_accounts" binding:"min=0"`    // 0 for no limit
+	RateLimits           map[string]model.PlanRateLimit `json:"rate_limits" binding:"omitempty,dive"`
+}
+
+type UpdateSubscriptionPlanDTO struct {
+	Name                 *string                         `json:"name" binding:"omitempty,min=1"`
+	Description          *string                         `json:"description"`
+	BytesLimit           *int64                          `json:"bytes_limit" binding:"omitempty,min=1"`
+	MaxFileSize          *int64                          `json:"max_file_size" binding:"omitempty,min=0"`
+	MaxEncryptedFileSize *int64                          `json:"max_encrypted_file_size" binding:"omitempty,min=0"`
+	MaxServiceAccounts   *int64                          `json:"max_service_accounts" binding:"omitempty,min=0"`
+	RateLimits           *map[string]model.PlanRateLimit `json:"rate_limits"`
+}
+
+type UpdateSubscriptionDTO struct {
+	PlanID string `json:"plan_id" binding:"required"`
+	Status string `json:"status" binding:"required,oneof=trial active past_due canceled"`
+}
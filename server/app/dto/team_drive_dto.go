@@ -0,0 +1,9 @@
+package dto
+
+type CreateTeamDriveDTO struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type UpdateTeamDriveDTO struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
@@ -3,3 +3,7 @@ package dto
 type UpdateLimitDTO struct {
 	BytesLimit int64 `json:"bytes_limit" binding:"required"`
 }
+
+type UpdateMemberLimitDTO struct {
+	BytesLimit int64 `json:"bytes_limit" binding:"min=0"` // 0 removes the member's cap
+}
@@ -0,0 +1,10 @@
+package dto
+
+type UpdateOrganizationSSODTO struct {
+	Issuer         string   `json:"issuer" binding:"required,url"`
+	ClientID       string   `json:"client_id" binding:"required"`
+	ClientSecret   string   `json:"client_secret"` // Keeps the stored secret when empty
+	AllowedDomains []string `json:"allowed_domains"`
+	DefaultRoleID  string   `json:"default_role_id"`
+	IsEnabled      bool     `json:"is_enabled"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "bongaquino/server/app/model"
+
+type UpdatePolicyDTO struct {
+	Name       *string                  `json:"name"`
+	Statements *[]model.PolicyStatement `json:"statements"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// WebAuthnAssertionDTO is an authentication response in the browser's PublicKeyCredential JSON encoding,
+// binary fields are base64url
+type WebAuthnAssertionDTO struct {
+	ID       string `json:"id" binding:"required"`
+	Type     string `json:"type" binding:"required,eq=public-key"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+		AuthenticatorData string `json:"authenticatorData" binding:"required"`
+		Signature         string `json:"signature" binding:"required"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response" binding:"required"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// WebAuthnAttestationDTO is a registration response in the browser's PublicKeyCredential JSON encoding,
+// binary fields are base64url
+type WebAuthnAttestationDTO struct {
+	ID       string `json:"id" binding:"required"`
+	Type     string `json:"type" binding:"required,eq=public-key"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+		AttestationObject string   `json:"attestationObject" binding:"required"`
+		Transports        []string `json:"transports"`
+	} `json:"response" binding:"required"`
+}
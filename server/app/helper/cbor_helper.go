@@ -0,0 +1,193 @@
+package helper
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Limits that keep hostile input from exhausting the stack or memory, WebAuthn structures stay far below them
+const (
+	maxCBORDepth  = 16
+	maxCBORLength = 64 * 1024
+	maxCBORItems  = 1024
+)
+
+// DecodeCBOR decodes one CBOR data item and returns it with the bytes that follow it.
+// Integers decode to int64, byte strings to []byte, text to string, arrays to []any and maps to map[any]any.
+// Only the definite-length encodings used by WebAuthn authenticators are supported, and maps must not repeat keys.
+func DecodeCBOR(data []byte) (any, []byte, error) {
+	if len(data) > maxCBORLength {
+		return nil, nil, errors.New("cbor: data too long")
+	}
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("cbor: nesting too deep")
+	}
+	if len(data) == 0 {
+		return nil, nil, errors.New("cbor: unexpected end of data")
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// Simple values and floats carry their payload in the additional information
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22, 23:
+			return nil, data, nil
+		case 25:
+			if len(data) < 2 {
+				return nil, nil, errors.New("cbor: unexpected end of data")
+			}
+			return float64(halfToFloat(binary.BigEndian.Uint16(data))), data[2:], nil
+		case 26:
+			if len(data) < 4 {
+				return nil, nil, errors.New("cbor: unexpected end of data")
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+		case 27:
+			if len(data) < 8 {
+				return nil, nil, errors.New("cbor: unexpected end of data")
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+		default:
+			return nil, nil, errors.New("cbor: unsupported simple value")
+		}
+	}
+
+	length, data, err := decodeCBORArgument(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if length > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(length), data, nil
+	case 1:
+		if length > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(length), data, nil
+	case 2, 3:
+		if uint64(len(data)) < length {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		value := data[:length]
+		if major == 3 {
+			return string(value), data[length:], nil
+		}
+		return append([]byte(nil), value...), data[length:], nil
+	case 4:
+		if length > maxCBORItems {
+			return nil, nil, errors.New("cbor: too many items")
+		}
+		// Every item takes at least one byte, which caps the allocation
+		if uint64(len(data)) < length {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		items := make([]any, 0, length)
+		for i := uint64(0); i < length; i++ {
+			var item any
+			item, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data, nil
+	case 5:
+		if length > maxCBORItems {
+			return nil, nil, errors.New("cbor: too many items")
+		}
+		if uint64(len(data)) < length*2 {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		items := make(map[any]any, length)
+		for i := uint64(0); i < length; i++ {
+			var key, value any
+			key, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errors.New("cbor: unsupported map key")
+			}
+			if _, exists := items[key]; exists {
+				return nil, nil, errors.New("cbor: duplicate map key")
+			}
+			value, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items[key] = value
+		}
+		return items, data, nil
+	case 6:
+		// Tags only annotate the item that follows
+		return decodeCBORItem(data, depth+1)
+	}
+
+	return nil, nil, errors.New("cbor: unsupported major type")
+}
+
+// decodeCBORArgument reads the length or value that follows the initial byte
+func decodeCBORArgument(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		if len(data) < 1 {
+			return 0, nil, errors.New("cbor: unexpected end of data")
+		}
+		return uint64(data[0]), data[1:], nil
+	case info == 25:
+		if len(data) < 2 {
+			return 0, nil, errors.New("cbor: unexpected end of data")
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26:
+		if len(data) < 4 {
+			return 0, nil, errors.New("cbor: unexpected end of data")
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27:
+		if len(data) < 8 {
+			return 0, nil, errors.New("cbor: unexpected end of data")
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+	return 0, nil, errors.New("cbor: indefinite or reserved lengths are not supported")
+}
+
+// halfToFloat converts an IEEE 754 half-precision value
+func halfToFloat(bits uint16) float32 {
+	sign := uint32(bits>>15) << 31
+	exponent := uint32(bits>>10) & 0x1f
+	mantissa := uint32(bits) & 0x3ff
+
+	switch exponent {
+	case 0:
+		value := float32(mantissa) / (1 << 24)
+		if sign != 0 {
+			return -value
+		}
+		return value
+	case 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | mantissa<<13)
+	}
+	return math.Float32frombits(sign | (exponent+112)<<23 | mantissa<<13)
+}
@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected any
+	}{
+		{"small unsigned", []byte{0x17}, int64(23)},
+		{"one byte unsigned", []byte{0x18, 0x64}, int64(100)},
+		{"eight byte unsigned", []byte{0x1b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00}, int64(256)},
+		{"negative", []byte{0x38, 0x63}, int64(-100)},
+		{"byte string", []byte{0x42, 0x01, 0x02}, []byte{0x01, 0x02}},
+		{"text string", []byte{0x63, 'f', 'm', 't'}, "fmt"},
+		{"array", []byte{0x82, 0x01, 0x20}, []any{int64(1), int64(-1)}},
+		{"map", []byte{0xa2, 0x01, 0x02, 0x61, 'a', 0xf5}, map[any]any{int64(1): int64(2), "a": true}},
+		{"tagged item", []byte{0xc2, 0x41, 0x01}, []byte{0x01}},
+		{"half float", []byte{0xf9, 0x3c, 0x00}, float64(1)},
+		{"null", []byte{0xf6}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, rest, err := DecodeCBOR(append(bytes.Clone(test.data), 0xff))
+			if err != nil {
+				t.Fatalf("expected the item to decode, got %v", err)
+			}
+			if !reflect.DeepEqual(value, test.expected) {
+				t.Fatalf("expected %#v, got %#v", test.expected, value)
+			}
+			if !bytes.Equal(rest, []byte{0xff}) {
+				t.Fatalf("expected the following byte to be returned, got %x", rest)
+			}
+		})
+	}
+}
+
+func TestDecodeCBORRejectsMalformedInput(t *testing.T) {
+	nested := append(bytes.Repeat([]byte{0x81}, maxCBORDepth+1), 0x00)
+	taggedLoop := append(bytes.Repeat([]byte{0xc6}, maxCBORDepth+1), 0x00)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated argument", []byte{0x19, 0x01}},
+		{"truncated byte string", []byte{0x43, 0x01, 0x02}},
+		{"truncated text string", []byte{0x62, 'a'}},
+		{"truncated array", []byte{0x83, 0x01, 0x02}},
+		{"truncated map", []byte{0xa1, 0x01}},
+		{"truncated float", []byte{0xfb, 0x00, 0x00}},
+		{"huge byte string length", []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"huge array length", []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"huge map length", []byte{0xbb, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"too many items", append([]byte{0x99, 0x04, 0x01}, make([]byte, maxCBORItems+1)...)},
+		{"integer overflow", []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"indefinite length", []byte{0x9f, 0x01, 0xff}},
+		{"reserved length", []byte{0x1c}},
+		{"unsupported simple value", []byte{0xf0}},
+		{"unsupported map key", []byte{0xa1, 0x80, 0x01}},
+		{"duplicate map key", []byte{0xa2, 0x01, 0x00, 0x01, 0x00}},
+		{"nesting too deep", nested},
+		{"tags nested too deep", taggedLoop},
+		{"data too long", append([]byte{0x5a, 0x00, 0x01, 0x00, 0x01}, make([]byte, maxCBORLength)...)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := DecodeCBOR(test.data); err == nil {
+				t.Fatal("expected the data to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package helper
+
+import "context"
+
+type clientInfoKey struct{}
+
+type clientInfo struct {
+	ip        string
+	userAgent string
+}
+
+// WithClientInfo stores the caller's IP and user agent in the request context for services that audit requests
+func WithClientInfo(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, clientInfo{ip: ip, userAgent: userAgent})
+}
+
+// ClientInfo returns the IP and user agent stored by WithClientInfo
+func ClientInfo(ctx context.Context) (ip string, userAgent string) {
+	info, _ := ctx.Value(clientInfoKey{}).(clientInfo)
+	return info.ip, info.userAgent
+}
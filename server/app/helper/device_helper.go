@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"bongaquino/server/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrustedDeviceToken reads the trusted device token from its header, falling back to the cookie set by verify-otp
+func TrustedDeviceToken(ctx *gin.Context) string {
+	mfaConfig := config.LoadMFAConfig()
+
+	if token := ctx.GetHeader(mfaConfig.TrustedDeviceHeader); token != "" {
+		return token
+	}
+	token, _ := ctx.Cookie(mfaConfig.TrustedDeviceCookie)
+	return token
+}
@@ -1,6 +1,13 @@
 package helper
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"bongaquino/server/config"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -18,3 +25,25 @@ func CheckHash(data, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(data))
 	return err == nil
 }
+
+// HashToken hashes a high-entropy token with SHA-256 so it can be looked up by its hash
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignToken appends an HMAC of the token keyed with the app key, so forged tokens are rejected before any lookup
+func SignToken(token string) string {
+	mac := hmac.New(sha256.New, []byte(config.LoadAppConfig().AppKey))
+	mac.Write([]byte(token))
+	return token + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignedToken returns the token inside a value produced by SignToken
+func VerifySignedToken(signed string) (string, bool) {
+	token, _, found := strings.Cut(signed, ".")
+	if !found || token == "" {
+		return "", false
+	}
+	return token, hmac.Equal([]byte(SignToken(token)), []byte(signed))
+}
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"slices"
 )
@@ -36,3 +37,10 @@ func ValidatePassword(password string) (bool, error) {
 func Contains(slice []string, item string) bool {
 	return slices.Contains(slice, item)
 }
+
+// IsPublicIP reports whether an address is reachable on the public internet, rejecting loopback, private, link-local
+// and unspecified addresses so the server cannot be pointed at internal services
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified() && !ip.IsMulticast()
+}
@@ -0,0 +1,227 @@
+package helper
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Authenticator data flags
+const (
+	WebAuthnFlagUserPresent  byte = 0x01
+	WebAuthnFlagUserVerified byte = 0x04
+	WebAuthnFlagAttestedData byte = 0x40
+	WebAuthnFlagExtensions   byte = 0x80
+)
+
+// maxCredentialIDLength is the longest credential ID the WebAuthn specification allows
+const maxCredentialIDLength = 1023
+
+// COSE algorithm identifiers accepted for passkeys
+const (
+	COSEAlgES256 int64 = -7
+	COSEAlgEdDSA int64 = -8
+	COSEAlgRS256 int64 = -257
+)
+
+// WebAuthnClientData is the JSON the browser signs over during a ceremony
+type WebAuthnClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// WebAuthnAuthenticatorData is the binary authenticator data of a ceremony
+type WebAuthnAuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte // COSE_Key, only present on registration
+}
+
+// DecodeBase64URL decodes base64url with or without padding, as browsers emit it
+func DecodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// EncodeBase64URL encodes bytes as unpadded base64url
+func EncodeBase64URL(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// ParseWebAuthnClientData decodes clientDataJSON
+func ParseWebAuthnClientData(raw []byte) (*WebAuthnClientData, error) {
+	var clientData WebAuthnClientData
+	if err := json.Unmarshal(raw, &clientData); err != nil {
+		return nil, errors.New("malformed client data")
+	}
+	return &clientData, nil
+}
+
+// ParseWebAuthnAuthenticatorData decodes authenticator data, including the attested credential when present.
+// Extensions must be a CBOR map announced by their flag, and nothing may follow them.
+func ParseWebAuthnAuthenticatorData(raw []byte) (*WebAuthnAuthenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("authenticator data too short")
+	}
+
+	authData := &WebAuthnAuthenticatorData{
+		RPIDHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[37:]
+
+	if authData.Flags&WebAuthnFlagAttestedData != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data too short")
+		}
+		authData.AAGUID = rest[:16]
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		if idLength == 0 || idLength > maxCredentialIDLength {
+			return nil, errors.New("invalid credential ID length")
+		}
+		rest = rest[18:]
+		if len(rest) < idLength {
+			return nil, errors.New("attested credential data too short")
+		}
+		authData.CredentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		// The COSE key runs until the end of its CBOR item, extensions may follow
+		key, remaining, err := DecodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("malformed credential public key")
+		}
+		if _, ok := key.(map[any]any); !ok {
+			return nil, errors.New("malformed credential public key")
+		}
+		authData.PublicKey = rest[:len(rest)-len(remaining)]
+		rest = remaining
+	}
+
+	if authData.Flags&WebAuthnFlagExtensions != 0 {
+		extensions, remaining, err := DecodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("malformed extensions")
+		}
+		if _, ok := extensions.(map[any]any); !ok {
+			return nil, errors.New("malformed extensions")
+		}
+		rest = remaining
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("unexpected trailing authenticator data")
+	}
+
+	return authData, nil
+}
+
+// ParseWebAuthnAttestationObject returns the authenticator data and statement format of an attestation object
+func ParseWebAuthnAttestationObject(raw []byte) ([]byte, string, error) {
+	decoded, remaining, err := DecodeCBOR(raw)
+	if err != nil || len(remaining) != 0 {
+		return nil, "", errors.New("malformed attestation object")
+	}
+	object, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, "", errors.New("malformed attestation object")
+	}
+	authData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, "", errors.New("attestation object has no authenticator data")
+	}
+	format, _ := object["fmt"].(string)
+	return authData, format, nil
+}
+
+// ParseCOSEKey converts a COSE_Key into a Go public key and its algorithm
+func ParseCOSEKey(raw []byte) (crypto.PublicKey, int64, error) {
+	decoded, remaining, err := DecodeCBOR(raw)
+	if err != nil || len(remaining) != 0 {
+		return nil, 0, errors.New("malformed public key")
+	}
+	key, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, 0, errors.New("malformed public key")
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+
+	switch {
+	case kty == 2 && alg == COSEAlgES256:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, errors.New("unsupported EC2 key")
+		}
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, 0, errors.New("invalid EC2 key")
+		}
+		return publicKey, alg, nil
+	case kty == 3 && alg == COSEAlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, 0, errors.New("unsupported RSA key")
+		}
+		exponent := new(big.Int).SetBytes(e).Int64()
+		if exponent < 3 || exponent%2 == 0 {
+			return nil, 0, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent)}, alg, nil
+	case kty == 1 && alg == COSEAlgEdDSA:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, errors.New("unsupported OKP key")
+		}
+		return ed25519.PublicKey(x), alg, nil
+	}
+
+	return nil, 0, errors.New("unsupported public key algorithm")
+}
+
+// VerifyWebAuthnSignature checks an assertion signature over authenticator data and the client data hash
+func VerifyWebAuthnSignature(coseKey, authData, clientDataJSON, signature []byte) error {
+	publicKey, alg, err := ParseCOSEKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+
+	switch alg {
+	case COSEAlgES256:
+		if !ecdsa.VerifyASN1(publicKey.(*ecdsa.PublicKey), digest[:], signature) {
+			return errors.New("invalid signature")
+		}
+	case COSEAlgRS256:
+		if rsa.VerifyPKCS1v15(publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) != nil {
+			return errors.New("invalid signature")
+		}
+	case COSEAlgEdDSA:
+		if !ed25519.Verify(publicKey.(ed25519.PublicKey), signed, signature) {
+			return errors.New("invalid signature")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// Real authenticator output, a macOS Touch ID registration and sign-in and a packed attestation from a security key
+const (
+	noneAttestationObject   = "o2NmbXRkbm9uZWdhdHRTdG10oGhhdXRoRGF0YVjEdKbqkhPJnC90siSSsyDPQCYqlMGpUKA5fyklC2CEHvBBAAAAAAAAAAAAAAAAAAAAAAAAAAAAQOia8u9zP1lVg6Fy7BsUbAVVR6T1g6TctRExl1BLyS3UwJ-RMOpwxlOlvIjt2ZHCxKq_ggcL8dKdlgMc7fEYsEGlAQIDJiABIVgg--n_QvZithDycYmnifk6vMHiwBP6kugn2PlsnvkrcSgiWCBAlBYm2B-rMtQlp5MxGTLoGDHoktxb0p364Hy2BH9U2Q"
+	packedAttestationObject = "o2NmbXRmcGFja2VkZ2F0dFN0bXSiY2FsZyZjc2lnWEcwRQIhAJgdgw5x8JzE4JfR6x1RBO8eCHNE8eW_L1VTV03zpyL5AiBv8eUzua3XSS3bPYC7m8eXzJhcaRyeGe7UcuqIrDSvC2hhdXRoRGF0YVi3SZYN5YgOjGh0NBcPZHZgW4_krrmihjLHmVzzuoMdl2NFXJE5zK3OAAI1vMYKZIsLJfHwVQMAMwDserxRhiE7ZcI4ahRbwJCZgc0s38BNXQWtX1Ufy7auS9-RSUTXYJF3vOL9_tExFTQkqaUBAgMmIAEhWCCm9OYidwiIoH9SwVQqUAnH8Gj5ZJ2_qr8gjbg41q4M1SJYIA07XKpHSgS1mE7R1MjotVIQqyHi9WAxGwHQsCteVK2V"
+	assertionAuthData       = "dKbqkhPJnC90siSSsyDPQCYqlMGpUKA5fyklC2CEHvBFXJJiGa3OAAI1vMYKZIsLJfHwVQMANwCOw-atj9C0vhWpfWU-whzNjeQS21Lpxfdk_G-omAtffWztpGoErlNOfuXWRqm9Uj9ANJck1p6lAQIDJiABIVggKAhfsdHcBIc0KPgAcRyAIK_-Vi-nCXHkRHPNaCMBZ-4iWCBxB8fGYQSBONi9uvq0gv95dGWlhJrBwCsj_a4LJQKVHQ"
+	assertionClientData     = "eyJjaGFsbGVuZ2UiOiJFNFBUY0lIX0hmWDFwQzZTaWdrMVNDOU5BbGdlenROMDQzOXZpOHpfYzlrIiwibmV3X2tleXNfbWF5X2JlX2FkZGVkX2hlcmUiOiJkbyBub3QgY29tcGFyZSBjbGllbnREYXRhSlNPTiBhZ2FpbnN0IGEgdGVtcGxhdGUuIFNlZSBodHRwczovL2dvby5nbC95YWJQZXgiLCJvcmlnaW4iOiJodHRwczovL3dlYmF1dGhuLmlvIiwidHlwZSI6IndlYmF1dGhuLmdldCJ9"
+	assertionSignature      = "MEUCIBtIVOQxzFYdyWQyxaLR0tik1TnuPhGVhXVSNgFwLmN5AiEAnxXdCq0UeAVGWxOaFcjBZ_mEZoXqNboY5IkQDdlWZYc"
+)
+
+func mustDecodeBase64URL(t *testing.T, value string) []byte {
+	decoded, err := DecodeBase64URL(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestParseWebAuthnAttestationObject(t *testing.T) {
+	tests := []struct {
+		name           string
+		object         string
+		format         string
+		credentialSize int
+	}{
+		{"none", noneAttestationObject, "none", 64},
+		{"packed", packedAttestationObject, "packed", 51},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, format, err := ParseWebAuthnAttestationObject(mustDecodeBase64URL(t, test.object))
+			if err != nil {
+				t.Fatalf("expected the attestation object to parse, got %v", err)
+			}
+			if format != test.format {
+				t.Fatalf("expected format %s, got %s", test.format, format)
+			}
+
+			authData, err := ParseWebAuthnAuthenticatorData(raw)
+			if err != nil {
+				t.Fatalf("expected the authenticator data to parse, got %v", err)
+			}
+			if len(authData.CredentialID) != test.credentialSize {
+				t.Fatalf("expected a %d byte credential ID, got %d", test.credentialSize, len(authData.CredentialID))
+			}
+			if _, alg, err := ParseCOSEKey(authData.PublicKey); err != nil || alg != COSEAlgES256 {
+				t.Fatalf("expected an ES256 key, got %d, %v", alg, err)
+			}
+		})
+	}
+}
+
+func TestVerifyWebAuthnSignature(t *testing.T) {
+	rawAuthData := mustDecodeBase64URL(t, assertionAuthData)
+	clientData := mustDecodeBase64URL(t, assertionClientData)
+	signature := mustDecodeBase64URL(t, assertionSignature)
+
+	// This authenticator repeats the credential key in its sign-in data
+	authData, err := ParseWebAuthnAuthenticatorData(rawAuthData)
+	if err != nil {
+		t.Fatalf("expected the authenticator data to parse, got %v", err)
+	}
+	if authData.Flags&WebAuthnFlagUserPresent == 0 || authData.SignCount == 0 {
+		t.Fatalf("expected a user present assertion with a sign count, got flags %x count %d", authData.Flags, authData.SignCount)
+	}
+
+	if err := VerifyWebAuthnSignature(authData.PublicKey, rawAuthData, clientData, signature); err != nil {
+		t.Fatalf("expected the signature to verify, got %v", err)
+	}
+
+	tampered := append([]byte(nil), rawAuthData...)
+	binary.BigEndian.PutUint32(tampered[33:37], authData.SignCount+1)
+	if err := VerifyWebAuthnSignature(authData.PublicKey, tampered, clientData, signature); err == nil {
+		t.Fatal("expected a tampered sign count to fail verification")
+	}
+	if err := VerifyWebAuthnSignature(authData.PublicKey, rawAuthData, append(clientData, ' '), signature); err == nil {
+		t.Fatal("expected tampered client data to fail verification")
+	}
+}
+
+func TestParseWebAuthnAuthenticatorDataRejectsMalformedInput(t *testing.T) {
+	object := mustDecodeBase64URL(t, noneAttestationObject)
+	valid, _, err := ParseWebAuthnAttestationObject(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withFlags := func(flags byte, tail ...byte) []byte {
+		raw := append(append([]byte(nil), valid...), tail...)
+		raw[32] = flags
+		return raw
+	}
+	withIDLength := func(length uint16) []byte {
+		raw := append([]byte(nil), valid...)
+		binary.BigEndian.PutUint16(raw[53:55], length)
+		return raw
+	}
+
+	tests := []struct {
+		name     string
+		authData []byte
+	}{
+		{"header only", valid[:36]},
+		{"attested data cut short", valid[:40]},
+		{"credential ID cut short", valid[:60]},
+		{"public key cut short", valid[:len(valid)-1]},
+		{"empty credential ID", withIDLength(0)},
+		{"credential ID past the end", withIDLength(0xffff)},
+		{"trailing bytes", withFlags(valid[32], 0x00)},
+		{"extensions flag without extensions", withFlags(valid[32] | WebAuthnFlagExtensions)},
+		{"extensions that are not a map", withFlags(valid[32]|WebAuthnFlagExtensions, 0x01)},
+		{"extensions without their flag", withFlags(valid[32], 0xa0)},
+		{"attested data flag without attested data", valid[:37]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseWebAuthnAuthenticatorData(test.authData); err == nil {
+				t.Fatal("expected the authenticator data to be rejected")
+			}
+		})
+	}
+
+	t.Run("extensions announced by their flag", func(t *testing.T) {
+		authData, err := ParseWebAuthnAuthenticatorData(withFlags(valid[32]|WebAuthnFlagExtensions, 0xa1, 0x63, 'c', 'r', 'p', 0xf5))
+		if err != nil {
+			t.Fatalf("expected the authenticator data to parse, got %v", err)
+		}
+		if _, _, err := ParseCOSEKey(authData.PublicKey); err != nil {
+			t.Fatalf("expected the key to stop before the extensions, got %v", err)
+		}
+	})
+}
+
+func TestParseWebAuthnAttestationObjectRejectsTruncatedInput(t *testing.T) {
+	object := mustDecodeBase64URL(t, noneAttestationObject)
+
+	for i := 0; i < len(object); i++ {
+		if _, _, err := ParseWebAuthnAttestationObject(object[:i]); err == nil {
+			t.Fatalf("expected the object truncated to %d bytes to be rejected", i)
+		}
+	}
+	if _, _, err := ParseWebAuthnAttestationObject(append(bytes.Clone(object), 0x00)); err == nil {
+		t.Fatal("expected trailing bytes to be rejected")
+	}
+	if _, _, err := ParseWebAuthnAttestationObject([]byte{0x82, 0x01, 0x02}); err == nil {
+		t.Fatal("expected an array to be rejected")
+	}
+}
+
+func TestParseCOSEKeyRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{"empty", nil},
+		{"not a map", []byte{0x01}},
+		{"unsupported algorithm", []byte{0xa2, 0x01, 0x02, 0x03, 0x38, 0x22}},
+		{"EC2 key without coordinates", []byte{0xa3, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01}},
+		{"EC2 point off the curve", append(append([]byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}, make([]byte, 32)...), append([]byte{0x22, 0x58, 0x20}, bytes.Repeat([]byte{0x01}, 32)...)...)},
+		{"OKP key of the wrong size", []byte{0xa4, 0x01, 0x01, 0x03, 0x27, 0x20, 0x06, 0x21, 0x41, 0x00}},
+		{"trailing bytes", append([]byte{0xa2, 0x01, 0x02, 0x03, 0x26}, 0x00)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := ParseCOSEKey(test.key); err == nil {
+				t.Fatal("expected the key to be rejected")
+			}
+		})
+	}
+}
@@ -2,22 +2,57 @@ package middleware
 
 import (
 	"net/http"
-	"time"
+	"strings"
 
 	"bongaquino/server/app/helper"
-	"bongaquino/server/app/repository"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/service"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type APIMiddleware struct {
 	Handle gin.HandlerFunc
 }
 
-func NewAPIMiddleware(svcAccRepo *repository.ServiceAccountRepository) *APIMiddleware {
+func NewAPIMiddleware(serviceAccountService *service.ServiceAccountService, jwtProvider *provider.JWTProvider) *APIMiddleware {
 	return &APIMiddleware{
 		Handle: func(ctx *gin.Context) {
+			// Prefer a bearer token issued by /oauth/token over raw credentials
+			if authHeader := ctx.GetHeader("Authorization"); authHeader != "" {
+				tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+				if tokenString == authHeader {
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid authorization header", nil, nil)
+					ctx.Abort()
+					return
+				}
+
+				// Validate the client token
+				claims, err := jwtProvider.ValidateClientToken(tokenString)
+				if err != nil {
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid or expired access token", nil, nil)
+					ctx.Abort()
+					return
+				}
+
+				// Reject tokens of service accounts revoked after issuance
+				if jwtProvider.IsClientTokenRevoked(claims) {
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid or expired access token", nil, nil)
+					ctx.Abort()
+					return
+				}
+
+				// Set the client ID, user ID, policy ID and granted scopes in the context
+				ctx.Set("clientID", *claims.ClientId)
+				ctx.Set("userID", claims.Sub)
+				ctx.Set("policyID", *claims.PolicyId)
+				ctx.Set("scopes", claims.Scopes)
+
+				// Continue to the next middleware
+				ctx.Next()
+				return
+			}
+
 			// Retrieve ClientID from the request header
 			clientID := ctx.GetHeader("Client-ID")
 			if clientID == "" {
@@ -34,34 +69,17 @@ func NewAPIMiddleware(svcAccRepo *repository.ServiceAccountRepository) *APIMiddl
 				return
 			}
 
-			// Read the ClientID and ClientSecret using ServiceAccountRepository
-			serviceAccount, err := svcAccRepo.ReadByClientID(ctx.Request.Context(), clientID)
+			// Check the ClientSecret against the account's unexpired secrets, this also records the last use
+			serviceAccount, err := serviceAccountService.Authenticate(ctx.Request.Context(), clientID, clientSecret)
 			if err != nil {
-				helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to validate credentials", nil, nil)
-				ctx.Abort()
-				return
-			}
-			if serviceAccount == nil {
-				helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid credentials", nil, nil)
-				ctx.Abort()
-				return
-			}
-
-			// Check if the ClientSecret is valid
-			if !helper.CheckHash(clientSecret, serviceAccount.ClientSecret) {
-				helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid credentials", nil, nil)
-				ctx.Abort()
-				return
-			}
-
-			// Update service account last used timestamp
-			serviceAccount.LastUsedAt = time.Now()
-
-			// Update the service account in the repository
-			if err := svcAccRepo.UpdateByClientID(ctx.Request.Context(), clientID, bson.M{
-				"last_used_at": serviceAccount.LastUsedAt,
-			}); err != nil {
-				helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to update service account", nil, nil)
+				switch err.Error() {
+				case "invalid client credentials":
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid credentials", nil, nil)
+				case "service account is disabled":
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+				default:
+					helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+				}
 				ctx.Abort()
 				return
 			}
@@ -72,6 +90,9 @@ func NewAPIMiddleware(svcAccRepo *repository.ServiceAccountRepository) *APIMiddl
 			// Set the user ID in the context
 			ctx.Set("userID", serviceAccount.UserID.Hex())
 
+			// Set the policy ID in the context
+			ctx.Set("policyID", serviceAccount.PolicyID.Hex())
+
 			// Continue to the next middleware
 			ctx.Next()
 		},
@@ -6,17 +6,20 @@ import (
 
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/provider"
+	"bongaquino/server/app/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthnMiddleware struct {
 	Handle gin.HandlerFunc
+	// HandleWithPAT also accepts personal access tokens, for routes whose permissions map to token scopes
+	HandleWithPAT gin.HandlerFunc
 }
 
-func NewAuthnMiddleware(jwtService *provider.JWTProvider) *AuthnMiddleware {
-	return &AuthnMiddleware{
-		Handle: func(ctx *gin.Context) {
+func NewAuthnMiddleware(jwtService *provider.JWTProvider, patService *service.PersonalAccessTokenService) *AuthnMiddleware {
+	handle := func(allowPAT bool) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
 			// Get the Authorization header
 			authHeader := ctx.GetHeader("Authorization")
 			if authHeader == "" {
@@ -33,6 +36,30 @@ func NewAuthnMiddleware(jwtService *provider.JWTProvider) *AuthnMiddleware {
 				return
 			}
 
+			// Personal access tokens carry their own scopes, checked by the authz middleware
+			if patService.IsPersonalAccessToken(tokenString) {
+				if !allowPAT {
+					helper.FormatResponse(ctx, "error", http.StatusForbidden, "personal access tokens are not accepted on this route", nil, nil)
+					ctx.Abort()
+					return
+				}
+
+				pat, err := patService.Authenticate(ctx.Request.Context(), tokenString)
+				if err != nil {
+					helper.FormatResponse(ctx, "error", http.StatusUnauthorized, err.Error(), nil, nil)
+					ctx.Abort()
+					return
+				}
+
+				// Set the user ID and granted scopes in the context
+				ctx.Set("userID", pat.UserID.Hex())
+				ctx.Set("scopes", pat.Scopes)
+
+				// Continue to the next middleware
+				ctx.Next()
+				return
+			}
+
 			// Validate the token
 			claims, err := jwtService.ValidateToken(tokenString)
 			if err != nil {
@@ -41,11 +68,23 @@ func NewAuthnMiddleware(jwtService *provider.JWTProvider) *AuthnMiddleware {
 				return
 			}
 
+			// Service account tokens are only valid on the clients API
+			if claims.Scope == "client" {
+				helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "invalid or expired access token", nil, nil)
+				ctx.Abort()
+				return
+			}
+
 			// Set the user ID in the context
 			ctx.Set("userID", claims.Sub)
 
 			// Continue to the next middleware
 			ctx.Next()
-		},
+		}
+	}
+
+	return &AuthnMiddleware{
+		Handle:        handle(false),
+		HandleWithPAT: handle(true),
 	}
 }
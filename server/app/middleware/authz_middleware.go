@@ -6,6 +6,7 @@ import (
 
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/app/service"
 
 	"slices"
 
@@ -15,12 +16,14 @@ import (
 type AuthzMiddleware struct {
 	userRoleRepository *repository.UserRoleRepository
 	roleRepository     *repository.RoleRepository
+	permissionService  *service.PermissionService
 }
 
-func NewAuthzMiddleware(userRoleRepository *repository.UserRoleRepository, roleRepository *repository.RoleRepository) *AuthzMiddleware {
+func NewAuthzMiddleware(userRoleRepository *repository.UserRoleRepository, roleRepository *repository.RoleRepository, permissionService *service.PermissionService) *AuthzMiddleware {
 	return &AuthzMiddleware{
 		userRoleRepository: userRoleRepository,
 		roleRepository:     roleRepository,
+		permissionService:  permissionService,
 	}
 }
 
@@ -64,6 +67,58 @@ func (m *AuthzMiddleware) Handle(requiredRoles []string) gin.HandlerFunc {
 	}
 }
 
+// RequirePermission rejects users whose system roles do not grant the permission. Inside a team drive the
+// caller's organization role decides instead. Personal access tokens must also have a scope covering it.
+func (m *AuthzMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Retrieve userID from the context (assumes it's set by a previous middleware)
+		userID, exists := ctx.Get("userID")
+		if !exists {
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// The drive middleware swaps userID for the drive and keeps the caller as actorID
+		orgID := ctx.GetString("organizationID")
+		if orgID != "" {
+			userID = ctx.GetString("actorID")
+		}
+
+		// Scopes are only present when authenticating with a personal access token
+		if value, exists := ctx.Get("scopes"); exists {
+			scopes, _ := value.([]string)
+			if !service.ScopesAllow(scopes, permission) {
+				helper.FormatResponse(ctx, "error", http.StatusForbidden, "token scopes do not allow "+permission, nil, nil)
+				ctx.Abort()
+				return
+			}
+		}
+
+		var allowed bool
+		var err error
+		if orgID != "" {
+			allowed, err = m.permissionService.HasOrganizationPermission(ctx.Request.Context(), userID.(string), orgID, permission)
+		} else {
+			allowed, err = m.permissionService.HasPermission(ctx.Request.Context(), userID.(string), permission)
+		}
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve user permissions", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		if !allowed {
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, "user does not have the "+permission+" permission", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Continue to the next middleware
+		ctx.Next()
+	}
+}
+
 // getUserRoles fetches roles for a given userID from the database
 func (m *AuthzMiddleware) getUserRoles(ctx context.Context, userID string) ([]string, error) {
 	// Fetch user roles from the UserRoleRepository
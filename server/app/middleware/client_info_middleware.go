@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"bongaquino/server/app/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientInfoMiddleware struct {
+	Handle gin.HandlerFunc
+}
+
+// NewClientInfoMiddleware makes the caller's IP and user agent available to services through the request context
+func NewClientInfoMiddleware() *ClientInfoMiddleware {
+	return &ClientInfoMiddleware{
+		Handle: func(ctx *gin.Context) {
+			ctx.Request = ctx.Request.WithContext(helper.WithClientInfo(ctx.Request.Context(), ctx.ClientIP(), ctx.Request.UserAgent()))
+
+			// Continue to the next middleware
+			ctx.Next()
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DriveMiddleware struct {
+	teamDriveService *service.TeamDriveService
+}
+
+func NewDriveMiddleware(teamDriveService *service.TeamDriveService) *DriveMiddleware {
+	return &DriveMiddleware{
+		teamDriveService: teamDriveService,
+	}
+}
+
+// Handle runs the request inside the team drive named by the drive_id query parameter.
+// The directory and file handlers scope everything by userID, so it is swapped for the drive ID
+// and the caller is kept as actorID. Requests without drive_id pass through untouched.
+func (m *DriveMiddleware) Handle(access string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		driveID := ctx.Query("drive_id")
+		if driveID == "" {
+			ctx.Next()
+			return
+		}
+
+		// Retrieve userID from the context (assumes it's set by a previous middleware)
+		userID, exists := ctx.Get("userID")
+		if !exists {
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		drive, err := m.teamDriveService.CheckDriveAccess(ctx.Request.Context(), driveID, userID.(string), access)
+		if err != nil {
+			switch err.Error() {
+			case "team drive not found":
+				helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+			case "organization role does not allow this in the team drive":
+				helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+			default:
+				helper.FormatResponse(ctx, "error", http.StatusInternalServerError, err.Error(), nil, nil)
+			}
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("actorID", userID)
+		ctx.Set("driveID", drive.ID.Hex())
+		ctx.Set("organizationID", drive.OrganizationID.Hex())
+		ctx.Set("userID", drive.ID.Hex())
+
+		// Continue to the next middleware
+		ctx.Next()
+	}
+}
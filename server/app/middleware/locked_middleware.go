@@ -41,7 +41,7 @@ func NewLockedMiddleware(userRepo *repository.UserRepository) *LockedMiddleware
 				return
 			}
 
-			if user != nil && user.IsLocked {
+			if user != nil && user.IsLockActive() {
 				helper.FormatResponse(ctx, "error", http.StatusForbidden, "account locked due to multiple failed login attempts", nil, nil)
 				ctx.Abort()
 				return
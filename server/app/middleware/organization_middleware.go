@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrganizationMiddleware struct {
+	orgUserRoleRepository *repository.OrganizationUserRoleRepository
+	roleRepository        *repository.RoleRepository
+}
+
+func NewOrganizationMiddleware(orgUserRoleRepository *repository.OrganizationUserRoleRepository, roleRepository *repository.RoleRepository) *OrganizationMiddleware {
+	return &OrganizationMiddleware{
+		orgUserRoleRepository: orgUserRoleRepository,
+		roleRepository:        roleRepository,
+	}
+}
+
+// Handle rejects users who are not members of the :orgID organization with one of the required roles
+func (m *OrganizationMiddleware) Handle(requiredRoles []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Retrieve userID from the context (assumes it's set by a previous middleware)
+		userID, exists := ctx.Get("userID")
+		if !exists {
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "userID not found in context", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		orgID := ctx.Param("orgID")
+		if _, err := primitive.ObjectIDFromHex(orgID); err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusBadRequest, "invalid organization ID", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Fetch the caller's membership in the organization
+		member, err := m.orgUserRoleRepository.ReadByUserIDOrganizationID(ctx.Request.Context(), userID.(string), orgID)
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve organization membership", nil, nil)
+			ctx.Abort()
+			return
+		}
+		if member == nil {
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, "user is not a member of the organization", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		role, err := m.roleRepository.Read(ctx.Request.Context(), member.RoleID.Hex())
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to retrieve organization role", nil, nil)
+			ctx.Abort()
+			return
+		}
+		if role == nil || !slices.Contains(requiredRoles, role.Name) {
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, "user does not have the required organization role", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Set the organization role in the context for downstream handlers
+		ctx.Set("orgRole", role.Name)
+
+		// Continue to the next middleware
+		ctx.Next()
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyMiddleware struct {
+	policyService         *service.PolicyService
+	serviceAccountService *service.ServiceAccountService
+}
+
+func NewPolicyMiddleware(policyService *service.PolicyService, serviceAccountService *service.ServiceAccountService) *PolicyMiddleware {
+	return &PolicyMiddleware{
+		policyService:         policyService,
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// directoryScopeAccess is the kind of access each permission needs within a directory scope, anything else needs "manage"
+var directoryScopeAccess = map[string]string{
+	"directory:read": "read",
+	"file:read":      "read",
+	"file:download":  "read",
+	"directory:add":  "write",
+	"file:upload":    "write",
+}
+
+// Handle rejects service account requests whose policy does not grant the permission
+func (m *PolicyMiddleware) Handle(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Retrieve policyID from the context (assumes it's set by the API middleware)
+		policyID, exists := ctx.Get("policyID")
+		if !exists {
+			helper.FormatResponse(ctx, "error", http.StatusUnauthorized, "policyID not found in context", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Scopes are only present when authenticating with a bearer token
+		var scopes []string
+		if value, exists := ctx.Get("scopes"); exists {
+			scopes, _ = value.([]string)
+		}
+
+		// Describe the request so statement resources and conditions can be matched
+		request := &service.PolicyRequest{
+			Action:      permission,
+			DirectoryID: ctx.Param("directoryID"),
+			FileID:      ctx.Param("fileID"),
+			SourceIP:    ctx.ClientIP(),
+			Time:        time.Now(),
+		}
+		if userID, exists := ctx.Get("userID"); exists {
+			request.UserID = userID.(string)
+		}
+		if permission == "directory:add" {
+			request.DirectoryID = m.peekDirectoryID(ctx)
+		}
+		if permission == "file:upload" {
+			request.DirectoryID = ctx.PostForm("directory_id")
+			request.IsEncrypted = ctx.PostForm("passphrase") != ""
+			if fileHeader, err := ctx.FormFile("file"); err == nil {
+				request.ObjectSize = fileHeader.Size
+			}
+		}
+
+		// Evaluate the policy
+		decision, err := m.policyService.Authorize(ctx.Request.Context(), policyID.(string), scopes, request)
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to evaluate policy", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		if !decision.Allowed {
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, "service account policy does not allow "+permission, nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Scoped service accounts only see their directory subtrees
+		if !m.checkDirectoryScope(ctx, permission, request) {
+			ctx.Abort()
+			return
+		}
+
+		// Continue to the next middleware
+		ctx.Next()
+	}
+}
+
+// checkDirectoryScope confines the request, and the destination of a move, to the service account's directory scopes
+func (m *PolicyMiddleware) checkDirectoryScope(ctx *gin.Context, permission string, request *service.PolicyRequest) bool {
+	access, exists := directoryScopeAccess[permission]
+	if !exists {
+		access = "manage"
+	}
+
+	targets := []*service.PolicyRequest{request}
+	if permission == "directory:edit" || permission == "file:edit" {
+		if destinationID := m.peekDirectoryID(ctx); destinationID != "" {
+			targets = append(targets, &service.PolicyRequest{UserID: request.UserID, DirectoryID: destinationID})
+		}
+	}
+
+	for i, target := range targets {
+		directoryPath, err := m.policyService.ResolveDirectoryPath(ctx.Request.Context(), target)
+		if err != nil {
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to check directory scope", nil, nil)
+			return false
+		}
+
+		// Moving something in needs the same access as creating it
+		if i > 0 {
+			access = "write"
+		}
+		err = m.serviceAccountService.CheckDirectoryScope(ctx.Request.Context(), ctx.GetString("clientID"), directoryPath, access)
+		if err == nil {
+			continue
+		}
+		switch {
+		case err.Error() == "directory not found" && target.FileID != "":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, "file not found", nil, nil)
+		case err.Error() == "directory not found":
+			helper.FormatResponse(ctx, "error", http.StatusNotFound, err.Error(), nil, nil)
+		case err.Error() == "operation not allowed by directory scope":
+			helper.FormatResponse(ctx, "error", http.StatusForbidden, err.Error(), nil, nil)
+		default:
+			helper.FormatResponse(ctx, "error", http.StatusInternalServerError, "failed to check directory scope", nil, nil)
+		}
+		return false
+	}
+	return true
+}
+
+// peekDirectoryID reads the parent directory from a JSON body and restores the body for the controller
+func (m *PolicyMiddleware) peekDirectoryID(ctx *gin.Context) string {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return ""
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		DirectoryID string `json:"directory_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.DirectoryID
+}
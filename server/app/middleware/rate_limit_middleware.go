@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/service"
+	"bongaquino/server/core/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitMiddleware struct {
+	rateLimitService *service.RateLimitService
+}
+
+func NewRateLimitMiddleware(rateLimitService *service.RateLimitService) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		rateLimitService: rateLimitService,
+	}
+}
+
+// Handle rejects requests over the named policy's limit with 429 and reports the limit in RateLimit-* headers
+func (m *RateLimitMiddleware) Handle(policyName string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// The plan is set by the subscription middleware, anonymous callers get the policy's own rule
+		plan, _ := ctx.Value("subscriptionPlan").(*model.SubscriptionPlan)
+		policy, rule, err := m.rateLimitService.Policy(policyName, plan)
+		if err != nil {
+			logger.Log.Error("rate limit policy error", logger.Error(err))
+			ctx.Next()
+			return
+		}
+		if policy == nil {
+			ctx.Next()
+			return
+		}
+
+		// Count the request against the caller, falling back to the IP when they are anonymous
+		key := "ip:" + ctx.ClientIP()
+		switch policy.KeyBy {
+		case "client":
+			if clientID := ctx.GetString("clientID"); clientID != "" {
+				key = "client:" + clientID
+			}
+		case "user":
+			if userID := ctx.GetString("userID"); userID != "" {
+				key = "user:" + userID
+			}
+		}
+
+		// Let requests through when Redis is unavailable rather than failing every route
+		result, err := m.rateLimitService.Hit(ctx.Request.Context(), policyName, key, *rule)
+		if err != nil {
+			logger.Log.Error("rate limit error", logger.Error(err))
+			ctx.Next()
+			return
+		}
+
+		// Report the most restrictive policy when several apply to the same route
+		resetSeconds := int64(math.Ceil(result.Reset.Seconds()))
+		current, err := strconv.ParseInt(ctx.Writer.Header().Get("RateLimit-Remaining"), 10, 64)
+		if err != nil || result.Remaining < current || !result.Allowed {
+			ctx.Header("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+			ctx.Header("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+			ctx.Header("RateLimit-Reset", strconv.FormatInt(resetSeconds, 10))
+			ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, int64(result.Window.Seconds())))
+		}
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.FormatInt(resetSeconds, 10))
+			helper.FormatResponse(ctx, "error", http.StatusTooManyRequests, "rate limit exceeded", nil, nil)
+			ctx.Abort()
+			return
+		}
+
+		// Continue to the next middleware
+		ctx.Next()
+	}
+}
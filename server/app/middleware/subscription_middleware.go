@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"bongaquino/server/app/service"
+	"bongaquino/server/core/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionMiddleware struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewSubscriptionMiddleware(subscriptionService *service.SubscriptionService) *SubscriptionMiddleware {
+	return &SubscriptionMiddleware{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// Handle puts the caller's subscription plan in the context as subscriptionPlan for the rate limits.
+// It runs before the drive middleware, so userID is still the caller.
+func (m *SubscriptionMiddleware) Handle(ctx *gin.Context) {
+	userID := ctx.GetString("userID")
+	if userID == "" {
+		ctx.Next()
+		return
+	}
+
+	// Callers keep the default rate limits rather than failing when the plan can't be resolved
+	plan, err := m.subscriptionService.ResolvePlan(ctx.Request.Context(), userID)
+	if err != nil {
+		logger.Log.Error("failed to resolve subscription plan", logger.Error(err))
+		ctx.Next()
+		return
+	}
+	ctx.Set("subscriptionPlan", plan)
+
+	// Continue to the next middleware
+	ctx.Next()
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"bongaquino/server/app/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageMiddleware struct {
+	usageService *service.UsageService
+}
+
+func NewUsageMiddleware(usageService *service.UsageService) *UsageMiddleware {
+	return &UsageMiddleware{
+		usageService: usageService,
+	}
+}
+
+// Handle counts the request as an API call of the caller for billing. It runs after the rate limits,
+// so rejected requests are not billed.
+func (m *UsageMiddleware) Handle(ctx *gin.Context) {
+	if userID := ctx.GetString("userID"); userID != "" {
+		m.usageService.RecordAPICall(ctx.Request.Context(), userID)
+	}
+
+	// Continue to the next middleware
+	ctx.Next()
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog records an account-level action, entries outlive the account they are about
+type AuditLog struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Action    string              `bson:"action" json:"action"` // e.g. "account_deletion_requested", "account_deleted", "data_export_requested"
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	ActorID   *primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Unset when the system acted
+	IPAddress string              `bson:"ip_address,omitempty" json:"ip_address,omitempty"`
+	UserAgent string              `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Details   bson.M              `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
+
+func (AuditLog) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+}
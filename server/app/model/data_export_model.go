@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataExport is an archive of a user's personal data, built in the background and downloaded through a signed link
+type DataExport struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID `bson:"user_id" json:"-"`
+	Status       string             `bson:"status" json:"status"` // "pending", "processing", "ready" or "failed"
+	IncludeFiles bool               `bson:"include_files" json:"include_files"`
+	Hash         string             `bson:"hash,omitempty" json:"-"` // IPFS hash of the archive
+	Size         int64              `bson:"size,omitempty" json:"size,omitempty"`
+	Error        string             `bson:"error,omitempty" json:"error,omitempty"`
+	ExpiresAt    *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CompletedAt  *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// IsDownloadable reports whether the archive is built and its link has not expired
+func (e *DataExport) IsDownloadable() bool {
+	return e.Status == "ready" && e.ExpiresAt != nil && time.Now().Before(*e.ExpiresAt)
+}
+
+func (DataExport) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		{{Key: "status", Value: 1}},
+	}
+}
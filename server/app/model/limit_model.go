@@ -7,18 +7,28 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Limit is a storage budget. A user's limit has UserID set; when OrganizationID is also set the user
+// draws from that organization's pool. The pool's own limit uses the organization ID as its UserID
+// so the unique user_id index still holds.
 type Limit struct {
-	ID             primitive.ObjectID  `bson:"_id,omitempty"`
-	UserID         primitive.ObjectID  `bson:"user_id"`
-	OrganizationID *primitive.ObjectID `bson:"organization_id"`
-	BytesLimit     int64               `bson:"bytes_limit"`
-	BytesUsage     int64               `bson:"bytes_usage"`
-	CreatedAt      time.Time           `bson:"created_at"`
-	UpdatedAt      time.Time           `bson:"updated_at"`
+	ID               primitive.ObjectID  `bson:"_id,omitempty"`
+	UserID           primitive.ObjectID  `bson:"user_id"`
+	OrganizationID   *primitive.ObjectID `bson:"organization_id"`
+	BytesLimit       int64               `bson:"bytes_limit"`
+	BytesUsage       int64               `bson:"bytes_usage"`
+	MemberBytesLimit int64               `bson:"member_bytes_limit,omitempty"` // Sub-cap of a pooled member, 0 for none
+	CreatedAt        time.Time           `bson:"created_at"`
+	UpdatedAt        time.Time           `bson:"updated_at"`
+}
+
+// IsPooled reports whether the user's uploads count against an organization pool
+func (l Limit) IsPooled() bool {
+	return l.OrganizationID != nil && l.UserID != *l.OrganizationID
 }
 
 func (Limit) GetIndexes() []bson.D {
 	return []bson.D{
 		{{Key: "user_id", Value: 1}},
+		{{Key: "organization_id", Value: 1}},
 	}
 }
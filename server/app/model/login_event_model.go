@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginEvent records one authentication attempt
+type LoginEvent struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID        *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Identifier    string              `bson:"identifier,omitempty" json:"identifier,omitempty"` // Email or client ID presented
+	Method        string              `bson:"method" json:"method"`                             // "password", "otp", "recovery_code", "passkey", "magic_link", "sso", "refresh" or "service_account"
+	Success       bool                `bson:"success" json:"success"`
+	FailureReason string              `bson:"failure_reason,omitempty" json:"failure_reason,omitempty"`
+	IPAddress     string              `bson:"ip_address" json:"ip_address"`
+	UserAgent     string              `bson:"user_agent" json:"user_agent"`
+	Fingerprint   string              `bson:"fingerprint" json:"-"` // Hash of the user agent and network
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+}
+
+func (LoginEvent) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		{{Key: "user_id", Value: 1}, {Key: "fingerprint", Value: 1}},
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MFARecoveryCode struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	CodeHash  string             `bson:"code_hash"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func (MFARecoveryCode) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "user_id", Value: 1}, {Key: "used_at", Value: 1}},
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrganizationInvitation struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	OrganizationID primitive.ObjectID `bson:"organization_id"`
+	Email          string             `bson:"email"`
+	RoleID         primitive.ObjectID `bson:"role_id"`
+	InvitedBy      primitive.ObjectID `bson:"invited_by"`
+	TokenHash      string             `bson:"token_hash"`
+	Status         string             `bson:"status"` // "pending", "accepted", "revoked", "expired"
+	ExpiresAt      time.Time          `bson:"expires_at"`
+	AcceptedAt     *time.Time         `bson:"accepted_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+}
+
+func (OrganizationInvitation) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "token_hash", Value: 1}},
+	}
+}
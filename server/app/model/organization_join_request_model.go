@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OrganizationJoinRequest struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty"`
+	OrganizationID primitive.ObjectID  `bson:"organization_id"`
+	UserID         primitive.ObjectID  `bson:"user_id"`
+	Email          string              `bson:"email"`
+	Status         string              `bson:"status"` // "pending", "approved" or "rejected"
+	ReviewedBy     *primitive.ObjectID `bson:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time          `bson:"reviewed_at,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at"`
+}
+
+func (OrganizationJoinRequest) GetIndexes() []bson.D {
+	return nil
+}
@@ -12,9 +12,13 @@ type Organization struct {
 	Domain               string             `bson:"domain"`
 	Contact              string             `bson:"contact"`
 	PolicyID             primitive.ObjectID `bson:"policy_id"`
-	SubscriptionPlanID   primitive.ObjectID `bson:"subscription_plan_id"`
+	SubscriptionPlanID   primitive.ObjectID `bson:"subscription_plan_id"` // Nil while members are on their own plans
 	SubscriptionStatusID primitive.ObjectID `bson:"subscription_status_id"`
 	ParentID             primitive.ObjectID `bson:"parent_id"`
+	DomainToken          string             `bson:"domain_token,omitempty"`
+	DomainVerifiedAt     *time.Time         `bson:"domain_verified_at,omitempty"`
+	AutoJoinMode         string             `bson:"auto_join_mode,omitempty"` // "disabled", "auto_join" or "request"
+	AutoJoinRoleID       primitive.ObjectID `bson:"auto_join_role_id,omitempty"`
 	CreatedAt            time.Time          `bson:"created_at"`
 	UpdatedAt            time.Time          `bson:"updated_at"`
 }
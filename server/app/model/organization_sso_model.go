@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrganizationSSO is an organization's OpenID Connect identity provider
+type OrganizationSSO struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	OrganizationID primitive.ObjectID `bson:"organization_id"`
+	Issuer         string             `bson:"issuer"`
+	ClientID       string             `bson:"client_id"`
+	ClientSecret   string             `bson:"client_secret"`   // Encrypted with the app key
+	AllowedDomains []string           `bson:"allowed_domains"` // Email domains the IdP may sign in, at least one
+	DefaultRoleID  primitive.ObjectID `bson:"default_role_id,omitempty"`
+	IsEnabled      bool               `bson:"is_enabled"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+}
+
+func (OrganizationSSO) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "organization_id", Value: 1}},
+	}
+}
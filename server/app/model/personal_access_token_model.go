@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PersonalAccessToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name       string             `bson:"name" json:"name"`
+	TokenHash  string             `bson:"token_hash" json:"-"`
+	Hint       string             `bson:"hint" json:"hint"` // Prefix and first characters, so users can tell tokens apart
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time         `bson:"expires_at,omitempty" json:"expires_at"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at"`
+	LastUsedIP string             `bson:"last_used_ip,omitempty" json:"last_used_ip"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// IsExpired reports whether the token had an expiry and it has passed
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+func (PersonalAccessToken) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "token_hash", Value: 1}},
+	}
+}
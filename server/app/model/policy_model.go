@@ -8,10 +8,34 @@ import (
 )
 
 type Policy struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Name      string             `bson:"name"`
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Name       string             `bson:"name"`
+	Statements []PolicyStatement  `bson:"statements,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+}
+
+// PolicyStatement allows or denies actions on resources while all of its conditions hold
+type PolicyStatement struct {
+	Effect     string           `bson:"effect" json:"effect"`       // "allow" or "deny"
+	Actions    []string         `bson:"actions" json:"actions"`     // "file:upload", "file:*" or "*"
+	Resources  []string         `bson:"resources" json:"resources"` // "*", "directory:<id>" (subtree) or "file:<id pattern>"
+	Conditions *PolicyCondition `bson:"conditions,omitempty" json:"conditions,omitempty"`
+}
+
+type PolicyCondition struct {
+	SourceCIDRs   []string          `bson:"source_cidrs,omitempty" json:"source_cidrs,omitempty"`
+	TimeOfDay     *PolicyTimeWindow `bson:"time_of_day,omitempty" json:"time_of_day,omitempty"`
+	NotBefore     *time.Time        `bson:"not_before,omitempty" json:"not_before,omitempty"`
+	NotAfter      *time.Time        `bson:"not_after,omitempty" json:"not_after,omitempty"`
+	MaxObjectSize *int64            `bson:"max_object_size,omitempty" json:"max_object_size,omitempty"` // In bytes
+	EncryptedOnly bool              `bson:"encrypted_only,omitempty" json:"encrypted_only,omitempty"`
+}
+
+type PolicyTimeWindow struct {
+	Start    string `bson:"start" json:"start"`                           // "HH:MM"
+	End      string `bson:"end" json:"end"`                               // "HH:MM", may wrap past midnight
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA name, defaults to UTC
 }
 
 func (Policy) GetIndexes() []bson.D {
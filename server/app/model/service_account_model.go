@@ -7,17 +7,66 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ServiceAccount acts as the user in UserID. Organization accounts act as one of the organization's
+// team drives instead, so they never carry a member's identity; CreatedBy records the administrator.
 type ServiceAccount struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty"`
-	UserID         primitive.ObjectID `bson:"user_id"`
-	OrganizationID primitive.ObjectID `bson:"organization_id"`
-	Name           string             `bson:"name"`
-	ClientID       string             `bson:"client_id"`
-	ClientSecret   string             `bson:"client_secret"`
-	PolicyID       primitive.ObjectID `bson:"policy_id"`
-	LastUsedAt     time.Time          `bson:"last_used_at"`
-	CreatedAt      time.Time          `bson:"created_at"`
-	UpdatedAt      time.Time          `bson:"updated_at"`
+	ID              primitive.ObjectID             `bson:"_id,omitempty"`
+	UserID          primitive.ObjectID             `bson:"user_id"`
+	OrganizationID  primitive.ObjectID             `bson:"organization_id"`
+	CreatedBy       *primitive.ObjectID            `bson:"created_by,omitempty"`
+	Name            string                         `bson:"name"`
+	ClientID        string                         `bson:"client_id"`
+	ClientSecret    string                         `bson:"client_secret,omitempty"` // Hash of the single secret issued before rotation was supported
+	Secrets         []ServiceAccountSecret         `bson:"secrets,omitempty"`
+	Keys            []ServiceAccountKey            `bson:"keys,omitempty"`
+	DirectoryScopes []ServiceAccountDirectoryScope `bson:"directory_scopes,omitempty"` // Confines the account to these subtrees when set
+	PolicyID        primitive.ObjectID             `bson:"policy_id"`
+	LastUsedAt      time.Time                      `bson:"last_used_at"`
+	DisabledAt      *time.Time                     `bson:"disabled_at,omitempty"`
+	DisabledReason  string                         `bson:"disabled_reason,omitempty"`
+	CreatedAt       time.Time                      `bson:"created_at"`
+	UpdatedAt       time.Time                      `bson:"updated_at"`
+}
+
+// ServiceAccountSecret is one of the client's secrets, each expiring on its own so secrets can be rotated without downtime
+type ServiceAccountSecret struct {
+	ID         string     `bson:"id"`
+	Hash       string     `bson:"hash" json:"-"`
+	ExpiresAt  time.Time  `bson:"expires_at"`
+	WarnedAt   *time.Time `bson:"warned_at,omitempty"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at"`
+}
+
+// ServiceAccountKey is the public half of a key pair whose private key was handed out in a JSON key file
+type ServiceAccountKey struct {
+	ID         string     `bson:"id"`
+	Algorithm  string     `bson:"algorithm"`
+	PublicKey  string     `bson:"public_key"`           // PEM encoded
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty"` // Only set while a rotated-out key is in its grace period
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at"`
+}
+
+// ServiceAccountDirectoryScope is a directory subtree the account is confined to
+type ServiceAccountDirectoryScope struct {
+	DirectoryID primitive.ObjectID `bson:"directory_id"`
+	Access      string             `bson:"access"` // "read_write", "read_only" or "write_only"
+}
+
+// UsesKeys reports whether the account authenticates with signed assertions instead of secrets
+func (s *ServiceAccount) UsesKeys() bool {
+	return len(s.Keys) > 0
+}
+
+// IsOrganizationAccount reports whether the account belongs to an organization rather than a user
+func (s *ServiceAccount) IsOrganizationAccount() bool {
+	return !s.OrganizationID.IsZero()
+}
+
+// IsDisabled reports whether the account was disabled, its credentials are then rejected
+func (s *ServiceAccount) IsDisabled() bool {
+	return s.DisabledAt != nil
 }
 
 func (ServiceAccount) GetIndexes() []bson.D {
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubscriptionPlan is a catalog entry of what a user or an organization may store and do
+type SubscriptionPlan struct {
+	ID                   primitive.ObjectID       `bson:"_id,omitempty" json:"id"`
+	Name                 string                   `bson:"name" json:"name"`
+	Description          string                   `bson:"description,omitempty" json:"description,omitempty"`
+	BytesLimit           int64                    `bson:"bytes_limit" json:"bytes_limit"`                         // A user's storage, or an organization's shared pool
+	MaxFileSize          int64                    `bson:"max_file_size" json:"max_file_size"`                     // 0 for no limit
+	MaxEncryptedFileSize int64                    `bson:"max_encrypted_file_size" json:"max_encrypted_file_size"` // 0 for the file configuration default, encrypted uploads are held in memory
+	MaxServiceAccounts   int64                    `bson:"max_service_accounts" json:"max_service_accounts"`       // 0 for no limit
+	RateLimits           map[string]PlanRateLimit `bson:"rate_limits,omitempty" json:"rate_limits,omitempty"`     // Keyed by rate limit policy name
+	CreatedAt            time.Time                `bson:"created_at" json:"created_at"`
+	UpdatedAt            time.Time                `bson:"updated_at" json:"updated_at"`
+}
+
+// PlanRateLimit replaces a rate limit policy's rule for callers on the plan
+type PlanRateLimit struct {
+	Limit         int64 `bson:"limit" json:"limit" binding:"min=1"`
+	WindowSeconds int64 `bson:"window_seconds" json:"window_seconds" binding:"min=1"`
+}
+
+func (SubscriptionPlan) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "name", Value: 1}},
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubscriptionStatus is a stage of a subscription's lifecycle, "trial", "active", "past_due" or "canceled"
+type SubscriptionStatus struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+func (SubscriptionStatus) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "name", Value: 1}},
+	}
+}
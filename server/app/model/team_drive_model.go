@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TeamDrive is storage owned by an organization. Its directories, files and limit use the drive ID
+// as their UserID, so they stay with the organization whoever uploaded them.
+type TeamDrive struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	OrganizationID primitive.ObjectID `bson:"organization_id"`
+	Name           string             `bson:"name"`
+	CreatedBy      primitive.ObjectID `bson:"created_by"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+}
+
+func (TeamDrive) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "organization_id", Value: 1}, {Key: "name", Value: 1}},
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TrustedDevice struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash  string             `bson:"token_hash" json:"-"`
+	IPAddress  string             `bson:"ip_address" json:"ip_address"`
+	UserAgent  string             `bson:"user_agent" json:"user_agent"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+func (TrustedDevice) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "token_hash", Value: 1}},
+	}
+}
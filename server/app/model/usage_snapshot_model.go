@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsageSnapshot is a user's or an organization's metered usage over one UTC day
+type UsageSnapshot struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SubjectType     string             `bson:"subject_type" json:"subject_type"` // "user" or "organization"
+	SubjectID       primitive.ObjectID `bson:"subject_id" json:"subject_id"`
+	Date            string             `bson:"date" json:"date"`                 // YYYY-MM-DD
+	BytesStored     int64              `bson:"bytes_stored" json:"bytes_stored"` // Taken when the day was snapshotted
+	BytesUploaded   int64              `bson:"bytes_uploaded" json:"bytes_uploaded"`
+	BytesDownloaded int64              `bson:"bytes_downloaded" json:"bytes_downloaded"`
+	FileCount       int64              `bson:"file_count" json:"file_count"`
+	APICalls        int64              `bson:"api_calls" json:"api_calls"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+func (UsageSnapshot) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "subject_type", Value: 1}, {Key: "subject_id", Value: 1}, {Key: "date", Value: 1}},
+	}
+}
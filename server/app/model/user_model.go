@@ -8,15 +8,25 @@ import (
 )
 
 type User struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty"`
-	Email      string             `bson:"email"`
-	Password   string             `bson:"password"`
-	OtpSecret  string             `bson:"otp_secret"`
-	IsVerified bool               `bson:"is_verified"`
-	IsLocked   bool               `bson:"is_locked"`
-	IsDeleted  bool               `bson:"is_deleted"`
-	CreatedAt  time.Time          `bson:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at"`
+	ID                   primitive.ObjectID `bson:"_id,omitempty"`
+	Email                string             `bson:"email"`
+	Password             string             `bson:"password"`
+	OtpSecret            string             `bson:"otp_secret"`
+	IsVerified           bool               `bson:"is_verified"`
+	IsLocked             bool               `bson:"is_locked"`
+	LockedUntil          *time.Time         `bson:"locked_until,omitempty"` // Unset for locks that only an admin or a password reset lifts
+	LockoutCount         int                `bson:"lockout_count"`          // Consecutive lockouts since the last successful login
+	IsDeleted            bool               `bson:"is_deleted"`
+	DeletionScheduledAt  *time.Time         `bson:"deletion_scheduled_at,omitempty"` // When the account is erased, unset unless the user asked for deletion
+	SubscriptionPlanID   primitive.ObjectID `bson:"subscription_plan_id,omitempty"`  // Unset for the default plan
+	SubscriptionStatusID primitive.ObjectID `bson:"subscription_status_id,omitempty"`
+	CreatedAt            time.Time          `bson:"created_at"`
+	UpdatedAt            time.Time          `bson:"updated_at"`
+}
+
+// IsLockActive reports whether the account is locked right now, automatic lockouts lapse at LockedUntil
+func (u User) IsLockActive() bool {
+	return u.IsLocked && (u.LockedUntil == nil || time.Now().Before(*u.LockedUntil))
 }
 
 func (User) GetIndexes() []bson.D {
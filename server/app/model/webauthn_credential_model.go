@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebAuthnCredential struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `bson:"user_id"`
+	Name         string             `bson:"name"`
+	CredentialID string             `bson:"credential_id"` // base64url
+	PublicKey    []byte             `bson:"public_key"`    // COSE_Key
+	SignCount    int64              `bson:"sign_count"`
+	Transports   []string           `bson:"transports,omitempty"`
+	AAGUID       string             `bson:"aaguid,omitempty"`
+	LastUsedAt   *time.Time         `bson:"last_used_at,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
+
+func (WebAuthnCredential) GetIndexes() []bson.D {
+	return []bson.D{
+		{{Key: "credential_id", Value: 1}},
+	}
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"context"
+	"net"
+)
+
+// DNSResolver looks up DNS records, tests can swap in a fake implementation
+type DNSResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DNSProvider resolves DNS records through the system resolver
+type DNSProvider struct {
+	resolver *net.Resolver
+}
+
+// NewDNSProvider initializes a new DNSProvider
+func NewDNSProvider() *DNSProvider {
+	return &DNSProvider{
+		resolver: net.DefaultResolver,
+	}
+}
+
+// LookupTXT returns the TXT records published for the name
+func (p *DNSProvider) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return p.resolver.LookupTXT(ctx, name)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"errors"
+	"strconv"
 	"time"
 
 	"bongaquino/server/config"
@@ -17,6 +18,7 @@ type JWTProvider struct {
 	secretKey       string
 	tokenDuration   time.Duration
 	refreshDuration time.Duration
+	clientDuration  time.Duration
 }
 
 // NewJWTProvider initializes a new JWTProvider with Redis dependency
@@ -32,29 +34,36 @@ func NewJWTProvider(redisProvider *RedisProvider) *JWTProvider {
 		secretKey:       jwtConfig.JWTSecret,
 		tokenDuration:   time.Duration(jwtConfig.JWTTokenExpiration) * time.Second,
 		refreshDuration: time.Duration(jwtConfig.JWTRefreshExpiration) * time.Second,
+		clientDuration:  time.Duration(jwtConfig.JWTClientExpiration) * time.Second,
 	}
 }
 
 // Claims structure for JWT
 type Claims struct {
-	Sub      string  `json:"sub"`
-	Email    *string `json:"email,omitempty"`
-	ClientId *string `json:"client_id,omitempty"`
-	Scope    string  `json:"scope"`
+	Sub        string   `json:"sub"`
+	Email      *string  `json:"email,omitempty"`
+	ClientId   *string  `json:"client_id,omitempty"`
+	PolicyId   *string  `json:"policy_id,omitempty"`
+	Scope      string   `json:"scope"`
+	Scopes     []string `json:"scopes,omitempty"`
+	IssuedAtMs int64    `json:"iat_ms,omitempty"` // Millisecond issue time, compared against session revocations
 	jwt.RegisteredClaims
 }
 
 // GenerateTokens creates an access and refresh token for a user
 func (j *JWTProvider) GenerateTokens(userID string, email, clientID *string) (accessToken, refreshToken string, err error) {
+	now := time.Now()
+
 	// Generate access token
 	accessClaims := Claims{
-		Sub:      userID,
-		Email:    email,
-		ClientId: clientID,
-		Scope:    "access",
+		Sub:        userID,
+		Email:      email,
+		ClientId:   clientID,
+		Scope:      "access",
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.tokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.tokenDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 	accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(j.secretKey))
@@ -64,13 +73,14 @@ func (j *JWTProvider) GenerateTokens(userID string, email, clientID *string) (ac
 
 	// Generate refresh token
 	refreshClaims := Claims{
-		Sub:      userID,
-		Email:    email,
-		ClientId: clientID,
-		Scope:    "refresh",
+		Sub:        userID,
+		Email:      email,
+		ClientId:   clientID,
+		Scope:      "refresh",
+		IssuedAtMs: now.UnixMilli(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.refreshDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.refreshDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 	refreshToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(j.secretKey))
@@ -89,6 +99,49 @@ func (j *JWTProvider) GenerateTokens(userID string, email, clientID *string) (ac
 	return accessToken, refreshToken, nil
 }
 
+// GenerateClientToken creates a short-lived access token for a service account.
+// No refresh token is issued; clients request a new token with their credentials.
+func (j *JWTProvider) GenerateClientToken(userID, clientID, policyID string, scopes []string) (accessToken string, expiresIn time.Duration, err error) {
+	now := time.Now()
+	claims := Claims{
+		Sub:        userID,
+		ClientId:   &clientID,
+		PolicyId:   &policyID,
+		Scope:      "client",
+		Scopes:     scopes,
+		IssuedAtMs: now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.clientDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	accessToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.secretKey))
+	if err != nil {
+		return "", 0, err
+	}
+
+	return accessToken, j.clientDuration, nil
+}
+
+// ValidateClientToken parses a service account access token
+func (j *JWTProvider) ValidateClientToken(tokenString string) (*Claims, error) {
+	claims, err := j.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Scope != "client" || claims.ClientId == nil || claims.PolicyId == nil {
+		return nil, errors.New("invalid token type, expected client token")
+	}
+
+	return claims, nil
+}
+
+// ClientTokenDuration returns the lifetime of service account access tokens
+func (j *JWTProvider) ClientTokenDuration() time.Duration {
+	return j.clientDuration
+}
+
 // ValidateToken parses and validates a JWT token
 func (j *JWTProvider) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
@@ -98,10 +151,16 @@ func (j *JWTProvider) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	// Reject user tokens issued before the user's sessions were revoked
+	if claims.Scope != "client" && j.isRevoked(claims) {
+		return nil, errors.New("token revoked")
 	}
-	return nil, errors.New("invalid token")
+	return claims, nil
 }
 
 // ValidateRefreshToken checks if the refresh token is valid and exists in Redis
@@ -133,3 +192,52 @@ func (j *JWTProvider) RevokeRefreshToken(userID string) error {
 	ctx := context.Background()
 	return j.redisProvider.Del(ctx, "refresh_token:"+userID)
 }
+
+// RevokeUserTokens ends every session of a user, access and refresh tokens issued until now are rejected
+func (j *JWTProvider) RevokeUserTokens(userID string) error {
+	ctx := context.Background()
+	err := j.redisProvider.Set(ctx, "tokens_revoked_at:"+userID, strconv.FormatInt(time.Now().UnixMilli(), 10), j.refreshDuration)
+	if err != nil {
+		return err
+	}
+	return j.redisProvider.Del(ctx, "refresh_token:"+userID)
+}
+
+// isRevoked reports whether the token was issued before the user's sessions were revoked
+func (j *JWTProvider) isRevoked(claims *Claims) bool {
+	return j.issuedBeforeRevocation(claims, "tokens_revoked_at:"+claims.Sub)
+}
+
+// RevokeClientTokens rejects every access token issued to a service account until now, tokens issued afterwards,
+// such as after the account is re-enabled, are accepted
+func (j *JWTProvider) RevokeClientTokens(clientID string) error {
+	ctx := context.Background()
+	return j.redisProvider.Set(ctx, "client_tokens_revoked_at:"+clientID, strconv.FormatInt(time.Now().UnixMilli(), 10), j.clientDuration)
+}
+
+// IsClientTokenRevoked reports whether a service account token was issued before the account's tokens were revoked
+func (j *JWTProvider) IsClientTokenRevoked(claims *Claims) bool {
+	return j.issuedBeforeRevocation(claims, "client_tokens_revoked_at:"+*claims.ClientId)
+}
+
+// issuedBeforeRevocation compares the token's issue time with the revocation time stored under the key
+func (j *JWTProvider) issuedBeforeRevocation(claims *Claims, key string) bool {
+	revokedAt, err := j.redisProvider.Get(context.Background(), key)
+	if err != nil || revokedAt == "" {
+		return false
+	}
+	revokedAtMs, err := strconv.ParseInt(revokedAt, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	// Tokens issued before iat_ms existed only carry the whole second
+	issuedAtMs := claims.IssuedAtMs
+	if issuedAtMs == 0 {
+		if claims.IssuedAt == nil {
+			return true
+		}
+		issuedAtMs = claims.IssuedAt.UnixMilli()
+	}
+	return issuedAtMs <= revokedAtMs
+}
@@ -37,6 +37,14 @@ func NewMongoProvider() *MongoProvider {
 	}
 }
 
+// NewMongoProviderFromDatabase wraps an already connected database, tests use it with a mock deployment
+func NewMongoProviderFromDatabase(db *mongo.Database) *MongoProvider {
+	return &MongoProvider{
+		client: db.Client(),
+		db:     db,
+	}
+}
+
 // GetDB retrieves the MongoDB database instance
 func (m *MongoProvider) GetDB() *mongo.Database {
 	if m.db == nil {
@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/config"
+)
+
+// OIDCDiscovery holds the parts of an issuer's discovery document used for sign-in
+type OIDCDiscovery struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+// JSONWebKey is a public key published in an issuer's JWKS
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// PublicKey converts the JWK into an RSA, ECDSA or Ed25519 public key
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid EC point")
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// OIDCTokenResponse is the token endpoint's reply to an authorization code exchange
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
+
+type oidcCacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// OIDCProvider talks to OpenID Connect identity providers and caches their discovery documents and keys
+type OIDCProvider struct {
+	client *http.Client
+	mu     sync.Mutex
+	cache  map[string]oidcCacheEntry
+}
+
+// NewOIDCProvider initializes a new OIDCProvider
+func NewOIDCProvider() *OIDCProvider {
+	ssoConfig := config.LoadSSOConfig()
+
+	// Issuers are configured by organization admins, so every connection is checked after DNS resolution, which also
+	// covers rebinding and redirects to the endpoints the discovery document points at
+	dialer := &net.Dialer{Timeout: ssoConfig.HTTPTimeout}
+	if !ssoConfig.AllowInsecureIssuers {
+		dialer.Control = refuseInternalAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would be dialed instead of the issuer and bypass the check
+	transport.DialContext = dialer.DialContext
+
+	return &OIDCProvider{
+		client: &http.Client{Timeout: ssoConfig.HTTPTimeout, Transport: transport},
+		cache:  make(map[string]oidcCacheEntry),
+	}
+}
+
+// Discover fetches the issuer's discovery document
+func (p *OIDCProvider) Discover(ctx context.Context, issuer string) (*OIDCDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	if cached, ok := p.cached("discovery:" + issuer); ok {
+		return cached.(*OIDCDiscovery), nil
+	}
+
+	var discovery OIDCDiscovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+
+	// The document must describe the issuer it was fetched from
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing endpoints")
+	}
+
+	p.store("discovery:"+issuer, &discovery)
+	return &discovery, nil
+}
+
+// JWKS fetches the issuer's signing keys, refresh skips the cache after a key rotation
+func (p *OIDCProvider) JWKS(ctx context.Context, jwksURI string, refresh bool) ([]JSONWebKey, error) {
+	if !refresh {
+		if cached, ok := p.cached("jwks:" + jwksURI); ok {
+			return cached.([]JSONWebKey), nil
+		}
+	}
+
+	var jwks struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	p.store("jwks:"+jwksURI, jwks.Keys)
+	return jwks.Keys, nil
+}
+
+// ExchangeCode redeems an authorization code at the token endpoint
+func (p *OIDCProvider) ExchangeCode(ctx context.Context, tokenEndpoint string, form url.Values) (*OIDCTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResponse OIDCTokenResponse
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, err
+	}
+	return &tokenResponse, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
+
+// refuseInternalAddress stops the client from connecting to loopback, private, link-local and unspecified addresses
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !helper.IsPublicIP(ip) {
+		return fmt.Errorf("connection to %s is not allowed", host)
+	}
+	return nil
+}
+
+func (p *OIDCProvider) cached(key string) (any, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (p *OIDCProvider) store(key string, value any) {
+	ssoConfig := config.LoadSSOConfig()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cache[key] = oidcCacheEntry{value: value, expiresAt: time.Now().Add(ssoConfig.MetadataCacheExpiry)}
+}
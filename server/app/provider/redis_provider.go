@@ -40,6 +40,14 @@ func NewRedisProvider() *RedisProvider {
 	}
 }
 
+// NewRedisProviderFromClient wraps an existing client, tests use it to avoid the connection check
+func NewRedisProviderFromClient(client *redis.Client, prefix string) *RedisProvider {
+	return &RedisProvider{
+		client: client,
+		prefix: prefix,
+	}
+}
+
 // prefixedKey adds the global prefix to a key if a prefix is set
 func (r *RedisProvider) prefixedKey(key string) string {
 	if r.prefix != "" {
@@ -60,8 +68,59 @@ func (r *RedisProvider) Get(ctx context.Context, key string) (string, error) {
 	return r.client.Get(ctx, prefixedKey).Result()
 }
 
+// GetDel retrieves the value of a key and deletes it atomically, so only one caller can consume it
+func (r *RedisProvider) GetDel(ctx context.Context, key string) (string, error) {
+	prefixedKey := r.prefixedKey(key)
+	return r.client.GetDel(ctx, prefixedKey).Result()
+}
+
 // Del deletes a key from Redis
 func (r *RedisProvider) Del(ctx context.Context, key string) error {
 	prefixedKey := r.prefixedKey(key)
 	return r.client.Del(ctx, prefixedKey).Err()
 }
+
+// SetNX sets a key only when it does not exist yet, reporting whether it was set
+func (r *RedisProvider) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	prefixedKey := r.prefixedKey(key)
+	return r.client.SetNX(ctx, prefixedKey, value, expiration).Result()
+}
+
+// Incr increments a counter and starts its expiration when the counter is created
+func (r *RedisProvider) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	prefixedKey := r.prefixedKey(key)
+	count, err := r.client.Incr(ctx, prefixedKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := r.client.Expire(ctx, prefixedKey, expiration).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// IncrBy adds to a counter and starts its expiration when the counter is created
+func (r *RedisProvider) IncrBy(ctx context.Context, key string, value int64, expiration time.Duration) (int64, error) {
+	prefixedKey := r.prefixedKey(key)
+	total, err := r.client.IncrBy(ctx, prefixedKey, value).Result()
+	if err != nil {
+		return 0, err
+	}
+	if total == value {
+		if err := r.client.Expire(ctx, prefixedKey, expiration).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// Eval runs a Lua script atomically against the given keys
+func (r *RedisProvider) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	prefixedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		prefixedKeys[i] = r.prefixedKey(key)
+	}
+	return redis.NewScript(script).Run(ctx, r.client, prefixedKeys, args...).Result()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AuditLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository(mongoProvider *provider.MongoProvider) *AuditLogRepository {
+	return &AuditLogRepository{
+		collection: mongoProvider.GetDB().Collection("audit_logs"),
+	}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		logger.Log.Error("error creating audit log", logger.Error(err))
+		return err
+	}
+	return nil
+}
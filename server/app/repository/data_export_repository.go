@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DataExportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDataExportRepository(mongoProvider *provider.MongoProvider) *DataExportRepository {
+	return &DataExportRepository{
+		collection: mongoProvider.GetDB().Collection("data_exports"),
+	}
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	export.ID = primitive.NewObjectID()
+	export.CreatedAt = time.Now()
+	export.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, export)
+	if err != nil {
+		logger.Log.Error("error creating data export", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *DataExportRepository) Read(ctx context.Context, id string) (*model.DataExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var export model.DataExport
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&export)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error fetching data export", logger.Error(err))
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ListByUserID returns the user's exports, newest first
+func (r *DataExportRepository) ListByUserID(ctx context.Context, userID string) ([]model.DataExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		logger.Log.Error("error fetching data exports", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	exports := []model.DataExport{}
+	if err := cursor.All(ctx, &exports); err != nil {
+		logger.Log.Error("error decoding data exports", logger.Error(err))
+		return nil, err
+	}
+	return exports, nil
+}
+
+// CountActiveByUserID counts the user's exports that are still being built
+func (r *DataExportRepository) CountActiveByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return 0, err
+	}
+
+	filter := bson.M{"user_id": objectID, "status": bson.M{"$in": []string{"pending", "processing"}}}
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		logger.Log.Error("error counting data exports", logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ClaimPending marks the oldest pending export as processing and returns it, nil when there is none
+func (r *DataExportRepository) ClaimPending(ctx context.Context) (*model.DataExport, error) {
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+	update := bson.M{"$set": bson.M{"status": "processing", "updated_at": time.Now()}}
+
+	var export model.DataExport
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"status": "pending"}, update, opts).Decode(&export)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error claiming data export", logger.Error(err))
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	update["updated_at"] = time.Now()
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating data export", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *DataExportRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting data exports", logger.Error(err))
+		return err
+	}
+	return nil
+}
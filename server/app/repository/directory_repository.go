@@ -225,4 +225,20 @@ func (r *DirectoryRepository) FindAllDescendantIDs(ctx context.Context, director
     }
 
     return result, nil
-}
\ No newline at end of file
+}
+
+// DeleteByUserID deletes every directory of the user
+func (r *DirectoryRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting directories", logger.Error(err))
+		return err
+	}
+	return nil
+}
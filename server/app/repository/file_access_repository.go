@@ -162,3 +162,43 @@ func (r *FileAccessRepository) DeleteByFileID(ctx context.Context, fileID string
 	}
 	return nil
 }
+
+// ListByOwnerID returns the shares the user made
+func (r *FileAccessRepository) ListByOwnerID(ctx context.Context, ownerID string) ([]model.FileAccess, error) {
+	objectID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		logger.Log.Error("invalid owner ID format", logger.Error(err))
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": objectID})
+	if err != nil {
+		logger.Log.Error("error listing file access by owner ID", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var fileAccessList []model.FileAccess
+	if err = cursor.All(ctx, &fileAccessList); err != nil {
+		logger.Log.Error("error decoding file access list", logger.Error(err))
+		return nil, err
+	}
+	return fileAccessList, nil
+}
+
+// DeleteByUserID removes the shares the user made or received
+func (r *FileAccessRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid user ID format", logger.Error(err))
+		return err
+	}
+
+	filter := bson.M{"$or": []bson.M{{"owner_id": objectID}, {"recipient_id": objectID}}}
+	_, err = r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		logger.Log.Error("error deleting file access by user ID", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -232,4 +232,20 @@ func (r *FileRepository) CountByUserID(ctx context.Context, userID string) (int6
 		return 0, err
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+// DeleteByUserID deletes every file of the user
+func (r *FileRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting files", logger.Error(err))
+		return err
+	}
+	return nil
+}
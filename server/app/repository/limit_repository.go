@@ -77,6 +77,7 @@ func (r *LimitRepository) ReadByUserID(ctx context.Context, userID string) (*mod
 	return &limit, nil
 }
 
+// ReadByOrganizationID reads the organization's shared pool, not the limits of its members
 func (r *LimitRepository) ReadByOrganizationID(ctx context.Context, orgID string) (*model.Limit, error) {
 	// Convert orgID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(orgID)
@@ -86,7 +87,7 @@ func (r *LimitRepository) ReadByOrganizationID(ctx context.Context, orgID string
 	}
 
 	var limit model.Limit
-	err = r.collection.FindOne(ctx, bson.M{"organization_id": objectID}).Decode(&limit)
+	err = r.collection.FindOne(ctx, bson.M{"organization_id": objectID, "user_id": objectID}).Decode(&limit)
 	if err != nil {
 		if err == mongoDriver.ErrNoDocuments {
 			return nil, nil
@@ -116,6 +117,7 @@ func (r *LimitRepository) UpdateByUserID(ctx context.Context, userID string, upd
 	return nil
 }
 
+// UpdateByOrganizationID updates the organization's shared pool
 func (r *LimitRepository) UpdateByOrganizationID(ctx context.Context, orgID string, update bson.M) error {
 	// Convert orgID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(orgID)
@@ -127,10 +129,92 @@ func (r *LimitRepository) UpdateByOrganizationID(ctx context.Context, orgID stri
 	// Set the updated time
 	update["updated_at"] = time.Now()
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"organization_id": objectID}, bson.M{"$set": update})
+	_, err = r.collection.UpdateOne(ctx, bson.M{"organization_id": objectID, "user_id": objectID}, bson.M{"$set": update})
 	if err != nil {
 		logger.Log.Error("error updating limit by orgID", logger.Error(err))
 		return err
 	}
 	return nil
 }
+
+// ListMembersByOrganizationID returns the limits of the users drawing from the organization's pool
+func (r *LimitRepository) ListMembersByOrganizationID(ctx context.Context, orgID string) ([]model.Limit, error) {
+	objectID, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"organization_id": objectID, "user_id": bson.M{"$ne": objectID}})
+	if err != nil {
+		logger.Log.Error("error listing limits by orgID", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var limits []model.Limit
+	if err = cursor.All(ctx, &limits); err != nil {
+		logger.Log.Error("error decoding limits", logger.Error(err))
+		return nil, err
+	}
+	return limits, nil
+}
+
+// IncrementUsageByUserID adds bytes to the user's usage, returning false when an increase would pass capBytes.
+// A negative capBytes means no cap.
+func (r *LimitRepository) IncrementUsageByUserID(ctx context.Context, userID string, bytes int64, capBytes int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	return r.incrementUsage(ctx, bson.M{"user_id": objectID}, bytes, capBytes)
+}
+
+// IncrementUsageByOrganizationID adds bytes to the usage of the organization's pool, like IncrementUsageByUserID
+func (r *LimitRepository) IncrementUsageByOrganizationID(ctx context.Context, orgID string, bytes int64, capBytes int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(orgID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	return r.incrementUsage(ctx, bson.M{"organization_id": objectID, "user_id": objectID}, bytes, capBytes)
+}
+
+// incrementUsage applies the change in a single update so concurrent uploads can't overshoot the cap,
+// usage never drops below zero
+func (r *LimitRepository) incrementUsage(ctx context.Context, filter bson.M, bytes int64, capBytes int64) (bool, error) {
+	if bytes > 0 && capBytes >= 0 {
+		filter["bytes_usage"] = bson.M{"$lte": capBytes - bytes}
+	}
+
+	update := mongoDriver.Pipeline{{{Key: "$set", Value: bson.M{
+		"bytes_usage": bson.M{"$max": bson.A{0, bson.M{"$add": bson.A{"$bytes_usage", bytes}}}},
+		"updated_at":  time.Now(),
+	}}}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Log.Error("error incrementing limit usage", logger.Error(err))
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// DeleteByUserID deletes every limit of the user
+func (r *LimitRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting limits", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoginEventRepository(mongoProvider *provider.MongoProvider) *LoginEventRepository {
+	return &LoginEventRepository{
+		collection: mongoProvider.GetDB().Collection("login_events"),
+	}
+}
+
+func (r *LoginEventRepository) Create(ctx context.Context, event *model.LoginEvent) error {
+	event.ID = primitive.NewObjectID()
+	event.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		logger.Log.Error("error creating login event", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByUserID returns the user's login events, newest first
+func (r *LoginEventRepository) ListByUserID(ctx context.Context, userID string, page, limit int) ([]model.LoginEvent, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	// Calculate the number of documents to skip
+	skip := (page - 1) * limit
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(int64(skip)).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		logger.Log.Error("error listing login events", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []model.LoginEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		logger.Log.Error("error decoding login events", logger.Error(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// CountSuccessfulByUserID counts the user's successful logins, only those from the fingerprint when one is given
+func (r *LoginEventRepository) CountSuccessfulByUserID(ctx context.Context, userID primitive.ObjectID, fingerprint string) (int64, error) {
+	filter := bson.M{"user_id": userID, "success": true}
+	if fingerprint != "" {
+		filter["fingerprint"] = fingerprint
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		logger.Log.Error("error counting login events", logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteByUserID deletes every login event of the user
+func (r *LoginEventRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting login events", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MFARecoveryCodeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMFARecoveryCodeRepository(mongoProvider *provider.MongoProvider) *MFARecoveryCodeRepository {
+	return &MFARecoveryCodeRepository{
+		collection: mongoProvider.GetDB().Collection("mfa_recovery_codes"),
+	}
+}
+
+func (r *MFARecoveryCodeRepository) CreateMany(ctx context.Context, codes []model.MFARecoveryCode) error {
+	documents := make([]interface{}, len(codes))
+	for i := range codes {
+		codes[i].ID = primitive.NewObjectID()
+		codes[i].CreatedAt = time.Now()
+		documents[i] = codes[i]
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	if err != nil {
+		logger.Log.Error("error creating recovery codes", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CountUnusedByUserID returns how many recovery codes the user has left
+func (r *MFARecoveryCodeRepository) CountUnusedByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return 0, err
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": objectID, "used_at": nil})
+	if err != nil {
+		logger.Log.Error("error counting recovery codes", logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListUnusedByUserID returns the recovery codes the user has left
+func (r *MFARecoveryCodeRepository) ListUnusedByUserID(ctx context.Context, userID string) ([]model.MFARecoveryCode, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID, "used_at": nil})
+	if err != nil {
+		logger.Log.Error("error listing recovery codes", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var codes []model.MFARecoveryCode
+	if err := cursor.All(ctx, &codes); err != nil {
+		logger.Log.Error("error decoding recovery codes", logger.Error(err))
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Consume marks an unused code as used, reporting false when it was already used
+func (r *MFARecoveryCodeRepository) Consume(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": id, "used_at": nil}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"used_at": time.Now()}})
+	if err != nil {
+		logger.Log.Error("error consuming recovery code", logger.Error(err))
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MFARecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting recovery codes", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrganizationInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationInvitationRepository(mongoProvider *provider.MongoProvider) *OrganizationInvitationRepository {
+	return &OrganizationInvitationRepository{
+		collection: mongoProvider.GetDB().Collection("organization_invitations"),
+	}
+}
+
+func (r *OrganizationInvitationRepository) Create(ctx context.Context, invitation *model.OrganizationInvitation) error {
+	invitation.ID = primitive.NewObjectID()
+	invitation.CreatedAt = time.Now()
+	invitation.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		logger.Log.Error("error creating organization invitation", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *OrganizationInvitationRepository) ListByOrganizationID(ctx context.Context, organizationID string) ([]model.OrganizationInvitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"organization_id": objectID}, opts)
+	if err != nil {
+		logger.Log.Error("error listing organization invitations", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []model.OrganizationInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		logger.Log.Error("error decoding organization invitations", logger.Error(err))
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *OrganizationInvitationRepository) ReadByIDOrganizationID(ctx context.Context, id, organizationID string) (*model.OrganizationInvitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+	orgObjectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var invitation model.OrganizationInvitation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "organization_id": orgObjectID}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading organization invitation", logger.Error(err))
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *OrganizationInvitationRepository) ReadByTokenHash(ctx context.Context, tokenHash string) (*model.OrganizationInvitation, error) {
+	var invitation model.OrganizationInvitation
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading organization invitation by token", logger.Error(err))
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *OrganizationInvitationRepository) ReadPendingByOrganizationIDEmail(ctx context.Context, organizationID, email string) (*model.OrganizationInvitation, error) {
+	orgObjectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	filter := bson.M{
+		"organization_id": orgObjectID,
+		"email":           email,
+		"status":          "pending",
+		"expires_at":      bson.M{"$gt": time.Now()},
+	}
+
+	var invitation model.OrganizationInvitation
+	err = r.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading pending organization invitation", logger.Error(err))
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *OrganizationInvitationRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	update["updated_at"] = time.Now()
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating organization invitation", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ExpirePending marks pending invitations past their expiry as expired
+func (r *OrganizationInvitationRepository) ExpirePending(ctx context.Context) error {
+	filter := bson.M{"status": "pending", "expires_at": bson.M{"$lte": time.Now()}}
+	update := bson.M{"$set": bson.M{"status": "expired", "updated_at": time.Now()}}
+
+	_, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Log.Error("error expiring organization invitations", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrganizationJoinRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationJoinRequestRepository(mongoProvider *provider.MongoProvider) *OrganizationJoinRequestRepository {
+	return &OrganizationJoinRequestRepository{
+		collection: mongoProvider.GetDB().Collection("organization_join_requests"),
+	}
+}
+
+func (r *OrganizationJoinRequestRepository) Create(ctx context.Context, joinRequest *model.OrganizationJoinRequest) error {
+	joinRequest.ID = primitive.NewObjectID()
+	joinRequest.CreatedAt = time.Now()
+	joinRequest.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, joinRequest)
+	if err != nil {
+		logger.Log.Error("error creating organization join request", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *OrganizationJoinRequestRepository) ListByOrganizationIDStatus(ctx context.Context, organizationID, status string) ([]model.OrganizationJoinRequest, error) {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	filter := bson.M{"organization_id": objectID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Log.Error("error listing organization join requests", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var joinRequests []model.OrganizationJoinRequest
+	if err := cursor.All(ctx, &joinRequests); err != nil {
+		logger.Log.Error("error decoding organization join requests", logger.Error(err))
+		return nil, err
+	}
+	return joinRequests, nil
+}
+
+func (r *OrganizationJoinRequestRepository) ReadByIDOrganizationID(ctx context.Context, id, organizationID string) (*model.OrganizationJoinRequest, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+	orgObjectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var joinRequest model.OrganizationJoinRequest
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "organization_id": orgObjectID}).Decode(&joinRequest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading organization join request", logger.Error(err))
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+func (r *OrganizationJoinRequestRepository) ReadPendingByOrganizationIDUserID(ctx context.Context, organizationID, userID primitive.ObjectID) (*model.OrganizationJoinRequest, error) {
+	filter := bson.M{"organization_id": organizationID, "user_id": userID, "status": "pending"}
+
+	var joinRequest model.OrganizationJoinRequest
+	err := r.collection.FindOne(ctx, filter).Decode(&joinRequest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading pending organization join request", logger.Error(err))
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+func (r *OrganizationJoinRequestRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	update["updated_at"] = time.Now()
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating organization join request", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -81,6 +81,45 @@ func (r *OrganizationRepository) Read(ctx context.Context, id string) (*model.Or
 	return &organization, nil
 }
 
+// ReadByVerifiedDomain returns the organization that has verified ownership of the domain
+func (r *OrganizationRepository) ReadByVerifiedDomain(ctx context.Context, domain string) (*model.Organization, error) {
+	filter := bson.M{"domain": domain, "domain_verified_at": bson.M{"$ne": nil}}
+
+	var organization model.Organization
+	err := r.collection.FindOne(ctx, filter).Decode(&organization)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error finding organization by verified domain", logger.Error(err))
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// ListBySubscriptionPlanID returns the organizations subscribed to the plan
+func (r *OrganizationRepository) ListBySubscriptionPlanID(ctx context.Context, planID string) ([]model.Organization, error) {
+	objectID, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var orgs []model.Organization
+	cursor, err := r.collection.Find(ctx, bson.M{"subscription_plan_id": objectID})
+	if err != nil {
+		logger.Log.Error("error listing orgs by subscription plan", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &orgs); err != nil {
+		logger.Log.Error("error decoding orgs", logger.Error(err))
+		return nil, err
+	}
+	return orgs, nil
+}
+
 func (r *OrganizationRepository) Update(ctx context.Context, id string, update bson.M) error {
 	// Convert userID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrganizationSSORepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationSSORepository(mongoProvider *provider.MongoProvider) *OrganizationSSORepository {
+	return &OrganizationSSORepository{
+		collection: mongoProvider.GetDB().Collection("organization_sso"),
+	}
+}
+
+func (r *OrganizationSSORepository) Create(ctx context.Context, sso *model.OrganizationSSO) error {
+	sso.ID = primitive.NewObjectID()
+	sso.CreatedAt = time.Now()
+	sso.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, sso)
+	if err != nil {
+		logger.Log.Error("error creating organization SSO", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *OrganizationSSORepository) ReadByOrganizationID(ctx context.Context, organizationID string) (*model.OrganizationSSO, error) {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var sso model.OrganizationSSO
+	err = r.collection.FindOne(ctx, bson.M{"organization_id": objectID}).Decode(&sso)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading organization SSO", logger.Error(err))
+		return nil, err
+	}
+	return &sso, nil
+}
+
+// ReadEnabledByDomain finds the enabled identity provider that accepts the email domain
+func (r *OrganizationSSORepository) ReadEnabledByDomain(ctx context.Context, domain string) (*model.OrganizationSSO, error) {
+	var sso model.OrganizationSSO
+	err := r.collection.FindOne(ctx, bson.M{"allowed_domains": domain, "is_enabled": true}).Decode(&sso)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading organization SSO by domain", logger.Error(err))
+		return nil, err
+	}
+	return &sso, nil
+}
+
+func (r *OrganizationSSORepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	update["updated_at"] = time.Now()
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating organization SSO", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *OrganizationSSORepository) DeleteByOrganizationID(ctx context.Context, organizationID string) error {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"organization_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting organization SSO", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -160,3 +160,19 @@ func (r *OrganizationUserRoleRepository) UpdateByOrganizationIDUserID(ctx contex
 	}
 	return nil
 }
+
+// DeleteByUserID deletes every organization membership of the user
+func (r *OrganizationUserRoleRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting organization memberships", logger.Error(err))
+		return err
+	}
+	return nil
+}
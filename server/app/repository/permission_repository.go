@@ -63,6 +63,26 @@ func (r *PermissionRepository) Create(ctx context.Context, permission *model.Per
 	return nil
 }
 
+func (r *PermissionRepository) Read(ctx context.Context, id string) (*model.Permission, error) {
+	// Convert id to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var permission model.Permission
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&permission)
+	if err != nil {
+		if err == mongoDriver.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading permission by ID", logger.Error(err))
+		return nil, err
+	}
+	return &permission, nil
+}
+
 func (r *PermissionRepository) ReadByName(ctx context.Context, name string) (*model.Permission, error) {
 	var permission model.Permission
 	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&permission)
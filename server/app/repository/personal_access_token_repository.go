@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PersonalAccessTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPersonalAccessTokenRepository(mongoProvider *provider.MongoProvider) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{
+		collection: mongoProvider.GetDB().Collection("personal_access_tokens"),
+	}
+}
+
+func (r *PersonalAccessTokenRepository) Create(ctx context.Context, token *model.PersonalAccessToken) error {
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, token)
+	if err != nil {
+		logger.Log.Error("error creating personal access token", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByUserID returns the user's tokens, newest first
+func (r *PersonalAccessTokenRepository) ListByUserID(ctx context.Context, userID string) ([]model.PersonalAccessToken, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		logger.Log.Error("error listing personal access tokens", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []model.PersonalAccessToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		logger.Log.Error("error decoding personal access tokens", logger.Error(err))
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *PersonalAccessTokenRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return 0, err
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error counting personal access tokens", logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PersonalAccessTokenRepository) ReadByTokenHash(ctx context.Context, tokenHash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading personal access token", logger.Error(err))
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PersonalAccessTokenRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating personal access token", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteByIDUserID removes one of the user's tokens, reporting false when it does not exist
+func (r *PersonalAccessTokenRepository) DeleteByIDUserID(ctx context.Context, id, userID string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userObjectID})
+	if err != nil {
+		logger.Log.Error("error deleting personal access token", logger.Error(err))
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+// DeleteByUserID deletes every personal access token of the user
+func (r *PersonalAccessTokenRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting personal access tokens", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -38,9 +38,16 @@ func (r *PolicyPermissionRepository) Create(ctx context.Context, policyPermissio
 }
 
 func (r *PolicyPermissionRepository) ReadByPolicyID(ctx context.Context, policyID string) ([]model.PolicyPermission, error) {
+	// Convert policyID to ObjectID
+	policyObjectID, err := primitive.ObjectIDFromHex(policyID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
 	var results []model.PolicyPermission
 
-	cursor, err := r.collection.Find(ctx, bson.M{"policy_id": policyID})
+	cursor, err := r.collection.Find(ctx, bson.M{"policy_id": policyObjectID})
 	if err != nil {
 		logger.Log.Error("error retrieving policy permissions", logger.Error(err))
 		return nil, err
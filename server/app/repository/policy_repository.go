@@ -96,8 +96,15 @@ func (r *PolicyRepository) ReadByName(ctx context.Context, name string) (*model.
 	return &policy, nil
 }
 
-func (r *PolicyRepository) Update(ctx context.Context, name string, update bson.M) error {
-	_, err := r.collection.UpdateOne(ctx, bson.M{"name": name}, bson.M{"$set": update})
+func (r *PolicyRepository) Update(ctx context.Context, id string, update bson.M) error {
+	// Convert id to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": update})
 	if err != nil {
 		logger.Log.Error("error updating policy", logger.Error(err))
 		return err
@@ -38,9 +38,16 @@ func (r *RolePermissionRepository) Create(ctx context.Context, rolePermission *m
 }
 
 func (r *RolePermissionRepository) ReadByRoleID(ctx context.Context, roleID string) ([]model.RolePermission, error) {
+	// Convert roleID to ObjectID
+	roleObjectID, err := primitive.ObjectIDFromHex(roleID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
 	var results []model.RolePermission
 
-	cursor, err := r.collection.Find(ctx, bson.M{"role_id": roleID})
+	cursor, err := r.collection.Find(ctx, bson.M{"role_id": roleObjectID})
 	if err != nil {
 		logger.Log.Error("error retrieving role permissions", logger.Error(err))
 		return nil, err
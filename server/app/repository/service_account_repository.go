@@ -5,7 +5,6 @@ import (
 	"errors"
 	"time"
 
-	"bongaquino/server/app/helper"
 	"bongaquino/server/app/model"
 	"bongaquino/server/app/provider"
 	"bongaquino/server/core/logger"
@@ -26,6 +25,7 @@ func NewServiceAccountRepository(mongoProvider *provider.MongoProvider) *Service
 	}
 }
 
+// ListByUserID returns the user's personal service accounts
 func (r *ServiceAccountRepository) ListByUserID(ctx context.Context, userID string) ([]*model.ServiceAccount, error) {
 	// Convert userID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(userID)
@@ -35,7 +35,7 @@ func (r *ServiceAccountRepository) ListByUserID(ctx context.Context, userID stri
 	}
 
 	var accounts []*model.ServiceAccount
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID, "organization_id": primitive.NilObjectID})
 	if err != nil {
 		logger.Log.Error("error reading service accounts by user ID", logger.Error(err))
 		return nil, err
@@ -59,19 +59,45 @@ func (r *ServiceAccountRepository) ListByUserID(ctx context.Context, userID stri
 	return accounts, nil
 }
 
+func (r *ServiceAccountRepository) ListByOrganizationID(ctx context.Context, organizationID string) ([]*model.ServiceAccount, error) {
+	// Convert organizationID to ObjectID
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var accounts []*model.ServiceAccount
+	cursor, err := r.collection.Find(ctx, bson.M{"organization_id": objectID})
+	if err != nil {
+		logger.Log.Error("error reading service accounts by organization ID", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var account model.ServiceAccount
+		if err := cursor.Decode(&account); err != nil {
+			logger.Log.Error("error decoding service account", logger.Error(err))
+			return nil, err
+		}
+		accounts = append(accounts, &account)
+	}
+
+	if err := cursor.Err(); err != nil {
+		logger.Log.Error("cursor error", logger.Error(err))
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
 func (r *ServiceAccountRepository) Create(ctx context.Context, account *model.ServiceAccount) error {
 	account.ID = primitive.NewObjectID()
 	account.CreatedAt = time.Now()
 	account.UpdatedAt = time.Now()
 
-	hashedSecret, err := helper.Hash(account.ClientSecret)
-	if err != nil {
-		logger.Log.Error("error hashing client secret", logger.Error(err))
-		return err
-	}
-	account.ClientSecret = hashedSecret
-
-	_, err = r.collection.InsertOne(ctx, account)
+	_, err := r.collection.InsertOne(ctx, account)
 	if err != nil {
 		logger.Log.Error("error creating service account", logger.Error(err))
 		return err
@@ -104,6 +130,98 @@ func (r *ServiceAccountRepository) UpdateByClientID(ctx context.Context, clientI
 	return nil
 }
 
+// TouchByClientID records a use of the account and of the secret it authenticated with, legacy secrets have no ID
+func (r *ServiceAccountRepository) TouchByClientID(ctx context.Context, clientID, secretID string) error {
+	now := time.Now()
+	filter := bson.M{"client_id": clientID}
+	update := bson.M{"last_used_at": now}
+	if secretID != "" {
+		filter["secrets.id"] = secretID
+		update["secrets.$.last_used_at"] = now
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating service account", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// TouchKeyByClientID records a use of the account and of the key its assertion was signed with
+func (r *ServiceAccountRepository) TouchKeyByClientID(ctx context.Context, clientID, keyID string) error {
+	now := time.Now()
+	filter := bson.M{"client_id": clientID, "keys.id": keyID}
+	update := bson.M{"last_used_at": now, "keys.$.last_used_at": now}
+
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating service account", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListWithSecretsExpiringBefore returns enabled accounts holding a secret that expires before the deadline and has not been warned about
+func (r *ServiceAccountRepository) ListWithSecretsExpiringBefore(ctx context.Context, deadline time.Time) ([]*model.ServiceAccount, error) {
+	filter := bson.M{
+		"disabled_at": nil,
+		"secrets": bson.M{"$elemMatch": bson.M{
+			"expires_at": bson.M{"$gt": time.Now(), "$lte": deadline},
+			"warned_at":  nil,
+		}},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		logger.Log.Error("error listing expiring service accounts", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	accounts := []*model.ServiceAccount{}
+	if err := cursor.All(ctx, &accounts); err != nil {
+		logger.Log.Error("error decoding service accounts", logger.Error(err))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// MarkSecretWarnedByClientID records that the owner was told the secret is about to expire
+func (r *ServiceAccountRepository) MarkSecretWarnedByClientID(ctx context.Context, clientID, secretID string) error {
+	filter := bson.M{"client_id": clientID, "secrets.id": secretID}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"secrets.$.warned_at": time.Now()}})
+	if err != nil {
+		logger.Log.Error("error updating service account secret", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListInactiveSince returns enabled accounts created and last used before the cutoff
+func (r *ServiceAccountRepository) ListInactiveSince(ctx context.Context, cutoff time.Time) ([]*model.ServiceAccount, error) {
+	filter := bson.M{
+		"disabled_at":  nil,
+		"created_at":   bson.M{"$lt": cutoff},
+		"last_used_at": bson.M{"$lt": cutoff},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		logger.Log.Error("error listing inactive service accounts", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	accounts := []*model.ServiceAccount{}
+	if err := cursor.All(ctx, &accounts); err != nil {
+		logger.Log.Error("error decoding service accounts", logger.Error(err))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// DeleteByUserIDClientID deletes one of the user's personal service accounts
 func (r *ServiceAccountRepository) DeleteByUserIDClientID(ctx context.Context, userID, clientID string) error {
 	// Convert userID to ObjectID
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
@@ -114,7 +232,7 @@ func (r *ServiceAccountRepository) DeleteByUserIDClientID(ctx context.Context, u
 
 	// Check if the service account exists
 	var account model.ServiceAccount
-	err = r.collection.FindOne(ctx, bson.M{"user_id": userObjectID, "client_id": clientID}).Decode(&account)
+	err = r.collection.FindOne(ctx, bson.M{"user_id": userObjectID, "organization_id": primitive.NilObjectID, "client_id": clientID}).Decode(&account)
 	if err != nil {
 		if err == mongoDriver.ErrNoDocuments {
 			// Return new error if the service account does not exist
@@ -124,15 +242,37 @@ func (r *ServiceAccountRepository) DeleteByUserIDClientID(ctx context.Context, u
 	}
 
 	// Delete the service account
-	_, err = r.collection.DeleteOne(ctx, bson.M{"user_id": userObjectID, "client_id": clientID})
+	_, err = r.collection.DeleteOne(ctx, bson.M{"user_id": userObjectID, "organization_id": primitive.NilObjectID, "client_id": clientID})
+	if err != nil {
+		logger.Log.Error("error deleting service account", logger.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *ServiceAccountRepository) DeleteByOrganizationIDClientID(ctx context.Context, organizationID, clientID string) error {
+	// Convert organizationID to ObjectID
+	orgObjectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	// Delete the service account, only when it belongs to the organization
+	result, err := r.collection.DeleteOne(ctx, bson.M{"organization_id": orgObjectID, "client_id": clientID})
 	if err != nil {
 		logger.Log.Error("error deleting service account", logger.Error(err))
 		return err
 	}
+	if result.DeletedCount == 0 {
+		return errors.New("service account not found")
+	}
 
 	return nil
 }
 
+// CountByUserID counts the user's personal service accounts
 func (r *ServiceAccountRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
 	// Convert userID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(userID)
@@ -141,7 +281,7 @@ func (r *ServiceAccountRepository) CountByUserID(ctx context.Context, userID str
 		return 0, err
 	}
 
-	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": objectID})
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": objectID, "organization_id": primitive.NilObjectID})
 	if err != nil {
 		logger.Log.Error("error counting service accounts by user ID", logger.Error(err))
 		return 0, err
@@ -149,3 +289,36 @@ func (r *ServiceAccountRepository) CountByUserID(ctx context.Context, userID str
 
 	return count, nil
 }
+
+// CountByOrganizationID counts the organization's service accounts
+func (r *ServiceAccountRepository) CountByOrganizationID(ctx context.Context, organizationID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return 0, err
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"organization_id": objectID})
+	if err != nil {
+		logger.Log.Error("error counting service accounts by organization ID", logger.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteByUserID deletes every personal service account of the user, organization accounts stay with the organization
+func (r *ServiceAccountRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID, "organization_id": primitive.NilObjectID})
+	if err != nil {
+		logger.Log.Error("error deleting service accounts", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SubscriptionPlanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSubscriptionPlanRepository(mongoProvider *provider.MongoProvider) *SubscriptionPlanRepository {
+	return &SubscriptionPlanRepository{
+		collection: mongoProvider.GetDB().Collection("subscription_plans"),
+	}
+}
+
+func (r *SubscriptionPlanRepository) List(ctx context.Context) ([]model.SubscriptionPlan, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "bytes_limit", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		logger.Log.Error("error listing subscription plans", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var plans []model.SubscriptionPlan
+	if err := cursor.All(ctx, &plans); err != nil {
+		logger.Log.Error("error decoding subscription plans", logger.Error(err))
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *SubscriptionPlanRepository) Create(ctx context.Context, plan *model.SubscriptionPlan) error {
+	plan.ID = primitive.NewObjectID()
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, plan)
+	if err != nil {
+		logger.Log.Error("error creating subscription plan", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *SubscriptionPlanRepository) Read(ctx context.Context, id string) (*model.SubscriptionPlan, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var plan model.SubscriptionPlan
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&plan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading subscription plan", logger.Error(err))
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *SubscriptionPlanRepository) ReadByName(ctx context.Context, name string) (*model.SubscriptionPlan, error) {
+	var plan model.SubscriptionPlan
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&plan)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading subscription plan by name", logger.Error(err))
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *SubscriptionPlanRepository) Update(ctx context.Context, id string, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	update["updated_at"] = time.Now()
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating subscription plan", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *SubscriptionPlanRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting subscription plan", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SubscriptionStatusRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSubscriptionStatusRepository(mongoProvider *provider.MongoProvider) *SubscriptionStatusRepository {
+	return &SubscriptionStatusRepository{
+		collection: mongoProvider.GetDB().Collection("subscription_statuses"),
+	}
+}
+
+func (r *SubscriptionStatusRepository) Create(ctx context.Context, status *model.SubscriptionStatus) error {
+	status.ID = primitive.NewObjectID()
+	status.CreatedAt = time.Now()
+	status.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, status)
+	if err != nil {
+		logger.Log.Error("error creating subscription status", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *SubscriptionStatusRepository) Read(ctx context.Context, id string) (*model.SubscriptionStatus, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var status model.SubscriptionStatus
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&status)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading subscription status", logger.Error(err))
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (r *SubscriptionStatusRepository) ReadByName(ctx context.Context, name string) (*model.SubscriptionStatus, error) {
+	var status model.SubscriptionStatus
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&status)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading subscription status by name", logger.Error(err))
+		return nil, err
+	}
+	return &status, nil
+}
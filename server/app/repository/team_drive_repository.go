@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TeamDriveRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTeamDriveRepository(mongoProvider *provider.MongoProvider) *TeamDriveRepository {
+	return &TeamDriveRepository{
+		collection: mongoProvider.GetDB().Collection("team_drives"),
+	}
+}
+
+func (r *TeamDriveRepository) Create(ctx context.Context, drive *model.TeamDrive) error {
+	drive.ID = primitive.NewObjectID()
+	drive.CreatedAt = time.Now()
+	drive.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, drive)
+	if err != nil {
+		logger.Log.Error("error creating team drive", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *TeamDriveRepository) Read(ctx context.Context, id string) (*model.TeamDrive, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var drive model.TeamDrive
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&drive)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading team drive", logger.Error(err))
+		return nil, err
+	}
+	return &drive, nil
+}
+
+func (r *TeamDriveRepository) ReadByIDOrganizationID(ctx context.Context, id, organizationID string) (*model.TeamDrive, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+	orgObjectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var drive model.TeamDrive
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "organization_id": orgObjectID}).Decode(&drive)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading team drive", logger.Error(err))
+		return nil, err
+	}
+	return &drive, nil
+}
+
+func (r *TeamDriveRepository) ListByOrganizationID(ctx context.Context, organizationID string) ([]model.TeamDrive, error) {
+	objectID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"organization_id": objectID}, opts)
+	if err != nil {
+		logger.Log.Error("error listing team drives", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var drives []model.TeamDrive
+	if err := cursor.All(ctx, &drives); err != nil {
+		logger.Log.Error("error decoding team drives", logger.Error(err))
+		return nil, err
+	}
+	return drives, nil
+}
+
+func (r *TeamDriveRepository) Update(ctx context.Context, id string, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	update["updated_at"] = time.Now()
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating team drive", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *TeamDriveRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting team drive", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TrustedDeviceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTrustedDeviceRepository(mongoProvider *provider.MongoProvider) *TrustedDeviceRepository {
+	return &TrustedDeviceRepository{
+		collection: mongoProvider.GetDB().Collection("trusted_devices"),
+	}
+}
+
+func (r *TrustedDeviceRepository) Create(ctx context.Context, device *model.TrustedDevice) error {
+	device.ID = primitive.NewObjectID()
+	device.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, device)
+	if err != nil {
+		logger.Log.Error("error creating trusted device", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListByUserID returns the user's unexpired trusted devices, most recently trusted first
+func (r *TrustedDeviceRepository) ListByUserID(ctx context.Context, userID string) ([]model.TrustedDevice, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	filter := bson.M{"user_id": objectID, "expires_at": bson.M{"$gt": time.Now()}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		logger.Log.Error("error listing trusted devices", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	devices := []model.TrustedDevice{}
+	if err := cursor.All(ctx, &devices); err != nil {
+		logger.Log.Error("error decoding trusted devices", logger.Error(err))
+		return nil, err
+	}
+	return devices, nil
+}
+
+// TouchByUserIDTokenHash marks an unexpired device as used, reporting false when no such device exists
+func (r *TrustedDeviceRepository) TouchByUserIDTokenHash(ctx context.Context, userID, tokenHash string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	filter := bson.M{"user_id": objectID, "token_hash": tokenHash, "expires_at": bson.M{"$gt": time.Now()}}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_used_at": time.Now()}})
+	if err != nil {
+		logger.Log.Error("error updating trusted device", logger.Error(err))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r *TrustedDeviceRepository) DeleteByIDUserID(ctx context.Context, id, userID string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userObjectID})
+	if err != nil {
+		logger.Log.Error("error deleting trusted device", logger.Error(err))
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+func (r *TrustedDeviceRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting trusted devices", logger.Error(err))
+		return err
+	}
+	return nil
+}
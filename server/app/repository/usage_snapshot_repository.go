@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UsageSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUsageSnapshotRepository(mongoProvider *provider.MongoProvider) *UsageSnapshotRepository {
+	return &UsageSnapshotRepository{
+		collection: mongoProvider.GetDB().Collection("usage_snapshots"),
+	}
+}
+
+// Upsert writes the subject's snapshot for its day, replacing the figures of an earlier run
+func (r *UsageSnapshotRepository) Upsert(ctx context.Context, snapshot *model.UsageSnapshot) error {
+	now := time.Now()
+	filter := bson.M{
+		"subject_type": snapshot.SubjectType,
+		"subject_id":   snapshot.SubjectID,
+		"date":         snapshot.Date,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"bytes_stored":     snapshot.BytesStored,
+			"bytes_uploaded":   snapshot.BytesUploaded,
+			"bytes_downloaded": snapshot.BytesDownloaded,
+			"file_count":       snapshot.FileCount,
+			"api_calls":        snapshot.APICalls,
+			"updated_at":       now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		logger.Log.Error("error upserting usage snapshot", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListBySubject returns the subject's snapshots between two days, both included, oldest first
+func (r *UsageSnapshotRepository) ListBySubject(ctx context.Context, subjectType, subjectID, from, to string) ([]model.UsageSnapshot, error) {
+	objectID, err := primitive.ObjectIDFromHex(subjectID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	return r.list(ctx, bson.M{
+		"subject_type": subjectType,
+		"subject_id":   objectID,
+		"date":         bson.M{"$gte": from, "$lte": to},
+	})
+}
+
+// ListByPeriod returns every snapshot of the subject type between two days, both included,
+// grouped by subject and oldest first
+func (r *UsageSnapshotRepository) ListByPeriod(ctx context.Context, subjectType, from, to string) ([]model.UsageSnapshot, error) {
+	return r.list(ctx, bson.M{
+		"subject_type": subjectType,
+		"date":         bson.M{"$gte": from, "$lte": to},
+	})
+}
+
+func (r *UsageSnapshotRepository) list(ctx context.Context, filter bson.M) ([]model.UsageSnapshot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "subject_id", Value: 1}, {Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		logger.Log.Error("error fetching usage snapshots", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	snapshots := []model.UsageSnapshot{}
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		logger.Log.Error("error decoding usage snapshots", logger.Error(err))
+		return nil, err
+	}
+	return snapshots, nil
+}
@@ -83,6 +83,21 @@ func (r *UserRepository) ReadByEmail(ctx context.Context, email string) (*model.
 	return &user, nil
 }
 
+// ReadByEmailCaseInsensitive matches the email regardless of letter case, for addresses typed by someone other than the owner
+func (r *UserRepository) ReadByEmailCaseInsensitive(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	opts := options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	err := r.collection.FindOne(ctx, bson.M{"email": email}, opts).Decode(&user)
+	if err != nil {
+		if err == mongoDriver.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading user by email", logger.Error(err))
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *UserRepository) Read(ctx context.Context, id string) (*model.User, error) {
 	// Convert id to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -140,6 +155,47 @@ func (r *UserRepository) Delete(ctx context.Context, email string) error {
 	return nil
 }
 
+// ListDeletionDueBefore returns the users whose scheduled deletion is due
+func (r *UserRepository) ListDeletionDueBefore(ctx context.Context, before time.Time) ([]model.User, error) {
+	var users []model.User
+
+	cursor, err := r.collection.Find(ctx, bson.M{"deletion_scheduled_at": bson.M{"$ne": nil, "$lte": before}})
+	if err != nil {
+		logger.Log.Error("error listing users due for deletion", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &users); err != nil {
+		logger.Log.Error("error decoding users", logger.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListBySubscriptionPlanID returns the users subscribed to the plan
+func (r *UserRepository) ListBySubscriptionPlanID(ctx context.Context, planID string) ([]model.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(planID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	var users []model.User
+	cursor, err := r.collection.Find(ctx, bson.M{"subscription_plan_id": objectID})
+	if err != nil {
+		logger.Log.Error("error listing users by subscription plan", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &users); err != nil {
+		logger.Log.Error("error decoding users", logger.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *UserRepository) SearchByEmail(ctx context.Context, email string) ([]model.User, error) {
 	var users []model.User
 
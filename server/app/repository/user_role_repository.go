@@ -110,3 +110,19 @@ func (r *UserRoleRepository) Delete(ctx context.Context, userID, roleID string)
 	}
 	return nil
 }
+
+// DeleteByUserID deletes every role assignment of the user
+func (r *UserRoleRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting user roles", logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebAuthnCredentialRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebAuthnCredentialRepository(mongoProvider *provider.MongoProvider) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		collection: mongoProvider.GetDB().Collection("webauthn_credentials"),
+	}
+}
+
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, credential *model.WebAuthnCredential) error {
+	credential.ID = primitive.NewObjectID()
+	credential.CreatedAt = time.Now()
+	credential.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, credential)
+	if err != nil {
+		logger.Log.Error("error creating webauthn credential", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *WebAuthnCredentialRepository) ListByUserID(ctx context.Context, userID string) ([]model.WebAuthnCredential, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		logger.Log.Error("error listing webauthn credentials", logger.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var credentials []model.WebAuthnCredential
+	if err := cursor.All(ctx, &credentials); err != nil {
+		logger.Log.Error("error decoding webauthn credentials", logger.Error(err))
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (r *WebAuthnCredentialRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return 0, err
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error counting webauthn credentials", logger.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *WebAuthnCredentialRepository) ReadByCredentialID(ctx context.Context, credentialID string) (*model.WebAuthnCredential, error) {
+	var credential model.WebAuthnCredential
+	err := r.collection.FindOne(ctx, bson.M{"credential_id": credentialID}).Decode(&credential)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		logger.Log.Error("error reading webauthn credential", logger.Error(err))
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *WebAuthnCredentialRepository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	update["updated_at"] = time.Now()
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": update})
+	if err != nil {
+		logger.Log.Error("error updating webauthn credential", logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteByIDUserID removes one of the user's credentials, reporting false when it does not exist
+func (r *WebAuthnCredentialRepository) DeleteByIDUserID(ctx context.Context, id, userID string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return false, err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userObjectID})
+	if err != nil {
+		logger.Log.Error("error deleting webauthn credential", logger.Error(err))
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+// DeleteByUserID deletes every passkey of the user
+func (r *WebAuthnCredentialRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Log.Error("invalid ID format", logger.Error(err))
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		logger.Log.Error("error deleting passkeys", logger.Error(err))
+		return err
+	}
+	return nil
+}
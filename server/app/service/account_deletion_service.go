@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AccountDeletionService erases accounts the user asked to delete once the grace period is over
+type AccountDeletionService struct {
+	userRepo                 *repository.UserRepository
+	profileRepo              *repository.ProfileRepository
+	settingRepo              *repository.SettingRepository
+	userRoleRepo             *repository.UserRoleRepository
+	limitRepo                *repository.LimitRepository
+	directoryRepo            *repository.DirectoryRepository
+	fileRepo                 *repository.FileRepository
+	fileAccessRepo           *repository.FileAccessRepository
+	serviceAccountRepo       *repository.ServiceAccountRepository
+	personalAccessTokenRepo  *repository.PersonalAccessTokenRepository
+	trustedDeviceRepo        *repository.TrustedDeviceRepository
+	recoveryCodeRepo         *repository.MFARecoveryCodeRepository
+	webauthnCredentialRepo   *repository.WebAuthnCredentialRepository
+	organizationUserRoleRepo *repository.OrganizationUserRoleRepository
+	loginEventRepo           *repository.LoginEventRepository
+	dataExportRepo           *repository.DataExportRepository
+	auditLogRepo             *repository.AuditLogRepository
+	emailService             *EmailService
+	quotaService             *QuotaService
+	jwtProvider              *provider.JWTProvider
+	redisProvider            *provider.RedisProvider
+}
+
+// NewAccountDeletionService initializes a new AccountDeletionService
+func NewAccountDeletionService(
+	userRepo *repository.UserRepository,
+	profileRepo *repository.ProfileRepository,
+	settingRepo *repository.SettingRepository,
+	userRoleRepo *repository.UserRoleRepository,
+	limitRepo *repository.LimitRepository,
+	directoryRepo *repository.DirectoryRepository,
+	fileRepo *repository.FileRepository,
+	fileAccessRepo *repository.FileAccessRepository,
+	serviceAccountRepo *repository.ServiceAccountRepository,
+	personalAccessTokenRepo *repository.PersonalAccessTokenRepository,
+	trustedDeviceRepo *repository.TrustedDeviceRepository,
+	recoveryCodeRepo *repository.MFARecoveryCodeRepository,
+	webauthnCredentialRepo *repository.WebAuthnCredentialRepository,
+	organizationUserRoleRepo *repository.OrganizationUserRoleRepository,
+	loginEventRepo *repository.LoginEventRepository,
+	dataExportRepo *repository.DataExportRepository,
+	auditLogRepo *repository.AuditLogRepository,
+	emailService *EmailService,
+	quotaService *QuotaService,
+	jwtProvider *provider.JWTProvider,
+	redisProvider *provider.RedisProvider,
+) *AccountDeletionService {
+	return &AccountDeletionService{
+		userRepo:                 userRepo,
+		profileRepo:              profileRepo,
+		settingRepo:              settingRepo,
+		userRoleRepo:             userRoleRepo,
+		limitRepo:                limitRepo,
+		directoryRepo:            directoryRepo,
+		fileRepo:                 fileRepo,
+		fileAccessRepo:           fileAccessRepo,
+		serviceAccountRepo:       serviceAccountRepo,
+		personalAccessTokenRepo:  personalAccessTokenRepo,
+		trustedDeviceRepo:        trustedDeviceRepo,
+		recoveryCodeRepo:         recoveryCodeRepo,
+		webauthnCredentialRepo:   webauthnCredentialRepo,
+		organizationUserRoleRepo: organizationUserRoleRepo,
+		loginEventRepo:           loginEventRepo,
+		dataExportRepo:           dataExportRepo,
+		auditLogRepo:             auditLogRepo,
+		emailService:             emailService,
+		quotaService:             quotaService,
+		jwtProvider:              jwtProvider,
+		redisProvider:            redisProvider,
+	}
+}
+
+// ScheduleDeletion marks the account for deletion after the grace period, the user can still sign in to cancel
+func (ads *AccountDeletionService) ScheduleDeletion(ctx context.Context, userID, password string) (time.Time, error) {
+	accountConfig := config.LoadAccountConfig()
+
+	user, err := ads.userRepo.Read(ctx, userID)
+	if err != nil {
+		return time.Time{}, errors.New("failed to retrieve user")
+	}
+	if user == nil {
+		return time.Time{}, errors.New("user not found")
+	}
+	if user.DeletionScheduledAt != nil {
+		return time.Time{}, errors.New("account deletion already scheduled")
+	}
+
+	// The password is asked again so an unattended session can't delete the account
+	if !helper.CheckHash(password, user.Password) {
+		return time.Time{}, errors.New("password is incorrect")
+	}
+
+	deleteAt := time.Now().Add(accountConfig.DeletionGracePeriod)
+	if err := ads.userRepo.Update(ctx, userID, bson.M{"deletion_scheduled_at": deleteAt}); err != nil {
+		return time.Time{}, errors.New("failed to schedule account deletion")
+	}
+
+	recordAudit(ctx, ads.auditLogRepo, "account_deletion_requested", user.ID, &user.ID, bson.M{"delete_at": deleteAt})
+
+	if err := ads.emailService.SendAccountDeletionScheduled(user.Email, deleteAt); err != nil {
+		logger.Log.Error("failed to send account deletion email", logger.Error(err))
+	}
+
+	return deleteAt, nil
+}
+
+// CancelDeletion keeps an account that was scheduled for deletion
+func (ads *AccountDeletionService) CancelDeletion(ctx context.Context, userID string) error {
+	user, err := ads.userRepo.Read(ctx, userID)
+	if err != nil {
+		return errors.New("failed to retrieve user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if user.DeletionScheduledAt == nil {
+		return errors.New("no account deletion scheduled")
+	}
+
+	if err := ads.userRepo.Update(ctx, userID, bson.M{"deletion_scheduled_at": nil}); err != nil {
+		return errors.New("failed to cancel account deletion")
+	}
+
+	recordAudit(ctx, ads.auditLogRepo, "account_deletion_cancelled", user.ID, &user.ID, nil)
+
+	return nil
+}
+
+// DeleteDueAccounts erases every account whose grace period is over
+func (ads *AccountDeletionService) DeleteDueAccounts(ctx context.Context) error {
+	users, err := ads.userRepo.ListDeletionDueBefore(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		if err := ads.deleteAccount(ctx, &users[i]); err != nil {
+			logger.Log.Error("failed to delete account", logger.String("user_id", users[i].ID.Hex()), logger.Error(err))
+		}
+	}
+	return nil
+}
+
+// deleteAccount removes the user's files, shares, credentials and records, then the user itself.
+// Steps are idempotent so an interrupted deletion is finished by the next run.
+func (ads *AccountDeletionService) deleteAccount(ctx context.Context, user *model.User) error {
+	userID := user.ID.Hex()
+
+	// Tokens already issued to the user's service accounts stop working right away
+	serviceAccounts, err := ads.serviceAccountRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, serviceAccount := range serviceAccounts {
+		err := ads.jwtProvider.RevokeClientTokens(serviceAccount.ClientID)
+		if err != nil {
+			return err
+		}
+	}
+	if err := ads.jwtProvider.RevokeUserTokens(userID); err != nil {
+		return err
+	}
+
+	steps := []struct {
+		name   string
+		delete func(ctx context.Context, userID string) error
+	}{
+		{"file_access", ads.fileAccessRepo.DeleteByUserID},
+		{"files", ads.fileRepo.DeleteByUserID},
+		{"directories", ads.directoryRepo.DeleteByUserID},
+		{"service_accounts", ads.serviceAccountRepo.DeleteByUserID},
+		{"personal_access_tokens", ads.personalAccessTokenRepo.DeleteByUserID},
+		{"trusted_devices", ads.trustedDeviceRepo.DeleteByUserID},
+		{"mfa_recovery_codes", ads.recoveryCodeRepo.DeleteByUserID},
+		{"webauthn_credentials", ads.webauthnCredentialRepo.DeleteByUserID},
+		{"organization_user_role", ads.organizationUserRoleRepo.DeleteByUserID},
+		{"user_role", ads.userRoleRepo.DeleteByUserID},
+		{"organization_storage", ads.quotaService.LeaveOrganizationPool},
+		{"limits", ads.limitRepo.DeleteByUserID},
+		{"settings", ads.settingRepo.Delete},
+		{"profiles", ads.profileRepo.Delete},
+		{"login_events", ads.loginEventRepo.DeleteByUserID},
+		{"data_exports", ads.dataExportRepo.DeleteByUserID},
+	}
+	cleaned := []string{}
+	for _, step := range steps {
+		if err := step.delete(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", step.name, err)
+		}
+		cleaned = append(cleaned, step.name)
+	}
+
+	if err := ads.userRepo.Delete(ctx, user.Email); err != nil {
+		return err
+	}
+
+	// The entry keeps no personal data beyond the ID of the erased user
+	recordAudit(ctx, ads.auditLogRepo, "account_deleted", user.ID, nil, bson.M{
+		"requested_delete_at": user.DeletionScheduledAt,
+		"service_accounts":    len(serviceAccounts),
+		"collections":         cleaned,
+	})
+
+	if err := ads.emailService.SendAccountDeleted(user.Email); err != nil {
+		logger.Log.Error("failed to send account deleted email", logger.Error(err))
+	}
+	return nil
+}
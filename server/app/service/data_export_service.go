@@ -0,0 +1,383 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DataExportService builds archives of a user's personal data in the background
+type DataExportService struct {
+	dataExportRepo *repository.DataExportRepository
+	userRepo       *repository.UserRepository
+	profileRepo    *repository.ProfileRepository
+	settingRepo    *repository.SettingRepository
+	directoryRepo  *repository.DirectoryRepository
+	fileRepo       *repository.FileRepository
+	fileAccessRepo *repository.FileAccessRepository
+	auditLogRepo   *repository.AuditLogRepository
+	ipfsService    *IPFSService
+	emailService   *EmailService
+}
+
+// NewDataExportService initializes a new DataExportService
+func NewDataExportService(
+	dataExportRepo *repository.DataExportRepository,
+	userRepo *repository.UserRepository,
+	profileRepo *repository.ProfileRepository,
+	settingRepo *repository.SettingRepository,
+	directoryRepo *repository.DirectoryRepository,
+	fileRepo *repository.FileRepository,
+	fileAccessRepo *repository.FileAccessRepository,
+	auditLogRepo *repository.AuditLogRepository,
+	ipfsService *IPFSService,
+	emailService *EmailService,
+) *DataExportService {
+	return &DataExportService{
+		dataExportRepo: dataExportRepo,
+		userRepo:       userRepo,
+		profileRepo:    profileRepo,
+		settingRepo:    settingRepo,
+		directoryRepo:  directoryRepo,
+		fileRepo:       fileRepo,
+		fileAccessRepo: fileAccessRepo,
+		auditLogRepo:   auditLogRepo,
+		ipfsService:    ipfsService,
+		emailService:   emailService,
+	}
+}
+
+// RequestExport queues an export, the user is emailed a download link once it is built
+func (des *DataExportService) RequestExport(ctx context.Context, userID string, includeFiles bool) (*model.DataExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	active, err := des.dataExportRepo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to check data exports")
+	}
+	if active > 0 {
+		return nil, errors.New("data export already in progress")
+	}
+
+	export := &model.DataExport{
+		UserID:       objectID,
+		Status:       "pending",
+		IncludeFiles: includeFiles,
+	}
+	if err := des.dataExportRepo.Create(ctx, export); err != nil {
+		return nil, errors.New("failed to create data export")
+	}
+
+	recordAudit(ctx, des.auditLogRepo, "data_export_requested", objectID, &objectID, bson.M{
+		"export_id":     export.ID.Hex(),
+		"include_files": includeFiles,
+	})
+
+	return export, nil
+}
+
+// ListExports returns the user's exports, newest first
+func (des *DataExportService) ListExports(ctx context.Context, userID string) ([]model.DataExport, error) {
+	exports, err := des.dataExportRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to list data exports")
+	}
+	return exports, nil
+}
+
+// DownloadURL returns the signed link of an export, it works until the export expires
+func (des *DataExportService) DownloadURL(export *model.DataExport) string {
+	return config.LoadAppConfig().AppURL + "/public/exports/download?token=" + url.QueryEscape(helper.SignToken(export.ID.Hex()))
+}
+
+// DownloadExport resolves a signed link to the export archive's content
+func (des *DataExportService) DownloadExport(ctx context.Context, signedToken string) (*model.DataExport, []byte, error) {
+	exportID, ok := helper.VerifySignedToken(signedToken)
+	if !ok {
+		return nil, nil, errors.New("invalid or expired download link")
+	}
+
+	export, err := des.dataExportRepo.Read(ctx, exportID)
+	if err != nil || export == nil || !export.IsDownloadable() {
+		return nil, nil, errors.New("invalid or expired download link")
+	}
+
+	content, err := des.ipfsService.DownloadFile(export.Hash)
+	if err != nil {
+		logger.Log.Error("failed to download data export", logger.Error(err))
+		return nil, nil, errors.New("failed to download data export")
+	}
+	return export, content, nil
+}
+
+// ProcessPendingExports builds every queued export, one at a time
+func (des *DataExportService) ProcessPendingExports(ctx context.Context) error {
+	accountConfig := config.LoadAccountConfig()
+
+	for {
+		export, err := des.dataExportRepo.ClaimPending(ctx)
+		if err != nil {
+			return err
+		}
+		if export == nil {
+			return nil
+		}
+
+		hash, size, err := des.buildArchive(ctx, export)
+		if err != nil {
+			logger.Log.Error("failed to build data export", logger.Error(err))
+			if err := des.dataExportRepo.Update(ctx, export.ID, bson.M{"status": "failed", "error": err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(accountConfig.ExportExpiry)
+		update := bson.M{
+			"status":       "ready",
+			"hash":         hash,
+			"size":         size,
+			"expires_at":   expiresAt,
+			"completed_at": now,
+		}
+		if err := des.dataExportRepo.Update(ctx, export.ID, update); err != nil {
+			return err
+		}
+		export.ExpiresAt = &expiresAt
+
+		user, err := des.userRepo.Read(ctx, export.UserID.Hex())
+		if err != nil || user == nil {
+			continue
+		}
+		if err := des.emailService.SendDataExportReady(user.Email, des.DownloadURL(export), expiresAt); err != nil {
+			logger.Log.Error("failed to send data export email", logger.Error(err))
+		}
+	}
+}
+
+// buildArchive writes the user's data to a zip archive and stores it on IPFS
+func (des *DataExportService) buildArchive(ctx context.Context, export *model.DataExport) (string, int64, error) {
+	accountConfig := config.LoadAccountConfig()
+	userID := export.UserID.Hex()
+
+	user, err := des.userRepo.Read(ctx, userID)
+	if err != nil || user == nil {
+		return "", 0, errors.New("user not found")
+	}
+	profile, err := des.profileRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		return "", 0, errors.New("failed to read profile")
+	}
+	setting, err := des.settingRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		return "", 0, errors.New("failed to read settings")
+	}
+	directories, err := des.directoryRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return "", 0, errors.New("failed to read directories")
+	}
+	files, err := des.fileRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return "", 0, errors.New("failed to read files")
+	}
+	shares, err := des.fileAccessRepo.ListByOwnerID(ctx, userID)
+	if err != nil {
+		return "", 0, errors.New("failed to read shares")
+	}
+
+	// Paths inside the archive follow the directory tree
+	directoriesByID := map[primitive.ObjectID]*model.Directory{}
+	for _, directory := range directories {
+		directoriesByID[directory.ID] = directory
+	}
+	directoryPaths := map[primitive.ObjectID]string{}
+	var directoryPath func(directory *model.Directory, depth int) string
+	directoryPath = func(directory *model.Directory, depth int) string {
+		if p, exists := directoryPaths[directory.ID]; exists {
+			return p
+		}
+		p := ""
+		if parent, exists := directoriesByID[derefObjectID(directory.DirectoryID)]; exists && depth < maxPolicyDirectoryDepth {
+			p = path.Join(directoryPath(parent, depth+1), directory.Name)
+		}
+		directoryPaths[directory.ID] = p
+		return p
+	}
+
+	directoryEntries := []bson.M{}
+	for _, directory := range directories {
+		if directory.IsDeleted {
+			continue
+		}
+		directoryEntries = append(directoryEntries, bson.M{
+			"id":         directory.ID.Hex(),
+			"path":       "/" + directoryPath(directory, 0),
+			"size":       directory.Size,
+			"created_at": directory.CreatedAt,
+			"updated_at": directory.UpdatedAt,
+		})
+	}
+
+	var contentBytes int64
+	fileEntries := []bson.M{}
+	for _, file := range files {
+		if file.IsDeleted {
+			continue
+		}
+		filePath := file.Name
+		if directory, exists := directoriesByID[derefObjectID(file.DirectoryID)]; exists {
+			filePath = path.Join(directoryPath(directory, 0), file.Name)
+		}
+		fileEntries = append(fileEntries, bson.M{
+			"id":           file.ID.Hex(),
+			"path":         "/" + filePath,
+			"hash":         file.Hash,
+			"size":         file.Size,
+			"content_type": file.ContentType,
+			"access":       file.Access,
+			"is_encrypted": file.IsEncrypted,
+			"created_at":   file.CreatedAt,
+			"updated_at":   file.UpdatedAt,
+		})
+		contentBytes += file.Size
+	}
+	if export.IncludeFiles && contentBytes > accountConfig.ExportMaxContentBytes {
+		return "", 0, errors.New("file contents exceed the export limit, request an export without them")
+	}
+
+	shareEntries := []bson.M{}
+	for _, share := range shares {
+		entry := bson.M{
+			"file_id":      share.FileID.Hex(),
+			"has_password": share.Password != nil,
+			"created_at":   share.CreatedAt,
+		}
+		if share.RecipientID != nil {
+			if recipient, err := des.userRepo.Read(ctx, share.RecipientID.Hex()); err == nil && recipient != nil {
+				entry["recipient_email"] = recipient.Email
+			}
+		}
+		shareEntries = append(shareEntries, entry)
+	}
+
+	// The archive is written to disk so file contents don't have to fit in memory
+	archive, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return "", 0, errors.New("failed to create archive")
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	writer := zip.NewWriter(archive)
+	documents := []struct {
+		name  string
+		value any
+	}{
+		{"account.json", bson.M{
+			"id":          user.ID.Hex(),
+			"email":       user.Email,
+			"is_verified": user.IsVerified,
+			"created_at":  user.CreatedAt,
+			"updated_at":  user.UpdatedAt,
+		}},
+		{"profile.json", profile},
+		{"settings.json", setting},
+		{"directories.json", directoryEntries},
+		{"files.json", fileEntries},
+		{"shares.json", shareEntries},
+	}
+	for _, document := range documents {
+		entry, err := writer.Create(document.name)
+		if err != nil {
+			return "", 0, errors.New("failed to write archive")
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(document.value); err != nil {
+			return "", 0, errors.New("failed to write archive")
+		}
+	}
+
+	// Encrypted files are exported as stored, they still need the passphrase to be read
+	if export.IncludeFiles {
+		for _, entry := range fileEntries {
+			content, err := des.ipfsService.DownloadFile(entry["hash"].(string))
+			if err != nil {
+				return "", 0, fmt.Errorf("failed to download file %s", entry["id"])
+			}
+			name := "files" + entry["path"].(string)
+			if entry["is_encrypted"].(bool) {
+				name += ".encrypted"
+			}
+			fileWriter, err := writer.Create(name)
+			if err != nil {
+				return "", 0, errors.New("failed to write archive")
+			}
+			if _, err := fileWriter.Write(content); err != nil {
+				return "", 0, errors.New("failed to write archive")
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", 0, errors.New("failed to write archive")
+	}
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, errors.New("failed to write archive")
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return "", 0, errors.New("failed to write archive")
+	}
+
+	hash, err := des.ipfsService.UploadFile(fmt.Sprintf("export-%s.zip", export.ID.Hex()), archive)
+	if err != nil {
+		logger.Log.Error("failed to store data export", logger.Error(err))
+		return "", 0, errors.New("failed to store archive")
+	}
+	return hash, size, nil
+}
+
+// derefObjectID returns the ID or the zero ID when unset
+func derefObjectID(id *primitive.ObjectID) primitive.ObjectID {
+	if id == nil {
+		return primitive.NilObjectID
+	}
+	return *id
+}
+
+// recordAudit stores an audit entry with the caller's address, a failure is logged and never blocks the action
+func recordAudit(ctx context.Context, auditLogRepo *repository.AuditLogRepository, action string, userID primitive.ObjectID, actorID *primitive.ObjectID, details bson.M) {
+	ip, userAgent := helper.ClientInfo(ctx)
+	entry := &model.AuditLog{
+		Action:    action,
+		UserID:    userID,
+		ActorID:   actorID,
+		IPAddress: ip,
+		UserAgent: userAgent,
+		Details:   details,
+	}
+	if err := auditLogRepo.Create(ctx, entry); err != nil {
+		logger.Log.Error("failed to record audit log", logger.Error(err))
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EmailChangeService changes a user's login email once the new address is confirmed,
+// the old address is told about the change and can cancel it
+type EmailChangeService struct {
+	userRepo      *repository.UserRepository
+	emailService  *EmailService
+	jwtProvider   *provider.JWTProvider
+	redisProvider *provider.RedisProvider
+}
+
+// pendingEmailChange is the change stored in Redis until it is confirmed, cancelled or expires
+type pendingEmailChange struct {
+	NewEmail        string `json:"new_email"`
+	CodeHash        string `json:"code_hash"`
+	CancelTokenHash string `json:"cancel_token_hash"`
+}
+
+// NewEmailChangeService initializes a new EmailChangeService
+func NewEmailChangeService(userRepo *repository.UserRepository, emailService *EmailService, jwtProvider *provider.JWTProvider, redisProvider *provider.RedisProvider) *EmailChangeService {
+	return &EmailChangeService{
+		userRepo:      userRepo,
+		emailService:  emailService,
+		jwtProvider:   jwtProvider,
+		redisProvider: redisProvider,
+	}
+}
+
+// RequestEmailChange emails a confirmation code to the new address and a cancel link to the current one.
+// A new request replaces a pending one.
+func (ecs *EmailChangeService) RequestEmailChange(ctx context.Context, userID, newEmail, password string) error {
+	emailChangeConfig := config.LoadEmailChangeConfig()
+
+	user, err := ecs.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("failed to retrieve user", logger.Error(err))
+		return errors.New("failed to retrieve user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	// The password is asked again so an unattended session can't take over the account
+	if !helper.CheckHash(password, user.Password) {
+		return errors.New("password is incorrect")
+	}
+	if newEmail == user.Email {
+		return errors.New("new email must be different from the current email")
+	}
+
+	existing, err := ecs.userRepo.ReadByEmail(ctx, newEmail)
+	if err != nil {
+		return errors.New("failed to check email")
+	}
+	if existing != nil {
+		return errors.New("email already in use")
+	}
+
+	code, err := helper.GenerateCode(3)
+	if err != nil {
+		return errors.New("failed to generate confirmation code")
+	}
+	cancelToken, err := helper.GenerateCode(32)
+	if err != nil {
+		return errors.New("failed to generate cancel token")
+	}
+
+	// Drop the cancel link of a change this one replaces
+	if previous, err := ecs.readPending(ctx, userID); err == nil && previous != nil {
+		if err := ecs.redisProvider.Del(ctx, fmt.Sprintf("email_change_cancel:%s", previous.CancelTokenHash)); err != nil {
+			logger.Log.Error("failed to delete cancel token", logger.Error(err))
+		}
+	}
+
+	stored, err := json.Marshal(pendingEmailChange{
+		NewEmail:        newEmail,
+		CodeHash:        helper.HashToken(code),
+		CancelTokenHash: helper.HashToken(cancelToken),
+	})
+	if err != nil {
+		return errors.New("failed to store email change")
+	}
+	if err := ecs.redisProvider.Set(ctx, fmt.Sprintf("email_change:%s", userID), string(stored), emailChangeConfig.CodeExpiry); err != nil {
+		return errors.New("failed to store email change")
+	}
+	if err := ecs.redisProvider.Set(ctx, fmt.Sprintf("email_change_cancel:%s", helper.HashToken(cancelToken)), userID, emailChangeConfig.CodeExpiry); err != nil {
+		return errors.New("failed to store email change")
+	}
+	if err := ecs.redisProvider.Del(ctx, fmt.Sprintf("email_change_attempts:%s", userID)); err != nil {
+		return errors.New("failed to store email change")
+	}
+
+	if err := ecs.emailService.SendEmailChangeCode(newEmail, code, emailChangeConfig.CodeExpiry); err != nil {
+		logger.Log.Error("failed to send email change code", logger.Error(err))
+		return errors.New("failed to send confirmation code")
+	}
+	if err := ecs.emailService.SendEmailChangeNotice(user.Email, newEmail, cancelToken); err != nil {
+		logger.Log.Error("failed to send email change notice", logger.Error(err))
+		return errors.New("failed to send email change notice")
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange swaps the login email once the code sent to the new address is entered,
+// then ends every session of the user
+func (ecs *EmailChangeService) ConfirmEmailChange(ctx context.Context, userID, code string) (string, error) {
+	emailChangeConfig := config.LoadEmailChangeConfig()
+
+	pending, err := ecs.readPending(ctx, userID)
+	if err != nil {
+		return "", errors.New("failed to retrieve email change")
+	}
+	if pending == nil {
+		return "", errors.New("no pending email change")
+	}
+
+	// Too many wrong codes drop the change, a new one has to be requested
+	if subtle.ConstantTimeCompare([]byte(helper.HashToken(code)), []byte(pending.CodeHash)) != 1 {
+		attempts, err := ecs.redisProvider.Incr(ctx, fmt.Sprintf("email_change_attempts:%s", userID), emailChangeConfig.CodeExpiry)
+		if err == nil && attempts >= int64(emailChangeConfig.MaxAttempts) {
+			ecs.clearPending(ctx, userID, pending)
+		}
+		return "", errors.New("invalid confirmation code")
+	}
+
+	// The unique_email index settles a race with a registration of the same address
+	if err := ecs.userRepo.Update(ctx, userID, bson.M{"email": pending.NewEmail}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			ecs.clearPending(ctx, userID, pending)
+			return "", errors.New("email already in use")
+		}
+		return "", errors.New("failed to update email")
+	}
+	ecs.clearPending(ctx, userID, pending)
+
+	if err := ecs.jwtProvider.RevokeUserTokens(userID); err != nil {
+		logger.Log.Error("failed to revoke sessions", logger.Error(err))
+		return "", errors.New("failed to revoke sessions")
+	}
+
+	return pending.NewEmail, nil
+}
+
+// CancelEmailChange drops a pending change using the link sent to the current address
+func (ecs *EmailChangeService) CancelEmailChange(ctx context.Context, token string) error {
+	userID, err := ecs.redisProvider.Get(ctx, fmt.Sprintf("email_change_cancel:%s", helper.HashToken(token)))
+	if err != nil || userID == "" {
+		return errors.New("invalid or expired cancel token")
+	}
+
+	pending, err := ecs.readPending(ctx, userID)
+	if err != nil {
+		return errors.New("failed to retrieve email change")
+	}
+	if pending == nil || pending.CancelTokenHash != helper.HashToken(token) {
+		return errors.New("invalid or expired cancel token")
+	}
+
+	ecs.clearPending(ctx, userID, pending)
+	return nil
+}
+
+// readPending loads the user's pending change, nil when there is none
+func (ecs *EmailChangeService) readPending(ctx context.Context, userID string) (*pendingEmailChange, error) {
+	stored, err := ecs.redisProvider.Get(ctx, fmt.Sprintf("email_change:%s", userID))
+	if err != nil || stored == "" {
+		return nil, nil
+	}
+
+	var pending pendingEmailChange
+	if err := json.Unmarshal([]byte(stored), &pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// clearPending deletes the pending change with its cancel link and attempt counter
+func (ecs *EmailChangeService) clearPending(ctx context.Context, userID string, pending *pendingEmailChange) {
+	keys := []string{
+		fmt.Sprintf("email_change:%s", userID),
+		fmt.Sprintf("email_change_cancel:%s", pending.CancelTokenHash),
+		fmt.Sprintf("email_change_attempts:%s", userID),
+	}
+	for _, key := range keys {
+		if err := ecs.redisProvider.Del(ctx, key); err != nil {
+			logger.Log.Error("failed to clear email change", logger.Error(err))
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package service
 
-import "bongaquino/server/app/provider"
+import (
+	"html"
+	"strconv"
+	"time"
+
+	"bongaquino/server/app/provider"
+	"bongaquino/server/config"
+)
 
 type EmailService struct {
 	postmarkProvider *provider.PostmarkProvider
@@ -34,3 +41,105 @@ func (es *EmailService) SendFileShareNotification(to, fileName string) error {
 	body := "<h1>File Shared</h1><p>A file named '" + fileName + "' has been shared with you.</p>"
 	return es.postmarkProvider.SendEmail(to, subject, body)
 }
+
+func (es *EmailService) SendOrganizationInvitation(to, orgName, token string) error {
+	link := config.LoadAppConfig().FrontendURL + "/invitations/accept?token=" + token
+	subject := "You're invited to join " + orgName
+	body := "<h1>Organization Invitation</h1><p>You have been invited to join '" + orgName + "'.</p>" +
+		"<p><a href=\"" + link + "\">Accept the invitation</a></p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendRecoveryCodeUsedAlert(to string, remaining int64) error {
+	subject := "A recovery code was used to sign in"
+	body := "<h1>Recovery Code Used</h1><p>A recovery code was just used to sign in to your account. " +
+		"You have " + strconv.FormatInt(remaining, 10) + " recovery codes left.</p>" +
+		"<p>If this wasn't you, change your password and regenerate your recovery codes right away.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendAccountLockedEmail(to, ip string, lockedUntil time.Time, token string) error {
+	link := config.LoadAppConfig().FrontendURL + "/unlock-account?token=" + token
+	subject := "Sign-ins to your account have been paused"
+	body := "<h1>Sign-Ins Paused</h1><p>Sign-ins to your account from IP address " + html.EscapeString(ip) +
+		" were paused after several failed attempts. Other devices and networks are not affected. " +
+		"The pause lifts automatically at " + lockedUntil.UTC().Format("2006-01-02 15:04 MST") + ".</p>" +
+		"<p>If this was you, <a href=\"" + link + "\">unlock sign-ins now</a>. " +
+		"If it wasn't, consider changing your password.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendMagicLink(to, token string, expiry time.Duration) error {
+	link := config.LoadAppConfig().FrontendURL + "/magic-link?token=" + token
+	subject := "Your sign-in link"
+	body := "<h1>Sign In</h1><p><a href=\"" + link + "\">Sign in to your account</a>. " +
+		"The link works once and expires in " + strconv.Itoa(int(expiry.Minutes())) + " minutes.</p>" +
+		"<p>If you didn't ask for it, you can ignore this email.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendNewDeviceAlert(to, ip, userAgent string, at time.Time) error {
+	subject := "New sign-in to your account"
+	body := "<h1>New Sign-In</h1><p>Your account was signed in to from a device or network we haven't seen before.</p>" +
+		"<p>Time: " + at.UTC().Format("2006-01-02 15:04 MST") + "<br>IP address: " + html.EscapeString(ip) +
+		"<br>Device: " + html.EscapeString(userAgent) + "</p>" +
+		"<p>If this wasn't you, change your password and review your login history.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendServiceAccountSecretExpiring(to, name, clientID string, expiresAt time.Time) error {
+	subject := "A service account secret is about to expire"
+	body := "<h1>Service Account Secret Expiring</h1><p>A secret of the service account <strong>" + html.EscapeString(name) +
+		"</strong> (" + html.EscapeString(clientID) + ") expires on " + expiresAt.UTC().Format("2006-01-02 15:04 MST") + ".</p>" +
+		"<p>Rotate the secret and deploy the new one before then, agents still using the old secret will stop working.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendServiceAccountDisabled(to, name, clientID string, inactivity time.Duration) error {
+	subject := "A service account was disabled"
+	body := "<h1>Service Account Disabled</h1><p>The service account <strong>" + html.EscapeString(name) +
+		"</strong> (" + html.EscapeString(clientID) + ") was disabled because it has not been used in " +
+		strconv.Itoa(int(inactivity.Hours()/24)) + " days.</p>" +
+		"<p>Rotate its secret to enable it again, or revoke it if it is no longer needed.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendEmailChangeCode(to, code string, expiry time.Duration) error {
+	subject := "Confirm your new email address"
+	body := "<h1>Confirm Email Change</h1><p>Your confirmation code is: " + code + "</p>" +
+		"<p>Enter it within " + strconv.Itoa(int(expiry.Minutes())) + " minutes to start signing in with this address. " +
+		"If you didn't ask for it, you can ignore this email.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendEmailChangeNotice(to, newEmail, token string) error {
+	link := config.LoadAppConfig().FrontendURL + "/cancel-email-change?token=" + token
+	subject := "Your login email is being changed"
+	body := "<h1>Email Change Requested</h1><p>A change of your login email to <strong>" + html.EscapeString(newEmail) +
+		"</strong> was requested. It takes effect once confirmed from the new address.</p>" +
+		"<p>If this wasn't you, <a href=\"" + link + "\">cancel the change</a> and change your password.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendDataExportReady(to, link string, expiresAt time.Time) error {
+	subject := "Your data export is ready"
+	body := "<h1>Data Export Ready</h1><p>The copy of your data you asked for is ready. " +
+		"<a href=\"" + link + "\">Download it</a> before " + expiresAt.UTC().Format("2006-01-02 15:04 MST") + ".</p>" +
+		"<p>Anyone with the link can download the archive, so don't share it.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendAccountDeletionScheduled(to string, deleteAt time.Time) error {
+	link := config.LoadAppConfig().FrontendURL + "/settings/account"
+	subject := "Your account is scheduled for deletion"
+	body := "<h1>Account Deletion Scheduled</h1><p>Your account and all of its files will be permanently deleted on " +
+		deleteAt.UTC().Format("2006-01-02 15:04 MST") + ".</p>" +
+		"<p>Changed your mind? <a href=\"" + link + "\">Sign in and cancel the deletion</a> before then.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
+
+func (es *EmailService) SendAccountDeleted(to string) error {
+	subject := "Your account has been deleted"
+	body := "<h1>Account Deleted</h1><p>Your account and all of its files have been permanently deleted as you asked.</p>"
+	return es.postmarkProvider.SendEmail(to, subject, body)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LockoutService counts failed logins and locks addresses out of accounts or throttles addresses that keep failing
+type LockoutService struct {
+	userRepo      *repository.UserRepository
+	emailService  *EmailService
+	redisProvider *provider.RedisProvider
+}
+
+// NewLockoutService initializes a new LockoutService
+func NewLockoutService(userRepo *repository.UserRepository, emailService *EmailService, redisProvider *provider.RedisProvider) *LockoutService {
+	return &LockoutService{
+		userRepo:      userRepo,
+		emailService:  emailService,
+		redisProvider: redisProvider,
+	}
+}
+
+// CheckIP rejects an address that used up its failed login budget
+func (ls *LockoutService) CheckIP(ctx context.Context, ip string) error {
+	lockoutConfig := config.LoadLockoutConfig()
+
+	attemptsStr, err := ls.redisProvider.Get(ctx, fmt.Sprintf("failed_login_ip:%s", ip))
+	if err != nil {
+		return nil
+	}
+	attempts, err := strconv.ParseInt(attemptsStr, 10, 64)
+	if err == nil && attempts >= lockoutConfig.IPMaxAttempts {
+		return errors.New("too many login attempts, try again later")
+	}
+	return nil
+}
+
+// CheckAddress rejects an address that is locked out of the account after failing too often, other addresses
+// including the owner's can still sign in
+func (ls *LockoutService) CheckAddress(ctx context.Context, user *model.User, ip string) error {
+	locked, err := ls.redisProvider.Get(ctx, addressLockKey(user, ip))
+	if err != nil || locked == "" {
+		return nil
+	}
+	return errors.New("too many login attempts, try again later")
+}
+
+// RecordFailure counts a failed login against the address and the account, locking the address out of the account
+// once it reaches the limit. The account itself is never locked, so guessing from one address cannot lock out the owner.
+func (ls *LockoutService) RecordFailure(ctx context.Context, user *model.User, ip string) error {
+	lockoutConfig := config.LoadLockoutConfig()
+
+	if _, err := ls.redisProvider.Incr(ctx, fmt.Sprintf("failed_login_ip:%s", ip), lockoutConfig.IPWindow); err != nil {
+		return fmt.Errorf("failed to increment failed attempts: %w", err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	attempts, err := ls.redisProvider.Incr(ctx, failedAttemptsKey(user, ip), lockoutConfig.AttemptWindow)
+	if err != nil {
+		return fmt.Errorf("failed to increment failed attempts: %w", err)
+	}
+	if attempts < lockoutConfig.MaxAttempts {
+		return nil
+	}
+
+	return ls.lock(ctx, user, ip)
+}
+
+// ClearFailures resets the failed attempts from the address after a successful login and lifts a lapsed lockout
+func (ls *LockoutService) ClearFailures(ctx context.Context, user *model.User, ip string) error {
+	err := ls.redisProvider.Del(ctx, failedAttemptsKey(user, ip))
+	if err != nil {
+		return fmt.Errorf("failed to reset failed attempts: %w", err)
+	}
+	if err := ls.redisProvider.Del(ctx, lockoutCountKey(user, ip)); err != nil {
+		return fmt.Errorf("failed to reset lockout count: %w", err)
+	}
+
+	// Accounts locked by earlier releases carry their lock on the user
+	if user.IsLocked || user.LockoutCount > 0 {
+		if err := ls.userRepo.Update(ctx, user.ID.Hex(), unlockUpdate()); err != nil {
+			return fmt.Errorf("failed to update user lock status: %w", err)
+		}
+	}
+	return nil
+}
+
+// Unlock lifts any lock on the account and forgets its failed attempts
+func (ls *LockoutService) Unlock(ctx context.Context, userID string) error {
+	user, err := ls.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return errors.New("error fetching user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	// Address lockouts and counters lapse on their own
+	if err := ls.userRepo.Update(ctx, userID, unlockUpdate()); err != nil {
+		return errors.New("failed to update user lock status")
+	}
+	return nil
+}
+
+// UnlockWithToken lifts the address lockout named by the link in the lockout email
+func (ls *LockoutService) UnlockWithToken(ctx context.Context, token string) error {
+	key := fmt.Sprintf("unlock_token:%s", helper.HashToken(token))
+
+	stored, err := ls.redisProvider.Get(ctx, key)
+	userID, ip, found := strings.Cut(stored, ":")
+	if err != nil || !found {
+		return errors.New("invalid or expired unlock token")
+	}
+
+	user, err := ls.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return errors.New("error fetching user")
+	}
+	if user == nil {
+		return errors.New("invalid or expired unlock token")
+	}
+
+	// Locks placed by an admin are not lifted by the emailed link
+	if user.IsLocked && user.LockedUntil == nil {
+		return errors.New("account locked by an administrator")
+	}
+
+	if err := ls.redisProvider.Del(ctx, key); err != nil {
+		return errors.New("failed to delete unlock token")
+	}
+	if err := ls.redisProvider.Del(ctx, addressLockKey(user, ip)); err != nil {
+		return errors.New("failed to lift address lockout")
+	}
+	if err := ls.redisProvider.Del(ctx, lockoutCountKey(user, ip)); err != nil {
+		return errors.New("failed to reset lockout count")
+	}
+	return nil
+}
+
+// lock locks the address out of the account for a period that doubles with each consecutive lockout and emails
+// the owner a link to lift it
+func (ls *LockoutService) lock(ctx context.Context, user *model.User, ip string) error {
+	lockoutConfig := config.LoadLockoutConfig()
+
+	lockouts, err := ls.redisProvider.Incr(ctx, lockoutCountKey(user, ip), lockoutConfig.EscalationWindow)
+	if err != nil {
+		return fmt.Errorf("failed to increment lockout count: %w", err)
+	}
+
+	duration := lockoutConfig.BaseDuration
+	for i := int64(1); i < lockouts && duration < lockoutConfig.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > lockoutConfig.MaxDuration {
+		duration = lockoutConfig.MaxDuration
+	}
+	lockedUntil := time.Now().Add(duration)
+
+	if err := ls.redisProvider.Set(ctx, addressLockKey(user, ip), "1", duration); err != nil {
+		return fmt.Errorf("failed to lock address: %w", err)
+	}
+	if err := ls.redisProvider.Del(ctx, failedAttemptsKey(user, ip)); err != nil {
+		return fmt.Errorf("failed to reset failed attempts: %w", err)
+	}
+
+	// The email is best effort, the lockout lapses on its own either way
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		logger.Log.Error("failed to generate unlock token", logger.Error(err))
+	} else if err := ls.redisProvider.Set(ctx, fmt.Sprintf("unlock_token:%s", helper.HashToken(token)), user.ID.Hex()+":"+ip, lockoutConfig.UnlockTokenExpiry); err != nil {
+		logger.Log.Error("failed to store unlock token", logger.Error(err))
+	} else if err := ls.emailService.SendAccountLockedEmail(user.Email, ip, lockedUntil, token); err != nil {
+		logger.Log.Error("failed to send account locked email", logger.Error(err))
+	}
+
+	return errors.New("too many login attempts, try again later")
+}
+
+// failedAttemptsKey counts the account's failed logins from one address
+func failedAttemptsKey(user *model.User, ip string) string {
+	return fmt.Sprintf("failed_login_attempts:%s:%s", user.Email, ip)
+}
+
+// addressLockKey marks an address as locked out of the account until it expires
+func addressLockKey(user *model.User, ip string) string {
+	return fmt.Sprintf("login_lock:%s:%s", user.Email, ip)
+}
+
+// lockoutCountKey counts the lockouts one address caused on the account
+func lockoutCountKey(user *model.User, ip string) string {
+	return fmt.Sprintf("lockout_count:%s:%s", user.Email, ip)
+}
+
+// unlockUpdate clears every lock field of a user
+func unlockUpdate() bson.M {
+	return bson.M{
+		"is_locked":     false,
+		"locked_until":  nil,
+		"lockout_count": 0,
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginEventService keeps the login history and alerts users about sign-ins from new devices
+type LoginEventService struct {
+	loginEventRepo *repository.LoginEventRepository
+	userRepo       *repository.UserRepository
+	emailService   *EmailService
+}
+
+// NewLoginEventService initializes a new LoginEventService
+func NewLoginEventService(loginEventRepo *repository.LoginEventRepository, userRepo *repository.UserRepository, emailService *EmailService) *LoginEventService {
+	return &LoginEventService{
+		loginEventRepo: loginEventRepo,
+		userRepo:       userRepo,
+		emailService:   emailService,
+	}
+}
+
+// Record stores an authentication attempt, recording never fails the login itself
+func (les *LoginEventService) Record(ctx context.Context, method string, userID *primitive.ObjectID, identifier string, authErr error) {
+	ip, userAgent := helper.ClientInfo(ctx)
+	event := &model.LoginEvent{
+		UserID:      userID,
+		Identifier:  identifier,
+		Method:      method,
+		Success:     authErr == nil,
+		IPAddress:   ip,
+		UserAgent:   userAgent,
+		Fingerprint: fingerprint(ip, userAgent),
+	}
+	if authErr != nil {
+		event.FailureReason = authErr.Error()
+	}
+
+	// Users are alerted about interactive sign-ins from an unseen device, but not on their very first one
+	alert := false
+	if event.Success && userID != nil && method != "refresh" && method != "service_account" {
+		seen, err := les.loginEventRepo.CountSuccessfulByUserID(ctx, *userID, event.Fingerprint)
+		if err == nil && seen == 0 {
+			total, err := les.loginEventRepo.CountSuccessfulByUserID(ctx, *userID, "")
+			alert = err == nil && total > 0
+		}
+	}
+
+	if err := les.loginEventRepo.Create(ctx, event); err != nil {
+		logger.Log.Error("failed to record login event", logger.Error(err))
+		return
+	}
+
+	if alert {
+		user, err := les.userRepo.Read(ctx, userID.Hex())
+		if err != nil || user == nil {
+			return
+		}
+		if err := les.emailService.SendNewDeviceAlert(user.Email, ip, userAgent, event.CreatedAt); err != nil {
+			logger.Log.Error("failed to send new device alert", logger.Error(err))
+		}
+	}
+}
+
+// ListLoginHistory returns the user's login events, newest first
+func (les *LoginEventService) ListLoginHistory(ctx context.Context, userID string, page, limit int) ([]model.LoginEvent, error) {
+	events, err := les.loginEventRepo.ListByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch login history")
+	}
+	return events, nil
+}
+
+// fingerprint identifies a device by its user agent and network, the /24 for IPv4 or /48 for IPv6, so address churn
+// within one network does not count as a new device
+func fingerprint(ip, userAgent string) string {
+	network := ip
+	if parsed := net.ParseIP(ip); parsed != nil {
+		if v4 := parsed.To4(); v4 != nil {
+			network = v4.Mask(net.CIDRMask(24, 32)).String()
+		} else {
+			network = parsed.Mask(net.CIDRMask(48, 128)).String()
+		}
+	}
+	return helper.HashToken(network + "|" + userAgent)
+}
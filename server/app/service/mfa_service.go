@@ -3,28 +3,49 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"bongaquino/server/app/dto"
 	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
 	"bongaquino/server/app/provider"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MFAService handles MFA-related operations
 type MFAService struct {
-	userRepo      *repository.UserRepository
-	settingRepo   *repository.SettingRepository
-	redisProvider *provider.RedisProvider
+	userRepo          *repository.UserRepository
+	settingRepo       *repository.SettingRepository
+	recoveryCodeRepo  *repository.MFARecoveryCodeRepository
+	trustedDeviceRepo *repository.TrustedDeviceRepository
+	emailService      *EmailService
+	webauthnService   *WebAuthnService
+	redisProvider     *provider.RedisProvider
+}
+
+// LoginFactor is the second factor presented for a pending login, exactly one field is expected
+type LoginFactor struct {
+	OTP          string
+	RecoveryCode string
+	Passkey      *dto.WebAuthnAssertionDTO
 }
 
 // NewMFAService initializes a new MFAService
-func NewMFAService(userRepo *repository.UserRepository, settingRepo *repository.SettingRepository, redisProvider *provider.RedisProvider) *MFAService {
+func NewMFAService(userRepo *repository.UserRepository, settingRepo *repository.SettingRepository, recoveryCodeRepo *repository.MFARecoveryCodeRepository, trustedDeviceRepo *repository.TrustedDeviceRepository, emailService *EmailService, webauthnService *WebAuthnService, redisProvider *provider.RedisProvider) *MFAService {
 	return &MFAService{
-		userRepo:      userRepo,
-		settingRepo:   settingRepo,
-		redisProvider: redisProvider,
+		userRepo:          userRepo,
+		settingRepo:       settingRepo,
+		recoveryCodeRepo:  recoveryCodeRepo,
+		trustedDeviceRepo: trustedDeviceRepo,
+		emailService:      emailService,
+		webauthnService:   webauthnService,
+		redisProvider:     redisProvider,
 	}
 }
 
@@ -83,16 +104,22 @@ func (ms *MFAService) VerifyOTP(ctx context.Context, userID, otp string) (bool,
 	return isValid, nil
 }
 
-func (ms *MFAService) EnableMFA(ctx context.Context, userID string) error {
+// EnableMFA stores the first set of recovery codes, then turns on MFA so a user never has MFA without them
+func (ms *MFAService) EnableMFA(ctx context.Context, userID string) ([]string, error) {
+	codes, err := ms.GenerateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	update := bson.M{
 		"is_mfa_enabled": true,
 	}
-	err := ms.settingRepo.UpdateByUserID(ctx, userID, update)
+	err = ms.settingRepo.UpdateByUserID(ctx, userID, update)
 	if err != nil {
-		return fmt.Errorf("failed to enable MFA: %w", err)
+		return nil, fmt.Errorf("failed to enable MFA: %w", err)
 	}
 
-	return nil
+	return codes, nil
 }
 
 func (ms *MFAService) DisableMFA(ctx context.Context, userID string) error {
@@ -112,9 +139,151 @@ func (ms *MFAService) DisableMFA(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to disable MFA: %w", err)
 	}
 
+	err = ms.recoveryCodeRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	err = ms.trustedDeviceRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke trusted devices: %w", err)
+	}
+
 	return nil
 }
 
+// GenerateRecoveryCodes replaces the user's recovery codes and returns the new ones, only their hashes are stored
+func (ms *MFAService) GenerateRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	mfaConfig := config.LoadMFAConfig()
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	codes := make([]string, mfaConfig.RecoveryCodeCount)
+	records := make([]model.MFARecoveryCode, mfaConfig.RecoveryCodeCount)
+	for i := range codes {
+		code, err := helper.GenerateCode(mfaConfig.RecoveryCodeLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		codeHash, err := helper.Hash(code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash recovery code: %w", err)
+		}
+		codes[i] = code[:len(code)/2] + "-" + code[len(code)/2:]
+		records[i] = model.MFARecoveryCode{
+			UserID:   userObjectID,
+			CodeHash: codeHash,
+		}
+	}
+
+	// Regenerating invalidates every earlier code
+	err = ms.recoveryCodeRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	err = ms.recoveryCodeRepo.CreateMany(ctx, records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes issues a fresh set of recovery codes for a user with MFA enabled
+func (ms *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	setting, err := ms.settingRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve settings: %w", err)
+	}
+	if setting == nil || !setting.IsMFAEnabled {
+		return nil, fmt.Errorf("MFA is not enabled")
+	}
+
+	return ms.GenerateRecoveryCodes(ctx, userID)
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes
+func (ms *MFAService) CountRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	count, err := ms.recoveryCodeRepo.CountUnusedByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// UseRecoveryCode consumes a recovery code and alerts the user by email
+func (ms *MFAService) UseRecoveryCode(ctx context.Context, userID, code string) (bool, error) {
+	// Accept codes with or without the dash and in any case
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	// Codes are salted, so each remaining one is compared in turn
+	unused, err := ms.recoveryCodeRepo.ListUnusedByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify recovery code: %w", err)
+	}
+	consumed := false
+	for _, recoveryCode := range unused {
+		if !helper.CheckHash(normalized, recoveryCode.CodeHash) {
+			continue
+		}
+		consumed, err = ms.recoveryCodeRepo.Consume(ctx, recoveryCode.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify recovery code: %w", err)
+		}
+		break
+	}
+	if !consumed {
+		return false, nil
+	}
+
+	user, err := ms.userRepo.Read(ctx, userID)
+	if err != nil || user == nil {
+		return true, nil
+	}
+	remaining, err := ms.recoveryCodeRepo.CountUnusedByUserID(ctx, userID)
+	if err != nil {
+		return true, nil
+	}
+	if err := ms.emailService.SendRecoveryCodeUsedAlert(user.Email, remaining); err != nil {
+		logger.Log.Error("failed to send recovery code alert", logger.Error(err))
+	}
+
+	return true, nil
+}
+
+// LoginMethods lists the second factors the user can complete a login with, none means MFA is off
+func (ms *MFAService) LoginMethods(ctx context.Context, userID string, isMFAEnabled bool) ([]string, error) {
+	methods := []string{}
+	if isMFAEnabled {
+		methods = append(methods, "totp", "recovery_code")
+	}
+
+	hasPasskeys, err := ms.webauthnService.HasCredentials(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve passkeys: %w", err)
+	}
+	if hasPasskeys {
+		methods = append(methods, "passkey")
+	}
+
+	return methods, nil
+}
+
+// BeginPasskeyLogin returns the passkey request options for a pending login
+func (ms *MFAService) BeginPasskeyLogin(ctx context.Context, loginCode string) (map[string]any, error) {
+	key := fmt.Sprintf("login_code:%s", loginCode)
+
+	userID, err := ms.redisProvider.Get(ctx, key)
+	if err != nil || userID == "" {
+		return nil, fmt.Errorf("invalid login code")
+	}
+
+	return ms.webauthnService.BeginAssertion(ctx, key, userID)
+}
+
 // Generate login code for the user
 func (ms *MFAService) GenerateLoginCode(ctx context.Context, userID string) (string, error) {
 	// Generate a login code
@@ -142,7 +311,8 @@ func (ms *MFAService) GenerateLoginCode(ctx context.Context, userID string) (str
 	return loginCode, nil
 }
 
-func (ms *MFAService) VerifyLoginCode(ctx context.Context, loginCode, otp string) (string, error) {
+// VerifyLoginCode checks the second factor for a pending login: a TOTP, a recovery code or a passkey
+func (ms *MFAService) VerifyLoginCode(ctx context.Context, loginCode string, factor LoginFactor) (string, error) {
 	// Construct the Redis key
 	key := fmt.Sprintf("login_code:%s", loginCode)
 
@@ -152,13 +322,28 @@ func (ms *MFAService) VerifyLoginCode(ctx context.Context, loginCode, otp string
 		return "", fmt.Errorf("invalid login code")
 	}
 
-	// Verify the OTP
-	isValid, err := ms.VerifyOTP(ctx, userID, otp)
-	if err != nil {
-		return "", fmt.Errorf("failed to verify OTP")
-	}
-	if !isValid {
-		return "", fmt.Errorf("invalid OTP")
+	// Verify the presented factor
+	switch {
+	case factor.Passkey != nil:
+		if _, err := ms.webauthnService.FinishAssertion(ctx, key, userID, factor.Passkey); err != nil {
+			return "", fmt.Errorf("invalid passkey")
+		}
+	case factor.RecoveryCode != "":
+		isValid, err := ms.UseRecoveryCode(ctx, userID, factor.RecoveryCode)
+		if err != nil {
+			return "", fmt.Errorf("failed to verify recovery code")
+		}
+		if !isValid {
+			return "", fmt.Errorf("invalid recovery code")
+		}
+	default:
+		isValid, err := ms.VerifyOTP(ctx, userID, factor.OTP)
+		if err != nil {
+			return "", fmt.Errorf("failed to verify OTP")
+		}
+		if !isValid {
+			return "", fmt.Errorf("invalid OTP")
+		}
 	}
 
 	// Delete the login code from Redis
@@ -169,3 +354,65 @@ func (ms *MFAService) VerifyLoginCode(ctx context.Context, loginCode, otp string
 
 	return userID, nil
 }
+
+// TrustDevice remembers the caller's browser after a completed second factor and returns its signed token
+func (ms *MFAService) TrustDevice(ctx context.Context, userID string) (string, *model.TrustedDevice, error) {
+	mfaConfig := config.LoadMFAConfig()
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid user ID")
+	}
+
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate trusted device token")
+	}
+
+	ip, userAgent := helper.ClientInfo(ctx)
+	device := &model.TrustedDevice{
+		UserID:    objectID,
+		TokenHash: helper.HashToken(token),
+		IPAddress: ip,
+		UserAgent: userAgent,
+		ExpiresAt: time.Now().Add(mfaConfig.TrustedDeviceExpiry),
+	}
+	if err := ms.trustedDeviceRepo.Create(ctx, device); err != nil {
+		return "", nil, fmt.Errorf("failed to store trusted device")
+	}
+
+	return helper.SignToken(token), device, nil
+}
+
+// IsDeviceTrusted reports whether the signed token belongs to one of the user's unexpired trusted devices
+func (ms *MFAService) IsDeviceTrusted(ctx context.Context, userID, signedToken string) bool {
+	if signedToken == "" {
+		return false
+	}
+
+	token, ok := helper.VerifySignedToken(signedToken)
+	if !ok {
+		return false
+	}
+
+	trusted, err := ms.trustedDeviceRepo.TouchByUserIDTokenHash(ctx, userID, helper.HashToken(token))
+	return err == nil && trusted
+}
+
+// ListTrustedDevices returns the user's unexpired trusted devices
+func (ms *MFAService) ListTrustedDevices(ctx context.Context, userID string) ([]model.TrustedDevice, error) {
+	devices, err := ms.trustedDeviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trusted devices")
+	}
+	return devices, nil
+}
+
+// RevokeTrustedDevice forgets one of the user's trusted devices, its next login asks for the second factor again
+func (ms *MFAService) RevokeTrustedDevice(ctx context.Context, userID, deviceID string) error {
+	deleted, err := ms.trustedDeviceRepo.DeleteByIDUserID(ctx, deviceID, userID)
+	if err != nil || !deleted {
+		return fmt.Errorf("trusted device not found")
+	}
+	return nil
+}
@@ -3,9 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
 	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
 	"bongaquino/server/core/logger"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,28 +18,43 @@ import (
 )
 
 type OrganizationService struct {
-	orgRepo         *repository.OrganizationRepository
-	policyRepo      *repository.PolicyRepository
-	permissionRepo  *repository.PermissionRepository
-	orgUserRoleRepo *repository.OrganizationUserRoleRepository
-	userRepo        *repository.UserRepository
-	roleRepo        *repository.RoleRepository
+	orgRepo            *repository.OrganizationRepository
+	policyRepo         *repository.PolicyRepository
+	permissionRepo     *repository.PermissionRepository
+	orgUserRoleRepo    *repository.OrganizationUserRoleRepository
+	orgInvitationRepo  *repository.OrganizationInvitationRepository
+	orgJoinRequestRepo *repository.OrganizationJoinRequestRepository
+	userRepo           *repository.UserRepository
+	roleRepo           *repository.RoleRepository
+	dnsResolver        provider.DNSResolver
+	permissionService  *PermissionService
+	quotaService       *QuotaService
 }
 
 func NewOrganizationService(orgRepo *repository.OrganizationRepository,
 	policyRepo *repository.PolicyRepository,
 	permissionRepo *repository.PermissionRepository,
 	orgUserRoleRepo *repository.OrganizationUserRoleRepository,
+	orgInvitationRepo *repository.OrganizationInvitationRepository,
+	orgJoinRequestRepo *repository.OrganizationJoinRequestRepository,
 	userRepo *repository.UserRepository,
 	roleRepo *repository.RoleRepository,
+	dnsResolver provider.DNSResolver,
+	permissionService *PermissionService,
+	quotaService *QuotaService,
 ) *OrganizationService {
 	return &OrganizationService{
-		orgRepo:         orgRepo,
-		policyRepo:      policyRepo,
-		permissionRepo:  permissionRepo,
-		orgUserRoleRepo: orgUserRoleRepo,
-		userRepo:        userRepo,
-		roleRepo:        roleRepo,
+		orgRepo:            orgRepo,
+		policyRepo:         policyRepo,
+		permissionRepo:     permissionRepo,
+		orgUserRoleRepo:    orgUserRoleRepo,
+		orgInvitationRepo:  orgInvitationRepo,
+		orgJoinRequestRepo: orgJoinRequestRepo,
+		userRepo:           userRepo,
+		roleRepo:           roleRepo,
+		dnsResolver:        dnsResolver,
+		permissionService:  permissionService,
+		quotaService:       quotaService,
 	}
 }
 
@@ -204,6 +224,13 @@ func (os *OrganizationService) UpdateOrg(ctx context.Context, orgID string, dto
 		"parent_id": parentID,
 	}
 
+	// A new domain has to be verified again before it can be used for auto-join
+	if !strings.EqualFold(dto.Domain, org.Domain) {
+		orgUpdate["domain_token"] = ""
+		orgUpdate["domain_verified_at"] = nil
+		orgUpdate["auto_join_mode"] = "disabled"
+	}
+
 	// Update the organization in the repository
 	err = os.orgRepo.Update(ctx, orgID, orgUpdate)
 	if err != nil {
@@ -289,6 +316,19 @@ func (os *OrganizationService) AddMember(ctx context.Context, orgID string, user
 		logger.Log.Error("error adding member to organization", logger.Error(err))
 		return errors.New("error adding member to organization")
 	}
+	os.permissionService.InvalidateOrganizationPermissions(ctx, orgID, userID)
+
+	// Members draw from the organization's shared storage when it has one
+	if err := os.quotaService.JoinOrganizationPool(ctx, orgID, userID); err != nil {
+		logger.Log.Error("error moving member to organization storage", logger.Error(err))
+
+		// Undo the membership so adding the member can be retried
+		if err := os.orgUserRoleRepo.DeleteByOrganizationIDUserID(ctx, orgID, userID); err != nil {
+			logger.Log.Error("error removing member after failed storage move", logger.Error(err))
+		}
+		os.permissionService.InvalidateOrganizationPermissions(ctx, orgID, userID)
+		return errors.New("error moving member to organization storage")
+	}
 
 	return nil
 }
@@ -335,11 +375,12 @@ func (os *OrganizationService) UpdateMember(ctx context.Context, orgID string, u
 	}
 
 	// Update the member's role in the organization
-	err = os.orgUserRoleRepo.UpdateByOrganizationIDUserID(ctx, orgID, userID, bson.M{"role_id": roleID})
+	err = os.orgUserRoleRepo.UpdateByOrganizationIDUserID(ctx, orgID, userID, bson.M{"role_id": role.ID})
 	if err != nil {
 		logger.Log.Error("error updating member in organization", logger.Error(err))
 		return errors.New("error updating member in organization")
 	}
+	os.permissionService.InvalidateOrganizationPermissions(ctx, orgID, userID)
 
 	return nil
 }
@@ -381,6 +422,13 @@ func (os *OrganizationService) RemoveMember(ctx context.Context, orgID string, u
 		logger.Log.Error("error updating member in organization", logger.Error(err))
 		return errors.New("error updating member in organization")
 	}
+	os.permissionService.InvalidateOrganizationPermissions(ctx, orgID, userID)
+
+	// The member's files leave the organization's shared storage with them
+	if err := os.quotaService.LeaveOrganizationPool(ctx, userID); err != nil {
+		logger.Log.Error("error moving member off organization storage", logger.Error(err))
+		return errors.New("error moving member off organization storage")
+	}
 
 	return nil
 }
@@ -410,3 +458,603 @@ func (os *OrganizationService) GetOrganizationByUserID(ctx context.Context, user
 
 	return org, nil
 }
+
+// CreateInvitation records a pending invitation and returns it with the plain token for the email link
+func (os *OrganizationService) CreateInvitation(ctx context.Context, orgID string, actorID string, actorRole string, email string, roleID string) (*model.OrganizationInvitation, *model.Organization, string, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, nil, "", errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, nil, "", errors.New("organization not found")
+	}
+
+	role, err := os.assignableRole(ctx, roleID, actorRole)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// Invitations are addressed in lowercase, the account may have been registered with any casing
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	// Registered users can only belong to one organization
+	user, err := os.userRepo.ReadByEmailCaseInsensitive(ctx, email)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return nil, nil, "", errors.New("error fetching user")
+	}
+	if user != nil {
+		orgUserRoles, err := os.orgUserRoleRepo.ReadByUserID(ctx, user.ID.Hex())
+		if err != nil {
+			logger.Log.Error("error checking existing member", logger.Error(err))
+			return nil, nil, "", errors.New("error checking existing member")
+		}
+		if len(orgUserRoles) > 0 {
+			if orgUserRoles[0].OrganizationID == org.ID {
+				return nil, nil, "", errors.New("user is already a member")
+			}
+			return nil, nil, "", errors.New("user is already a member of another organization")
+		}
+	}
+
+	pending, err := os.orgInvitationRepo.ReadPendingByOrganizationIDEmail(ctx, orgID, email)
+	if err != nil {
+		logger.Log.Error("error checking pending invitations", logger.Error(err))
+		return nil, nil, "", errors.New("error checking pending invitations")
+	}
+	if pending != nil {
+		return nil, nil, "", errors.New("invitation already pending")
+	}
+
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		logger.Log.Error("error generating invitation token", logger.Error(err))
+		return nil, nil, "", errors.New("error generating invitation token")
+	}
+
+	actorObjectID, _ := primitive.ObjectIDFromHex(actorID)
+	invitation := &model.OrganizationInvitation{
+		OrganizationID: org.ID,
+		Email:          email,
+		RoleID:         role.ID,
+		InvitedBy:      actorObjectID,
+		TokenHash:      helper.HashToken(token),
+		Status:         "pending",
+		ExpiresAt:      time.Now().Add(orgConfig.InvitationExpiry),
+	}
+	if err := os.orgInvitationRepo.Create(ctx, invitation); err != nil {
+		logger.Log.Error("error creating invitation", logger.Error(err))
+		return nil, nil, "", errors.New("error creating invitation")
+	}
+
+	return invitation, org, token, nil
+}
+
+// ListInvitations returns the organization's invitations, expiring stale ones first
+func (os *OrganizationService) ListInvitations(ctx context.Context, orgID string) ([]model.OrganizationInvitation, error) {
+	if err := os.orgInvitationRepo.ExpirePending(ctx); err != nil {
+		logger.Log.Error("error expiring invitations", logger.Error(err))
+		return nil, errors.New("error expiring invitations")
+	}
+
+	invitations, err := os.orgInvitationRepo.ListByOrganizationID(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error listing invitations", logger.Error(err))
+		return nil, errors.New("error listing invitations")
+	}
+
+	return invitations, nil
+}
+
+// ResendInvitation issues a fresh token and expiry for a pending or expired invitation
+func (os *OrganizationService) ResendInvitation(ctx context.Context, orgID string, invitationID string) (*model.OrganizationInvitation, *model.Organization, string, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	invitation, err := os.readOrgInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if invitation.Status != "pending" && invitation.Status != "expired" {
+		return nil, nil, "", errors.New("invitation is no longer pending")
+	}
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, nil, "", errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, nil, "", errors.New("organization not found")
+	}
+
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		logger.Log.Error("error generating invitation token", logger.Error(err))
+		return nil, nil, "", errors.New("error generating invitation token")
+	}
+
+	invitation.TokenHash = helper.HashToken(token)
+	invitation.Status = "pending"
+	invitation.ExpiresAt = time.Now().Add(orgConfig.InvitationExpiry)
+	err = os.orgInvitationRepo.Update(ctx, invitation.ID, bson.M{
+		"token_hash": invitation.TokenHash,
+		"status":     invitation.Status,
+		"expires_at": invitation.ExpiresAt,
+	})
+	if err != nil {
+		logger.Log.Error("error updating invitation", logger.Error(err))
+		return nil, nil, "", errors.New("error updating invitation")
+	}
+
+	return invitation, org, token, nil
+}
+
+// RevokeInvitation cancels a pending invitation
+func (os *OrganizationService) RevokeInvitation(ctx context.Context, orgID string, invitationID string) error {
+	invitation, err := os.readOrgInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.Status != "pending" {
+		return errors.New("invitation is no longer pending")
+	}
+
+	if err := os.orgInvitationRepo.Update(ctx, invitation.ID, bson.M{"status": "revoked"}); err != nil {
+		logger.Log.Error("error updating invitation", logger.Error(err))
+		return errors.New("error updating invitation")
+	}
+
+	return nil
+}
+
+// ReadInvitationByToken returns the pending invitation for an emailed token
+func (os *OrganizationService) ReadInvitationByToken(ctx context.Context, token string) (*model.OrganizationInvitation, error) {
+	invitation, err := os.orgInvitationRepo.ReadByTokenHash(ctx, helper.HashToken(token))
+	if err != nil {
+		logger.Log.Error("error fetching invitation", logger.Error(err))
+		return nil, errors.New("error fetching invitation")
+	}
+	if invitation == nil {
+		return nil, errors.New("invitation not found")
+	}
+	if invitation.Status == "expired" || (invitation.Status == "pending" && time.Now().After(invitation.ExpiresAt)) {
+		return nil, errors.New("invitation expired")
+	}
+	if invitation.Status != "pending" {
+		return nil, errors.New("invitation is no longer pending")
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation adds the invited user to the organization with the invited role
+func (os *OrganizationService) AcceptInvitation(ctx context.Context, token string, userID string) (*model.OrganizationInvitation, error) {
+	invitation, err := os.ReadInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := os.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return nil, errors.New("error fetching user")
+	}
+	if user == nil || !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("invitation does not match user")
+	}
+
+	if err := os.AddMember(ctx, invitation.OrganizationID.Hex(), userID, invitation.RoleID.Hex()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.Status = "accepted"
+	invitation.AcceptedAt = &now
+	if err := os.orgInvitationRepo.Update(ctx, invitation.ID, bson.M{"status": "accepted", "accepted_at": now}); err != nil {
+		logger.Log.Error("error updating invitation", logger.Error(err))
+		return nil, errors.New("error updating invitation")
+	}
+
+	return invitation, nil
+}
+
+// readOrgInvitation fetches an invitation that belongs to the organization
+func (os *OrganizationService) readOrgInvitation(ctx context.Context, orgID string, invitationID string) (*model.OrganizationInvitation, error) {
+	if _, err := primitive.ObjectIDFromHex(invitationID); err != nil {
+		return nil, errors.New("invitation not found")
+	}
+
+	invitation, err := os.orgInvitationRepo.ReadByIDOrganizationID(ctx, invitationID, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching invitation", logger.Error(err))
+		return nil, errors.New("error fetching invitation")
+	}
+	if invitation == nil {
+		return nil, errors.New("invitation not found")
+	}
+
+	return invitation, nil
+}
+
+// ChangeMemberRole updates a member's role on behalf of an organization member
+func (os *OrganizationService) ChangeMemberRole(ctx context.Context, orgID string, actorID string, actorRole string, userID string, roleID string) error {
+	if actorID == userID {
+		return errors.New("cannot change your own role")
+	}
+
+	role, err := os.assignableRole(ctx, roleID, actorRole)
+	if err != nil {
+		return err
+	}
+
+	if err := os.checkManageableMember(ctx, orgID, actorRole, userID); err != nil {
+		return err
+	}
+
+	return os.UpdateMember(ctx, orgID, userID, role.ID.Hex())
+}
+
+// RemoveOrgMember removes a member on behalf of an organization member
+func (os *OrganizationService) RemoveOrgMember(ctx context.Context, orgID string, actorID string, actorRole string, userID string) error {
+	if actorID == userID {
+		return errors.New("cannot remove yourself")
+	}
+
+	if err := os.checkManageableMember(ctx, orgID, actorRole, userID); err != nil {
+		return err
+	}
+
+	return os.RemoveMember(ctx, orgID, userID)
+}
+
+// assignableRole returns the organization role with the given ID if it does not rank above the actor's role
+func (os *OrganizationService) assignableRole(ctx context.Context, roleID string, actorRole string) (*model.Role, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	if _, err := primitive.ObjectIDFromHex(roleID); err != nil {
+		return nil, errors.New("role not found")
+	}
+	role, err := os.roleRepo.Read(ctx, roleID)
+	if err != nil {
+		logger.Log.Error("error fetching role", logger.Error(err))
+		return nil, errors.New("error fetching role")
+	}
+	if role == nil {
+		return nil, errors.New("role not found")
+	}
+
+	rank, ok := orgConfig.RoleRanks[role.Name]
+	if !ok {
+		return nil, errors.New("role is not an organization role")
+	}
+	if rank > orgConfig.RoleRanks[actorRole] {
+		return nil, errors.New("cannot assign a role above your own")
+	}
+
+	return role, nil
+}
+
+// checkManageableMember ensures the member exists and does not rank above the actor's role
+func (os *OrganizationService) checkManageableMember(ctx context.Context, orgID string, actorRole string, userID string) error {
+	orgConfig := config.LoadOrganizationConfig()
+
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return errors.New("user is not a member")
+	}
+	member, err := os.orgUserRoleRepo.ReadByUserIDOrganizationID(ctx, userID, orgID)
+	if err != nil {
+		logger.Log.Error("error checking existing member", logger.Error(err))
+		return errors.New("error checking existing member")
+	}
+	if member == nil {
+		return errors.New("user is not a member")
+	}
+
+	memberRole, err := os.roleRepo.Read(ctx, member.RoleID.Hex())
+	if err != nil {
+		logger.Log.Error("error fetching role", logger.Error(err))
+		return errors.New("error fetching role")
+	}
+	if memberRole != nil && orgConfig.RoleRanks[memberRole.Name] > orgConfig.RoleRanks[actorRole] {
+		return errors.New("cannot manage a member above your own role")
+	}
+
+	return nil
+}
+
+// CreateDomainChallenge issues a new verification token and returns the TXT record the organization must publish
+func (os *OrganizationService) CreateDomainChallenge(ctx context.Context, orgID string) (string, string, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return "", "", errors.New("error fetching organization")
+	}
+	if org == nil {
+		return "", "", errors.New("organization not found")
+	}
+	if org.Domain == "" {
+		return "", "", errors.New("organization has no domain")
+	}
+
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		logger.Log.Error("error generating domain token", logger.Error(err))
+		return "", "", errors.New("error generating domain token")
+	}
+
+	// Issuing a new challenge revokes any earlier verification
+	err = os.orgRepo.Update(ctx, orgID, bson.M{
+		"domain_token":       token,
+		"domain_verified_at": nil,
+		"auto_join_mode":     "disabled",
+	})
+	if err != nil {
+		logger.Log.Error("error updating organization", logger.Error(err))
+		return "", "", errors.New("error updating organization")
+	}
+
+	recordName := orgConfig.DomainRecordName + "." + strings.ToLower(org.Domain)
+	return recordName, orgConfig.DomainRecordTag + token, nil
+}
+
+// VerifyDomain checks the organization's TXT record and marks the domain as verified when the token matches
+func (os *OrganizationService) VerifyDomain(ctx context.Context, orgID string) (*model.Organization, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+	if org.Domain == "" {
+		return nil, errors.New("organization has no domain")
+	}
+	if org.DomainToken == "" {
+		return nil, errors.New("domain challenge not started")
+	}
+
+	domain := strings.ToLower(org.Domain)
+	records, err := os.dnsResolver.LookupTXT(ctx, orgConfig.DomainRecordName+"."+domain)
+	if err != nil {
+		logger.Log.Info("domain verification lookup failed", logger.String("domain", domain), logger.Error(err))
+		return nil, errors.New("domain verification record not found")
+	}
+
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == orgConfig.DomainRecordTag+org.DomainToken {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New("domain verification record not found")
+	}
+
+	// A domain can only route users to one organization
+	existing, err := os.orgRepo.ReadByVerifiedDomain(ctx, domain)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, errors.New("error fetching organization")
+	}
+	if existing != nil && existing.ID != org.ID {
+		return nil, errors.New("domain is already verified by another organization")
+	}
+
+	err = os.orgRepo.Update(ctx, orgID, bson.M{"domain": domain, "domain_verified_at": time.Now()})
+	if err != nil {
+		logger.Log.Error("error updating organization", logger.Error(err))
+		return nil, errors.New("error updating organization")
+	}
+
+	return os.orgRepo.Read(ctx, orgID)
+}
+
+// UpdateAutoJoin sets how users on the verified domain join the organization and the role they receive
+func (os *OrganizationService) UpdateAutoJoin(ctx context.Context, orgID string, actorRole string, mode string, roleID string) (*model.Organization, error) {
+	if mode != "disabled" && mode != "auto_join" && mode != "request" {
+		return nil, errors.New("invalid auto-join mode")
+	}
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+	if mode != "disabled" && org.DomainVerifiedAt == nil {
+		return nil, errors.New("domain is not verified")
+	}
+
+	update := bson.M{"auto_join_mode": mode}
+	if roleID != "" {
+		role, err := os.assignableRole(ctx, roleID, actorRole)
+		if err != nil {
+			return nil, err
+		}
+		update["auto_join_role_id"] = role.ID
+	}
+
+	if err := os.orgRepo.Update(ctx, orgID, update); err != nil {
+		logger.Log.Error("error updating organization", logger.Error(err))
+		return nil, errors.New("error updating organization")
+	}
+
+	return os.orgRepo.Read(ctx, orgID)
+}
+
+// HandleDomainJoin adds a user to the organization that verified their email domain,
+// or queues a join request when the organization reviews new members
+func (os *OrganizationService) HandleDomainJoin(ctx context.Context, userID string) error {
+	user, err := os.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return errors.New("error fetching user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	at := strings.LastIndex(user.Email, "@")
+	if at < 0 {
+		return nil
+	}
+	org, err := os.orgRepo.ReadByVerifiedDomain(ctx, strings.ToLower(user.Email[at+1:]))
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return errors.New("error fetching organization")
+	}
+	if org == nil || (org.AutoJoinMode != "auto_join" && org.AutoJoinMode != "request") {
+		return nil
+	}
+
+	// Users belong to a single organization, existing members are left alone
+	orgUserRoles, err := os.orgUserRoleRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error checking existing member", logger.Error(err))
+		return errors.New("error checking existing member")
+	}
+	if len(orgUserRoles) > 0 {
+		return nil
+	}
+
+	if org.AutoJoinMode == "auto_join" {
+		roleID, err := os.autoJoinRoleID(ctx, org)
+		if err != nil {
+			return err
+		}
+		return os.AddMember(ctx, org.ID.Hex(), userID, roleID)
+	}
+
+	pending, err := os.orgJoinRequestRepo.ReadPendingByOrganizationIDUserID(ctx, org.ID, user.ID)
+	if err != nil {
+		logger.Log.Error("error fetching join request", logger.Error(err))
+		return errors.New("error fetching join request")
+	}
+	if pending != nil {
+		return nil
+	}
+
+	err = os.orgJoinRequestRepo.Create(ctx, &model.OrganizationJoinRequest{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+		Email:          user.Email,
+		Status:         "pending",
+	})
+	if err != nil {
+		logger.Log.Error("error creating join request", logger.Error(err))
+		return errors.New("error creating join request")
+	}
+
+	return nil
+}
+
+// ListJoinRequests returns the organization's join requests, optionally filtered by status
+func (os *OrganizationService) ListJoinRequests(ctx context.Context, orgID string, status string) ([]model.OrganizationJoinRequest, error) {
+	joinRequests, err := os.orgJoinRequestRepo.ListByOrganizationIDStatus(ctx, orgID, status)
+	if err != nil {
+		logger.Log.Error("error listing join requests", logger.Error(err))
+		return nil, errors.New("error listing join requests")
+	}
+	return joinRequests, nil
+}
+
+// ApproveJoinRequest adds the requesting user to the organization with its auto-join role
+func (os *OrganizationService) ApproveJoinRequest(ctx context.Context, orgID string, requestID string, actorID string) error {
+	joinRequest, err := os.readPendingJoinRequest(ctx, orgID, requestID)
+	if err != nil {
+		return err
+	}
+
+	org, err := os.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return errors.New("error fetching organization")
+	}
+	if org == nil {
+		return errors.New("organization not found")
+	}
+
+	roleID, err := os.autoJoinRoleID(ctx, org)
+	if err != nil {
+		return err
+	}
+	if err := os.AddMember(ctx, orgID, joinRequest.UserID.Hex(), roleID); err != nil {
+		return err
+	}
+
+	return os.reviewJoinRequest(ctx, joinRequest, "approved", actorID)
+}
+
+// RejectJoinRequest declines a pending join request
+func (os *OrganizationService) RejectJoinRequest(ctx context.Context, orgID string, requestID string, actorID string) error {
+	joinRequest, err := os.readPendingJoinRequest(ctx, orgID, requestID)
+	if err != nil {
+		return err
+	}
+
+	return os.reviewJoinRequest(ctx, joinRequest, "rejected", actorID)
+}
+
+// readPendingJoinRequest fetches a pending join request that belongs to the organization
+func (os *OrganizationService) readPendingJoinRequest(ctx context.Context, orgID string, requestID string) (*model.OrganizationJoinRequest, error) {
+	if _, err := primitive.ObjectIDFromHex(requestID); err != nil {
+		return nil, errors.New("join request not found")
+	}
+
+	joinRequest, err := os.orgJoinRequestRepo.ReadByIDOrganizationID(ctx, requestID, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching join request", logger.Error(err))
+		return nil, errors.New("error fetching join request")
+	}
+	if joinRequest == nil {
+		return nil, errors.New("join request not found")
+	}
+	if joinRequest.Status != "pending" {
+		return nil, errors.New("join request is no longer pending")
+	}
+
+	return joinRequest, nil
+}
+
+// reviewJoinRequest records the outcome of a join request and who decided it
+func (os *OrganizationService) reviewJoinRequest(ctx context.Context, joinRequest *model.OrganizationJoinRequest, status string, actorID string) error {
+	update := bson.M{"status": status, "reviewed_at": time.Now()}
+	if reviewerID, err := primitive.ObjectIDFromHex(actorID); err == nil {
+		update["reviewed_by"] = reviewerID
+	}
+
+	if err := os.orgJoinRequestRepo.Update(ctx, joinRequest.ID, update); err != nil {
+		logger.Log.Error("error updating join request", logger.Error(err))
+		return errors.New("error updating join request")
+	}
+	return nil
+}
+
+// autoJoinRoleID returns the role given to users joining through the organization's verified domain
+func (os *OrganizationService) autoJoinRoleID(ctx context.Context, org *model.Organization) (string, error) {
+	if !org.AutoJoinRoleID.IsZero() {
+		return org.AutoJoinRoleID.Hex(), nil
+	}
+
+	orgConfig := config.LoadOrganizationConfig()
+	role, err := os.roleRepo.ReadByName(ctx, orgConfig.AutoJoinRole)
+	if err != nil {
+		logger.Log.Error("error fetching role", logger.Error(err))
+		return "", errors.New("error fetching role")
+	}
+	if role == nil {
+		return "", errors.New("role not found")
+	}
+	return role.ID.Hex(), nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// fakeResolver answers TXT lookups from a fixed table
+type fakeResolver struct {
+	records map[string][]string
+	err     error
+}
+
+func (r *fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.records[name], nil
+}
+
+// newTestOrganizationService wires the service to the mock deployment, Redis is unreachable
+// so cache invalidation only logs a warning
+func newTestOrganizationService(mt *mtest.T, resolver provider.DNSResolver) *OrganizationService {
+	mongoProvider := provider.NewMongoProviderFromDatabase(mt.DB)
+	redisProvider := provider.NewRedisProviderFromClient(redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1}), "")
+
+	orgRepo := repository.NewOrganizationRepository(mongoProvider)
+	orgUserRoleRepo := repository.NewOrganizationUserRoleRepository(mongoProvider)
+	userRepo := repository.NewUserRepository(mongoProvider)
+	roleRepo := repository.NewRoleRepository(mongoProvider)
+	permissionRepo := repository.NewPermissionRepository(mongoProvider)
+
+	permissionService := NewPermissionService(repository.NewUserRoleRepository(mongoProvider), orgUserRoleRepo, roleRepo, repository.NewRolePermissionRepository(mongoProvider), permissionRepo, redisProvider)
+	quotaService := NewQuotaService(repository.NewLimitRepository(mongoProvider), orgRepo, orgUserRoleRepo, userRepo)
+
+	return NewOrganizationService(orgRepo, repository.NewPolicyRepository(mongoProvider), permissionRepo, orgUserRoleRepo,
+		repository.NewOrganizationInvitationRepository(mongoProvider), repository.NewOrganizationJoinRequestRepository(mongoProvider),
+		userRepo, roleRepo, resolver, permissionService, quotaService)
+}
+
+// document marshals a model into a mock cursor document
+func document(mt *mtest.T, value any) bson.D {
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		mt.Fatal(err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		mt.Fatal(err)
+	}
+	return doc
+}
+
+func found(mt *mtest.T, ns string, value any) bson.D {
+	return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, value))
+}
+
+func notFound(ns string) bson.D {
+	return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch)
+}
+
+func TestVerifyDomain(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	org := model.Organization{ID: primitive.NewObjectID(), Name: "Acme", Domain: "Acme.com", DomainToken: "token"}
+	recordName := "_bongaquino-verification.acme.com"
+
+	mt.Run("matching record verifies the domain", func(mt *mtest.T) {
+		verified := org
+		now := time.Now()
+		verified.DomainVerifiedAt = &now
+
+		mt.AddMockResponses(
+			found(mt, "db.organizations", org),
+			notFound("db.organizations"),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			found(mt, "db.organizations", verified),
+		)
+		resolver := &fakeResolver{records: map[string][]string{
+			recordName: {"v=spf1 -all", " bongaquino-verification=token "},
+		}}
+
+		result, err := newTestOrganizationService(mt, resolver).VerifyDomain(context.Background(), org.ID.Hex())
+		if err != nil {
+			mt.Fatalf("expected the domain to verify, got %v", err)
+		}
+		if result.DomainVerifiedAt == nil {
+			mt.Fatal("expected the organization to be marked as verified")
+		}
+	})
+
+	mt.Run("missing record is rejected", func(mt *mtest.T) {
+		mt.AddMockResponses(found(mt, "db.organizations", org))
+		resolver := &fakeResolver{records: map[string][]string{
+			recordName: {"bongaquino-verification=other"},
+		}}
+
+		_, err := newTestOrganizationService(mt, resolver).VerifyDomain(context.Background(), org.ID.Hex())
+		if err == nil || err.Error() != "domain verification record not found" {
+			mt.Fatalf("expected domain verification record not found, got %v", err)
+		}
+	})
+
+	mt.Run("resolver error is rejected", func(mt *mtest.T) {
+		mt.AddMockResponses(found(mt, "db.organizations", org))
+		resolver := &fakeResolver{err: errors.New("no such host")}
+
+		_, err := newTestOrganizationService(mt, resolver).VerifyDomain(context.Background(), org.ID.Hex())
+		if err == nil || err.Error() != "domain verification record not found" {
+			mt.Fatalf("expected domain verification record not found, got %v", err)
+		}
+	})
+}
+
+func TestHandleDomainJoin(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	user := model.User{ID: primitive.NewObjectID(), Email: "jane@acme.com", IsVerified: true}
+	role := model.Role{ID: primitive.NewObjectID(), Name: "organization_viewer"}
+	now := time.Now()
+	org := model.Organization{ID: primitive.NewObjectID(), Name: "Acme", Domain: "acme.com", DomainVerifiedAt: &now, AutoJoinRoleID: role.ID}
+
+	mt.Run("auto-join adds the user", func(mt *mtest.T) {
+		org := org
+		org.AutoJoinMode = "auto_join"
+
+		mt.AddMockResponses(
+			found(mt, "db.users", user),
+			found(mt, "db.organizations", org),
+			notFound("db.organization_user_role"),
+			// AddMember
+			found(mt, "db.organizations", org),
+			found(mt, "db.users", user),
+			found(mt, "db.roles", role),
+			notFound("db.organization_user_role"),
+			notFound("db.organization_user_role"),
+			mtest.CreateSuccessResponse(),
+			notFound("db.limits"),
+		)
+
+		if err := newTestOrganizationService(mt, &fakeResolver{}).HandleDomainJoin(context.Background(), user.ID.Hex()); err != nil {
+			mt.Fatalf("expected the user to join, got %v", err)
+		}
+
+		inserted := false
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "insert" && event.Command.Lookup("insert").StringValue() == "organization_user_role" {
+				inserted = true
+			}
+		}
+		if !inserted {
+			mt.Fatal("expected an organization membership to be created")
+		}
+	})
+
+	mt.Run("disabled auto-join leaves the user alone", func(mt *mtest.T) {
+		org := org
+		org.AutoJoinMode = "disabled"
+
+		mt.AddMockResponses(
+			found(mt, "db.users", user),
+			found(mt, "db.organizations", org),
+		)
+
+		if err := newTestOrganizationService(mt, &fakeResolver{}).HandleDomainJoin(context.Background(), user.ID.Hex()); err != nil {
+			mt.Fatalf("expected no error, got %v", err)
+		}
+
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "insert" {
+				mt.Fatalf("expected nothing to be written, got an insert into %s", event.Command.Lookup("insert").StringValue())
+			}
+		}
+	})
+}
+
+func TestAddMemberUndoesMembershipWhenPoolJoinFails(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	user := model.User{ID: primitive.NewObjectID(), Email: "jane@acme.com", IsVerified: true}
+	role := model.Role{ID: primitive.NewObjectID(), Name: "organization_user"}
+	org := model.Organization{ID: primitive.NewObjectID(), Name: "Acme"}
+
+	mt.Run("failed pool join removes the membership", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			found(mt, "db.organizations", org),
+			found(mt, "db.users", user),
+			found(mt, "db.roles", role),
+			notFound("db.organization_user_role"),
+			notFound("db.organization_user_role"),
+			mtest.CreateSuccessResponse(),
+			// JoinOrganizationPool
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "unavailable"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		err := newTestOrganizationService(mt, &fakeResolver{}).AddMember(context.Background(), org.ID.Hex(), user.ID.Hex(), role.ID.Hex())
+		if err == nil || err.Error() != "error moving member to organization storage" {
+			mt.Fatalf("expected error moving member to organization storage, got %v", err)
+		}
+
+		deleted := false
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "delete" && event.Command.Lookup("delete").StringValue() == "organization_user_role" {
+				deleted = true
+			}
+		}
+		if !deleted {
+			mt.Fatal("expected the organization membership to be removed")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PermissionService resolves user roles to permissions
+type PermissionService struct {
+	userRoleRepo       *repository.UserRoleRepository
+	orgUserRoleRepo    *repository.OrganizationUserRoleRepository
+	roleRepo           *repository.RoleRepository
+	rolePermissionRepo *repository.RolePermissionRepository
+	permissionRepo     *repository.PermissionRepository
+	redisProvider      *provider.RedisProvider
+}
+
+// NewPermissionService initializes a new PermissionService
+func NewPermissionService(
+	userRoleRepo *repository.UserRoleRepository,
+	orgUserRoleRepo *repository.OrganizationUserRoleRepository,
+	roleRepo *repository.RoleRepository,
+	rolePermissionRepo *repository.RolePermissionRepository,
+	permissionRepo *repository.PermissionRepository,
+	redisProvider *provider.RedisProvider,
+) *PermissionService {
+	return &PermissionService{
+		userRoleRepo:       userRoleRepo,
+		orgUserRoleRepo:    orgUserRoleRepo,
+		roleRepo:           roleRepo,
+		rolePermissionRepo: rolePermissionRepo,
+		permissionRepo:     permissionRepo,
+		redisProvider:      redisProvider,
+	}
+}
+
+// ListUserPermissions returns the permission names granted to a user by their system roles. Organization roles
+// only apply inside the organization, see ListOrganizationPermissions.
+func (ps *PermissionService) ListUserPermissions(ctx context.Context, userID string) ([]string, error) {
+	permissionConfig := config.LoadPermissionConfig()
+
+	// Serve from the cache when possible, the lookup runs on every request
+	key := fmt.Sprintf("user_permissions:%s", userID)
+	if cached, err := ps.redisProvider.Get(ctx, key); err == nil {
+		if cached == "" {
+			return []string{}, nil
+		}
+		return strings.Split(cached, ","), nil
+	}
+
+	userRoles, err := ps.userRoleRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user roles", logger.Error(err))
+		return nil, errors.New("error fetching user roles")
+	}
+
+	roleIDs := make([]primitive.ObjectID, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		roleIDs = append(roleIDs, userRole.RoleID)
+	}
+
+	permissions, err := ps.listRolePermissions(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.redisProvider.Set(ctx, key, strings.Join(permissions, ","), permissionConfig.CacheExpiry); err != nil {
+		logger.Log.Warn("failed to cache user permissions", logger.Error(err))
+	}
+
+	return permissions, nil
+}
+
+// ListOrganizationPermissions returns the permission names granted to a user by their role in the organization,
+// empty when they are not a member
+func (ps *PermissionService) ListOrganizationPermissions(ctx context.Context, userID, orgID string) ([]string, error) {
+	permissionConfig := config.LoadPermissionConfig()
+
+	// Serve from the cache when possible, role changes and removals drop the member's entry
+	key := fmt.Sprintf("org_permissions:%s:%s", orgID, userID)
+	if cached, err := ps.redisProvider.Get(ctx, key); err == nil {
+		if cached == "" {
+			return []string{}, nil
+		}
+		return strings.Split(cached, ","), nil
+	}
+
+	member, err := ps.orgUserRoleRepo.ReadByUserIDOrganizationID(ctx, userID, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization user role", logger.Error(err))
+		return nil, errors.New("error fetching organization user roles")
+	}
+	// Non-members are not cached, members join through several flows
+	if member == nil {
+		return []string{}, nil
+	}
+
+	permissions, err := ps.listRolePermissions(ctx, []primitive.ObjectID{member.RoleID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.redisProvider.Set(ctx, key, strings.Join(permissions, ","), permissionConfig.CacheExpiry); err != nil {
+		logger.Log.Warn("failed to cache organization permissions", logger.Error(err))
+	}
+
+	return permissions, nil
+}
+
+// listRolePermissions returns the distinct permission names linked to the roles
+func (ps *PermissionService) listRolePermissions(ctx context.Context, roleIDs []primitive.ObjectID) ([]string, error) {
+	permissions := []string{}
+	for _, roleID := range roleIDs {
+		rolePermissions, err := ps.rolePermissionRepo.ReadByRoleID(ctx, roleID.Hex())
+		if err != nil {
+			logger.Log.Error("error fetching role permissions", logger.Error(err))
+			return nil, errors.New("error fetching role permissions")
+		}
+		for _, rolePermission := range rolePermissions {
+			permission, err := ps.permissionRepo.Read(ctx, rolePermission.PermissionID.Hex())
+			if err != nil {
+				logger.Log.Error("error fetching permission", logger.Error(err))
+				return nil, errors.New("error fetching permission")
+			}
+			if permission != nil && !slices.Contains(permissions, permission.Name) {
+				permissions = append(permissions, permission.Name)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// HasPermission reports whether a user is granted the permission
+func (ps *PermissionService) HasPermission(ctx context.Context, userID string, permission string) (bool, error) {
+	permissions, err := ps.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// HasOrganizationPermission reports whether a user's role in the organization grants the permission
+func (ps *PermissionService) HasOrganizationPermission(ctx context.Context, userID, orgID, permission string) (bool, error) {
+	permissions, err := ps.ListOrganizationPermissions(ctx, userID, orgID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(permissions, permission), nil
+}
+
+// InvalidateUserPermissions drops the cached permissions after a role change
+func (ps *PermissionService) InvalidateUserPermissions(ctx context.Context, userID string) {
+	if err := ps.redisProvider.Del(ctx, fmt.Sprintf("user_permissions:%s", userID)); err != nil {
+		logger.Log.Warn("failed to invalidate user permissions", logger.Error(err))
+	}
+}
+
+// InvalidateOrganizationPermissions drops a member's cached organization permissions after their role changes
+func (ps *PermissionService) InvalidateOrganizationPermissions(ctx context.Context, orgID, userID string) {
+	if err := ps.redisProvider.Del(ctx, fmt.Sprintf("org_permissions:%s:%s", orgID, userID)); err != nil {
+		logger.Log.Warn("failed to invalidate organization permissions", logger.Error(err))
+	}
+}
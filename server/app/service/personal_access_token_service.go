@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PersonalAccessTokenService manages user-level API tokens limited to a set of scopes
+type PersonalAccessTokenService struct {
+	tokenRepo *repository.PersonalAccessTokenRepository
+	userRepo  *repository.UserRepository
+}
+
+// NewPersonalAccessTokenService initializes a new PersonalAccessTokenService
+func NewPersonalAccessTokenService(tokenRepo *repository.PersonalAccessTokenRepository, userRepo *repository.UserRepository) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateToken issues a token for the user and returns it in plain text, only its hash is stored
+func (ps *PersonalAccessTokenService) CreateToken(ctx context.Context, userID string, request *dto.CreatePersonalAccessTokenDTO) (string, *model.PersonalAccessToken, error) {
+	patConfig := config.LoadPersonalAccessTokenConfig()
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", nil, errors.New("invalid user ID")
+	}
+
+	// Only known scopes may be granted
+	scopes := []string{}
+	for _, scope := range request.Scopes {
+		if _, ok := patConfig.Scopes[scope]; !ok {
+			return "", nil, errors.New("invalid scope " + scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	var expiresAt *time.Time
+	if request.ExpiresInDays > 0 {
+		expiry := time.Duration(request.ExpiresInDays) * 24 * time.Hour
+		if expiry > patConfig.MaxExpiry {
+			return "", nil, errors.New("expiry is too long")
+		}
+		at := time.Now().Add(expiry)
+		expiresAt = &at
+	}
+
+	count, err := ps.tokenRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return "", nil, errors.New("failed to create personal access token")
+	}
+	if count >= patConfig.MaxPerUser {
+		return "", nil, errors.New("personal access token limit reached")
+	}
+
+	secret, err := helper.GenerateCode(patConfig.TokenLength)
+	if err != nil {
+		return "", nil, errors.New("failed to generate personal access token")
+	}
+	token := patConfig.Prefix + strings.ToLower(secret)
+
+	pat := &model.PersonalAccessToken{
+		UserID:    userObjectID,
+		Name:      request.Name,
+		TokenHash: helper.HashToken(token),
+		Hint:      token[:len(patConfig.Prefix)+6],
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := ps.tokenRepo.Create(ctx, pat); err != nil {
+		return "", nil, errors.New("failed to create personal access token")
+	}
+
+	return token, pat, nil
+}
+
+// ListTokens returns the user's tokens without their secrets
+func (ps *PersonalAccessTokenService) ListTokens(ctx context.Context, userID string) ([]model.PersonalAccessToken, error) {
+	tokens, err := ps.tokenRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch personal access tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeToken deletes one of the user's tokens
+func (ps *PersonalAccessTokenService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	deleted, err := ps.tokenRepo.DeleteByIDUserID(ctx, tokenID, userID)
+	if err != nil || !deleted {
+		return errors.New("personal access token not found")
+	}
+	return nil
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token rather than a JWT
+func (ps *PersonalAccessTokenService) IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, config.LoadPersonalAccessTokenConfig().Prefix)
+}
+
+// Authenticate resolves a bearer token to its personal access token and records when it was last used
+func (ps *PersonalAccessTokenService) Authenticate(ctx context.Context, token string) (*model.PersonalAccessToken, error) {
+	patConfig := config.LoadPersonalAccessTokenConfig()
+
+	pat, err := ps.tokenRepo.ReadByTokenHash(ctx, helper.HashToken(token))
+	if err != nil || pat == nil || pat.IsExpired() {
+		return nil, errors.New("invalid or expired personal access token")
+	}
+
+	// Tokens stop working with their owner
+	user, err := ps.userRepo.Read(ctx, pat.UserID.Hex())
+	if err != nil || user == nil {
+		return nil, errors.New("invalid or expired personal access token")
+	}
+	if user.IsLockActive() {
+		return nil, errors.New("account locked due to multiple failed login attempts")
+	}
+
+	// Last-used tracking is throttled so busy scripts do not write on every request
+	if pat.LastUsedAt == nil || time.Since(*pat.LastUsedAt) > patConfig.LastUsedInterval {
+		ip, _ := helper.ClientInfo(ctx)
+		if err := ps.tokenRepo.Update(ctx, pat.ID, bson.M{"last_used_at": time.Now(), "last_used_ip": ip}); err != nil {
+			logger.Log.Error("failed to track personal access token use", logger.Error(err))
+		}
+	}
+
+	return pat, nil
+}
+
+// ScopesAllow reports whether any of the token scopes covers the permission
+func ScopesAllow(scopes []string, permission string) bool {
+	patConfig := config.LoadPersonalAccessTokenConfig()
+
+	for _, scope := range scopes {
+		if slices.Contains(patConfig.Scopes[scope], permission) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,628 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPolicyDirectoryDepth bounds the walk from a directory up to the root
+const maxPolicyDirectoryDepth = 256
+
+// PolicyService evaluates service account policies
+type PolicyService struct {
+	policyRepo           *repository.PolicyRepository
+	policyPermissionRepo *repository.PolicyPermissionRepository
+	permissionRepo       *repository.PermissionRepository
+	directoryRepo        *repository.DirectoryRepository
+	fileRepo             *repository.FileRepository
+	redisProvider        *provider.RedisProvider
+}
+
+// NewPolicyService initializes a new PolicyService
+func NewPolicyService(
+	policyRepo *repository.PolicyRepository,
+	policyPermissionRepo *repository.PolicyPermissionRepository,
+	permissionRepo *repository.PermissionRepository,
+	directoryRepo *repository.DirectoryRepository,
+	fileRepo *repository.FileRepository,
+	redisProvider *provider.RedisProvider,
+) *PolicyService {
+	return &PolicyService{
+		policyRepo:           policyRepo,
+		policyPermissionRepo: policyPermissionRepo,
+		permissionRepo:       permissionRepo,
+		directoryRepo:        directoryRepo,
+		fileRepo:             fileRepo,
+		redisProvider:        redisProvider,
+	}
+}
+
+// ResolvePolicy returns the policy with the given ID, or the default service account policy if no ID is set
+func (ps *PolicyService) ResolvePolicy(ctx context.Context, policyID string) (*model.Policy, error) {
+	policyConfig := config.LoadPolicyConfig()
+
+	var policy *model.Policy
+	var err error
+	if policyID == "" || policyID == primitive.NilObjectID.Hex() {
+		policy, err = ps.policyRepo.ReadByName(ctx, policyConfig.DefaultServiceAccountPolicy)
+	} else {
+		if _, err := primitive.ObjectIDFromHex(policyID); err != nil {
+			return nil, errors.New("invalid policy ID")
+		}
+		policy, err = ps.policyRepo.Read(ctx, policyID)
+	}
+	if err != nil {
+		logger.Log.Error("error fetching policy", logger.Error(err))
+		return nil, errors.New("error fetching policy")
+	}
+	if policy == nil {
+		return nil, errors.New("policy not found")
+	}
+
+	return policy, nil
+}
+
+// ListPolicyPermissions returns the permission names granted by a policy
+func (ps *PolicyService) ListPolicyPermissions(ctx context.Context, policyID string) ([]string, error) {
+	policy, err := ps.ResolvePolicy(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+	return ps.listPermissions(ctx, policy)
+}
+
+// ScopesAllowed reports whether every scope is a permission the policy can grant, through
+// policy_permission or an allow statement
+func (ps *PolicyService) ScopesAllowed(ctx context.Context, policy *model.Policy, scopes []string) (bool, error) {
+	permissions, err := ps.listPermissions(ctx, policy)
+	if err != nil {
+		return false, err
+	}
+
+	for _, scope := range scopes {
+		if slices.Contains(permissions, scope) {
+			continue
+		}
+
+		permission, err := ps.permissionRepo.ReadByName(ctx, scope)
+		if err != nil {
+			logger.Log.Error("error fetching permission", logger.Error(err))
+			return false, errors.New("error fetching permission")
+		}
+		if permission == nil {
+			return false, nil
+		}
+
+		allowed := false
+		for _, statement := range policy.Statements {
+			if statement.Effect == "allow" && matchesAction(statement.Actions, scope) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// listPermissions returns the permission names linked to a resolved policy
+func (ps *PolicyService) listPermissions(ctx context.Context, policy *model.Policy) ([]string, error) {
+	policyConfig := config.LoadPolicyConfig()
+
+	// Serve from the cache when possible, the lookup runs on every client request
+	key := fmt.Sprintf("policy_permissions:%s", policy.ID.Hex())
+	if cached, err := ps.redisProvider.Get(ctx, key); err == nil {
+		if cached == "" {
+			return []string{}, nil
+		}
+		return strings.Split(cached, ","), nil
+	}
+
+	policyPermissions, err := ps.policyPermissionRepo.ReadByPolicyID(ctx, policy.ID.Hex())
+	if err != nil {
+		logger.Log.Error("error fetching policy permissions", logger.Error(err))
+		return nil, errors.New("error fetching policy permissions")
+	}
+
+	permissions := make([]string, 0, len(policyPermissions))
+	for _, policyPermission := range policyPermissions {
+		permission, err := ps.permissionRepo.Read(ctx, policyPermission.PermissionID.Hex())
+		if err != nil {
+			logger.Log.Error("error fetching permission", logger.Error(err))
+			return nil, errors.New("error fetching permission")
+		}
+		if permission != nil {
+			permissions = append(permissions, permission.Name)
+		}
+	}
+
+	if err := ps.redisProvider.Set(ctx, key, strings.Join(permissions, ","), policyConfig.PermissionCacheExpiry); err != nil {
+		logger.Log.Warn("failed to cache policy permissions", logger.Error(err))
+	}
+
+	return permissions, nil
+}
+
+// Authorize evaluates a service account request. When the access token was issued
+// with scopes, the action must also be one of those scopes.
+func (ps *PolicyService) Authorize(ctx context.Context, policyID string, scopes []string, request *PolicyRequest) (*PolicyDecision, error) {
+	if len(scopes) > 0 && !slices.Contains(scopes, request.Action) {
+		return &PolicyDecision{Allowed: false, Effect: "implicit_deny", Reason: "action is outside the token scopes"}, nil
+	}
+	return ps.Evaluate(ctx, policyID, request)
+}
+
+// ListPolicies returns all policies
+func (ps *PolicyService) ListPolicies(ctx context.Context) ([]*model.Policy, error) {
+	policies, err := ps.policyRepo.List(ctx)
+	if err != nil {
+		logger.Log.Error("error listing policies", logger.Error(err))
+		return nil, errors.New("error listing policies")
+	}
+	return policies, nil
+}
+
+// ReadPolicy returns a policy along with the permissions linked to it
+func (ps *PolicyService) ReadPolicy(ctx context.Context, policyID string) (*model.Policy, []string, error) {
+	if _, err := primitive.ObjectIDFromHex(policyID); err != nil {
+		return nil, nil, errors.New("invalid policy ID")
+	}
+
+	policy, err := ps.ResolvePolicy(ctx, policyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissions, err := ps.ListPolicyPermissions(ctx, policyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return policy, permissions, nil
+}
+
+// CreatePolicy stores a new policy document
+func (ps *PolicyService) CreatePolicy(ctx context.Context, request *dto.CreatePolicyDTO) (*model.Policy, error) {
+	existing, err := ps.policyRepo.ReadByName(ctx, request.Name)
+	if err != nil {
+		logger.Log.Error("error fetching policy", logger.Error(err))
+		return nil, errors.New("error fetching policy")
+	}
+	if existing != nil {
+		return nil, errors.New("policy already exists")
+	}
+
+	if err := ps.ValidateStatements(ctx, request.Statements); err != nil {
+		return nil, err
+	}
+
+	policy := &model.Policy{
+		Name:       request.Name,
+		Statements: request.Statements,
+	}
+	if err := ps.policyRepo.Create(ctx, policy); err != nil {
+		logger.Log.Error("error creating policy", logger.Error(err))
+		return nil, errors.New("error creating policy")
+	}
+
+	return policy, nil
+}
+
+// UpdatePolicy renames a policy or replaces its statements
+func (ps *PolicyService) UpdatePolicy(ctx context.Context, policyID string, request *dto.UpdatePolicyDTO) (*model.Policy, error) {
+	if _, err := primitive.ObjectIDFromHex(policyID); err != nil {
+		return nil, errors.New("invalid policy ID")
+	}
+
+	policy, err := ps.ResolvePolicy(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{}
+	if request.Name != nil && *request.Name != policy.Name {
+		// Service accounts without a policy fall back to the default one by name
+		if policy.Name == config.LoadPolicyConfig().DefaultServiceAccountPolicy {
+			return nil, errors.New("default policy cannot be renamed")
+		}
+		existing, err := ps.policyRepo.ReadByName(ctx, *request.Name)
+		if err != nil {
+			logger.Log.Error("error fetching policy", logger.Error(err))
+			return nil, errors.New("error fetching policy")
+		}
+		if existing != nil {
+			return nil, errors.New("policy already exists")
+		}
+		update["name"] = *request.Name
+	}
+	if request.Statements != nil {
+		if err := ps.ValidateStatements(ctx, *request.Statements); err != nil {
+			return nil, err
+		}
+		update["statements"] = *request.Statements
+	}
+	if len(update) == 0 {
+		return policy, nil
+	}
+	update["updated_at"] = time.Now()
+
+	if err := ps.policyRepo.Update(ctx, policy.ID.Hex(), update); err != nil {
+		logger.Log.Error("error updating policy", logger.Error(err))
+		return nil, errors.New("error updating policy")
+	}
+
+	return ps.ResolvePolicy(ctx, policyID)
+}
+
+// PolicyRequest describes a request to evaluate against a policy
+type PolicyRequest struct {
+	Action      string
+	UserID      string // Owner of the target resource, used to resolve directory ancestry
+	DirectoryID string // Target directory, or the directory receiving an upload
+	FileID      string
+	SourceIP    string
+	Time        time.Time
+	ObjectSize  int64
+	IsEncrypted bool
+}
+
+// PolicyDecision is the outcome of evaluating a policy
+type PolicyDecision struct {
+	Allowed   bool   `json:"allowed"`
+	Effect    string `json:"effect"`              // "allow", "deny" or "implicit_deny"
+	Statement *int   `json:"statement,omitempty"` // Index of the deciding statement
+	Reason    string `json:"reason"`
+}
+
+// resolvedRequest is a PolicyRequest with its resource ancestry loaded
+type resolvedRequest struct {
+	*PolicyRequest
+	directoryPath []string
+}
+
+// Evaluate decides a request against a policy document. An explicit deny in any matching
+// statement wins, otherwise any matching allow grants access. Permissions linked through
+// policy_permission only grant actions that no statement mentions, so allow statements with
+// resources or conditions narrow a linked permission instead of being bypassed by it.
+func (ps *PolicyService) Evaluate(ctx context.Context, policyID string, request *PolicyRequest) (*PolicyDecision, error) {
+	policy, err := ps.ResolvePolicy(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := ps.listPermissions(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := ps.resolveRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowedBy *int
+	isGoverned := false
+	for i, statement := range policy.Statements {
+		if !matchesAction(statement.Actions, request.Action) {
+			continue
+		}
+		if statement.Effect == "allow" {
+			isGoverned = true
+		}
+		if !resolved.matchesResource(statement.Resources) {
+			continue
+		}
+		if !resolved.meetsConditions(statement.Conditions) {
+			continue
+		}
+		index := i
+		if statement.Effect == "deny" {
+			return &PolicyDecision{Allowed: false, Effect: "deny", Statement: &index, Reason: "explicitly denied by statement"}, nil
+		}
+		if allowedBy == nil {
+			allowedBy = &index
+		}
+	}
+
+	if allowedBy != nil {
+		return &PolicyDecision{Allowed: true, Effect: "allow", Statement: allowedBy, Reason: "allowed by statement"}, nil
+	}
+	if !isGoverned && slices.Contains(permissions, request.Action) {
+		return &PolicyDecision{Allowed: true, Effect: "allow", Reason: "granted by policy permissions"}, nil
+	}
+	return &PolicyDecision{Allowed: false, Effect: "implicit_deny", Reason: "no statement allows the action"}, nil
+}
+
+// ResolveDirectoryPath returns the request's target directory followed by its ancestors up to the owner's root,
+// it is empty when the target does not exist
+func (ps *PolicyService) ResolveDirectoryPath(ctx context.Context, request *PolicyRequest) ([]string, error) {
+	resolved, err := ps.resolveRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.directoryPath, nil
+}
+
+// resolveRequest loads the target file and the directory chain up to the owner's root
+func (ps *PolicyService) resolveRequest(ctx context.Context, request *PolicyRequest) (*resolvedRequest, error) {
+	resolved := &resolvedRequest{PolicyRequest: request}
+	if request.UserID == "" {
+		if request.DirectoryID != "" {
+			resolved.directoryPath = []string{request.DirectoryID}
+		}
+		return resolved, nil
+	}
+
+	directoryID := request.DirectoryID
+	if request.FileID != "" {
+		if _, err := primitive.ObjectIDFromHex(request.FileID); err == nil {
+			file, err := ps.fileRepo.ReadByIDUserID(ctx, request.FileID, request.UserID)
+			if err != nil {
+				logger.Log.Error("error fetching file", logger.Error(err))
+				return nil, errors.New("error fetching file")
+			}
+			// Missing files are left to the controller, which responds with 404
+			if file != nil {
+				if file.DirectoryID != nil {
+					directoryID = file.DirectoryID.Hex()
+				}
+				if request.ObjectSize == 0 {
+					request.ObjectSize = file.Size
+				}
+				request.IsEncrypted = request.IsEncrypted || file.IsEncrypted
+			}
+		}
+	}
+
+	var directory *model.Directory
+	var err error
+	if directoryID == "" || directoryID == "root" {
+		directory, err = ps.directoryRepo.ReadByUserIDName(ctx, request.UserID, "root")
+	} else if _, parseErr := primitive.ObjectIDFromHex(directoryID); parseErr == nil {
+		directory, err = ps.directoryRepo.ReadByIDUserID(ctx, directoryID, request.UserID)
+	}
+	if err != nil {
+		logger.Log.Error("error fetching directory", logger.Error(err))
+		return nil, errors.New("error fetching directory")
+	}
+
+	// Walk up to the root so directory resources match their whole subtree
+	for depth := 0; directory != nil && depth < maxPolicyDirectoryDepth; depth++ {
+		resolved.directoryPath = append(resolved.directoryPath, directory.ID.Hex())
+		if directory.DirectoryID == nil {
+			break
+		}
+		directory, err = ps.directoryRepo.ReadByIDUserID(ctx, directory.DirectoryID.Hex(), request.UserID)
+		if err != nil {
+			logger.Log.Error("error fetching parent directory", logger.Error(err))
+			return nil, errors.New("error fetching directory")
+		}
+	}
+
+	return resolved, nil
+}
+
+// ValidateStatements checks that policy statements are well formed before they are stored
+func (ps *PolicyService) ValidateStatements(ctx context.Context, statements []model.PolicyStatement) error {
+	permissions, err := ps.permissionRepo.List(ctx)
+	if err != nil {
+		logger.Log.Error("error listing permissions", logger.Error(err))
+		return errors.New("error listing permissions")
+	}
+	known := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		known[permission.Name] = true
+		known[strings.SplitN(permission.Name, ":", 2)[0]+":*"] = true
+	}
+
+	for i, statement := range statements {
+		invalid := func(reason string) error {
+			return fmt.Errorf("invalid policy statement %d: %s", i, reason)
+		}
+
+		if statement.Effect != "allow" && statement.Effect != "deny" {
+			return invalid("effect must be allow or deny")
+		}
+		if len(statement.Actions) == 0 {
+			return invalid("at least one action is required")
+		}
+		for _, action := range statement.Actions {
+			if action != "*" && !known[action] {
+				return invalid("unknown action " + action)
+			}
+		}
+		if len(statement.Resources) == 0 {
+			return invalid("at least one resource is required")
+		}
+		for _, resource := range statement.Resources {
+			if resource == "*" {
+				continue
+			}
+			kind, value, found := strings.Cut(resource, ":")
+			switch {
+			case !found || value == "":
+				return invalid("malformed resource " + resource)
+			case kind == "directory":
+				if _, err := primitive.ObjectIDFromHex(value); err != nil {
+					return invalid("malformed resource " + resource)
+				}
+			case kind == "file":
+				if _, err := path.Match(value, ""); err != nil {
+					return invalid("malformed resource " + resource)
+				}
+				// Uploads have no file ID yet, so only file:* covers them and narrower patterns would be silently skipped
+				if value != "*" && matchesAction(statement.Actions, "file:upload") {
+					return invalid("file:upload cannot be limited to file resources other than file:*, use a directory resource")
+				}
+			default:
+				return invalid("malformed resource " + resource)
+			}
+		}
+
+		conditions := statement.Conditions
+		if conditions == nil {
+			continue
+		}
+		for _, cidr := range conditions.SourceCIDRs {
+			if _, err := netip.ParsePrefix(cidr); err != nil {
+				return invalid("malformed source CIDR " + cidr)
+			}
+		}
+		if window := conditions.TimeOfDay; window != nil {
+			if _, err := parseClock(window.Start); err != nil {
+				return invalid("time_of_day start must be HH:MM")
+			}
+			if _, err := parseClock(window.End); err != nil {
+				return invalid("time_of_day end must be HH:MM")
+			}
+			if _, err := time.LoadLocation(window.Timezone); err != nil {
+				return invalid("unknown timezone " + window.Timezone)
+			}
+		}
+		if conditions.NotBefore != nil && conditions.NotAfter != nil && !conditions.NotBefore.Before(*conditions.NotAfter) {
+			return invalid("not_before must be earlier than not_after")
+		}
+		if conditions.MaxObjectSize != nil && *conditions.MaxObjectSize < 0 {
+			return invalid("max_object_size cannot be negative")
+		}
+	}
+
+	return nil
+}
+
+// matchesAction reports whether the statement actions cover the requested action
+func matchesAction(actions []string, action string) bool {
+	for _, candidate := range actions {
+		if candidate == "*" || candidate == action {
+			return true
+		}
+		if prefix, found := strings.CutSuffix(candidate, ":*"); found && strings.HasPrefix(action, prefix+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource reports whether any statement resource covers the request target
+func (r *resolvedRequest) matchesResource(resources []string) bool {
+	for _, resource := range resources {
+		if resource == "*" {
+			return true
+		}
+		kind, value, _ := strings.Cut(resource, ":")
+		switch kind {
+		case "directory":
+			if slices.Contains(r.directoryPath, value) {
+				return true
+			}
+		case "file":
+			// Uploads have no file ID yet, only a pattern covering every file covers them
+			if r.Action == "file:upload" {
+				if value == "*" {
+					return true
+				}
+				continue
+			}
+			// Directory requests have no file, so file patterns never cover them
+			if r.FileID == "" {
+				continue
+			}
+			if matched, _ := path.Match(value, r.FileID); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// meetsConditions reports whether every condition holds for the request
+func (r *resolvedRequest) meetsConditions(conditions *model.PolicyCondition) bool {
+	if conditions == nil {
+		return true
+	}
+
+	if len(conditions.SourceCIDRs) > 0 {
+		addr, err := netip.ParseAddr(r.SourceIP)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		inRange := false
+		for _, cidr := range conditions.SourceCIDRs {
+			if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Contains(addr) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+
+	if window := conditions.TimeOfDay; window != nil {
+		location, err := time.LoadLocation(window.Timezone)
+		if err != nil {
+			return false
+		}
+		start, startErr := parseClock(window.Start)
+		end, endErr := parseClock(window.End)
+		if startErr != nil || endErr != nil {
+			return false
+		}
+		local := r.Time.In(location)
+		minute := local.Hour()*60 + local.Minute()
+		if start <= end {
+			if minute < start || minute >= end {
+				return false
+			}
+		} else if minute < start && minute >= end {
+			// Windows such as 22:00-06:00 wrap past midnight
+			return false
+		}
+	}
+
+	if conditions.NotBefore != nil && r.Time.Before(*conditions.NotBefore) {
+		return false
+	}
+	if conditions.NotAfter != nil && r.Time.After(*conditions.NotAfter) {
+		return false
+	}
+
+	if conditions.MaxObjectSize != nil && r.ObjectSize > *conditions.MaxObjectSize {
+		return false
+	}
+
+	// Encryption only applies to file actions
+	if conditions.EncryptedOnly && strings.HasPrefix(r.Action, "file:") && !r.IsEncrypted {
+		return false
+	}
+
+	return true
+}
+
+// parseClock converts "HH:MM" into minutes after midnight
+func parseClock(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
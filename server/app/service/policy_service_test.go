@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// newTestPolicyService wires the service to the mock deployment, Redis is unreachable so
+// permissions are always read from the database
+func newTestPolicyService(mt *mtest.T) *PolicyService {
+	mongoProvider := provider.NewMongoProviderFromDatabase(mt.DB)
+	redisProvider := provider.NewRedisProviderFromClient(redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1}), "")
+
+	return NewPolicyService(repository.NewPolicyRepository(mongoProvider), repository.NewPolicyPermissionRepository(mongoProvider),
+		repository.NewPermissionRepository(mongoProvider), repository.NewDirectoryRepository(mongoProvider),
+		repository.NewFileRepository(mongoProvider), redisProvider)
+}
+
+func TestEvaluate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	// 15:30 UTC is 23:30 in Manila
+	now := time.Date(2025, 6, 1, 15, 30, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+	maxSize := int64(1024)
+	nightShift := &model.PolicyTimeWindow{Start: "22:00", End: "06:00", Timezone: "Asia/Manila"}
+	allowAll := model.PolicyStatement{Effect: "allow", Actions: []string{"*"}, Resources: []string{"*"}}
+	download := func(change func(*PolicyRequest)) *PolicyRequest {
+		request := &PolicyRequest{Action: "file:download", FileID: "65fcd89a89c9a8f123456789", SourceIP: "203.0.113.7", Time: now, ObjectSize: 512}
+		if change != nil {
+			change(request)
+		}
+		return request
+	}
+	allowWhen := func(conditions *model.PolicyCondition) []model.PolicyStatement {
+		return []model.PolicyStatement{{Effect: "allow", Actions: []string{"file:download"}, Resources: []string{"*"}, Conditions: conditions}}
+	}
+
+	tests := []struct {
+		name        string
+		statements  []model.PolicyStatement
+		permissions []string // Linked through policy_permission
+		request     *PolicyRequest
+		effect      string
+	}{
+		{
+			name:       "explicit deny beats allow",
+			statements: []model.PolicyStatement{allowAll, {Effect: "deny", Actions: []string{"file:download"}, Resources: []string{"file:65fc*"}}},
+			request:    download(nil),
+			effect:     "deny",
+		},
+		{
+			name:       "deny that does not match leaves the allow",
+			statements: []model.PolicyStatement{allowAll, {Effect: "deny", Actions: []string{"file:download"}, Resources: []string{"file:abc*"}}},
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:       "no matching statement is an implicit deny",
+			statements: []model.PolicyStatement{{Effect: "allow", Actions: []string{"directory:*"}, Resources: []string{"*"}}},
+			request:    download(nil),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "source inside the CIDR",
+			statements: allowWhen(&model.PolicyCondition{SourceCIDRs: []string{"10.0.0.0/8", "203.0.113.0/24"}}),
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:       "IPv4-mapped source inside the CIDR",
+			statements: allowWhen(&model.PolicyCondition{SourceCIDRs: []string{"203.0.113.0/24"}}),
+			request:    download(func(r *PolicyRequest) { r.SourceIP = "::ffff:203.0.113.7" }),
+			effect:     "allow",
+		},
+		{
+			name:       "source outside the CIDR",
+			statements: allowWhen(&model.PolicyCondition{SourceCIDRs: []string{"203.0.113.0/24"}}),
+			request:    download(func(r *PolicyRequest) { r.SourceIP = "198.51.100.7" }),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "late evening inside a window past midnight",
+			statements: allowWhen(&model.PolicyCondition{TimeOfDay: nightShift}),
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:       "early morning inside a window past midnight",
+			statements: allowWhen(&model.PolicyCondition{TimeOfDay: nightShift}),
+			request:    download(func(r *PolicyRequest) { r.Time = time.Date(2025, 6, 1, 21, 59, 0, 0, time.UTC) }),
+			effect:     "allow",
+		},
+		{
+			name:       "window end is exclusive",
+			statements: allowWhen(&model.PolicyCondition{TimeOfDay: nightShift}),
+			request:    download(func(r *PolicyRequest) { r.Time = time.Date(2025, 6, 1, 22, 0, 0, 0, time.UTC) }),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "midday outside a window past midnight",
+			statements: allowWhen(&model.PolicyCondition{TimeOfDay: nightShift}),
+			request:    download(func(r *PolicyRequest) { r.Time = time.Date(2025, 6, 1, 4, 0, 0, 0, time.UTC) }),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "inside not_before and not_after",
+			statements: allowWhen(&model.PolicyCondition{NotBefore: &earlier, NotAfter: &later}),
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:       "before not_before",
+			statements: allowWhen(&model.PolicyCondition{NotBefore: &later}),
+			request:    download(nil),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "after not_after",
+			statements: allowWhen(&model.PolicyCondition{NotAfter: &earlier}),
+			request:    download(nil),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "object within max_object_size",
+			statements: allowWhen(&model.PolicyCondition{MaxObjectSize: &maxSize}),
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:       "object over max_object_size",
+			statements: allowWhen(&model.PolicyCondition{MaxObjectSize: &maxSize}),
+			request:    download(func(r *PolicyRequest) { r.ObjectSize = maxSize + 1 }),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "encrypted file with encrypted_only",
+			statements: allowWhen(&model.PolicyCondition{EncryptedOnly: true}),
+			request:    download(func(r *PolicyRequest) { r.IsEncrypted = true }),
+			effect:     "allow",
+		},
+		{
+			name:       "plain file with encrypted_only",
+			statements: allowWhen(&model.PolicyCondition{EncryptedOnly: true}),
+			request:    download(nil),
+			effect:     "implicit_deny",
+		},
+		{
+			name:       "deny applies only while its conditions hold",
+			statements: []model.PolicyStatement{allowAll, {Effect: "deny", Actions: []string{"*"}, Resources: []string{"*"}, Conditions: &model.PolicyCondition{SourceCIDRs: []string{"198.51.100.0/24"}}}},
+			request:    download(nil),
+			effect:     "allow",
+		},
+		{
+			name:        "linked permission grants actions no statement mentions",
+			statements:  []model.PolicyStatement{{Effect: "allow", Actions: []string{"directory:read"}, Resources: []string{"*"}}},
+			permissions: []string{"file:download"},
+			request:     download(nil),
+			effect:      "allow",
+		},
+		{
+			name:        "allow statement narrows a linked permission",
+			statements:  allowWhen(&model.PolicyCondition{SourceCIDRs: []string{"198.51.100.0/24"}}),
+			permissions: []string{"file:download"},
+			request:     download(nil),
+			effect:      "implicit_deny",
+		},
+		{
+			name:        "deny beats a linked permission",
+			statements:  []model.PolicyStatement{{Effect: "deny", Actions: []string{"file:download"}, Resources: []string{"*"}}},
+			permissions: []string{"file:download"},
+			request:     download(nil),
+			effect:      "deny",
+		},
+	}
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			policy := model.Policy{ID: primitive.NewObjectID(), Name: "conditions", Statements: test.statements}
+
+			links := make([]bson.D, 0, len(test.permissions))
+			permissions := make([]bson.D, 0, len(test.permissions))
+			for _, name := range test.permissions {
+				permission := model.Permission{ID: primitive.NewObjectID(), Name: name}
+				links = append(links, document(mt, model.PolicyPermission{ID: primitive.NewObjectID(), PolicyID: policy.ID, PermissionID: permission.ID}))
+				permissions = append(permissions, found(mt, "db.permissions", permission))
+			}
+			mt.AddMockResponses(found(mt, "db.policies", policy), mtest.CreateCursorResponse(0, "db.policy_permission", mtest.FirstBatch, links...))
+			mt.AddMockResponses(permissions...)
+
+			decision, err := newTestPolicyService(mt).Evaluate(context.Background(), policy.ID.Hex(), test.request)
+			if err != nil {
+				mt.Fatalf("expected the request to be evaluated, got %v", err)
+			}
+			if decision.Effect != test.effect {
+				mt.Fatalf("expected %s, got %s (%s)", test.effect, decision.Effect, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateUpload(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	directoryID := primitive.NewObjectID().Hex()
+	upload := &PolicyRequest{Action: "file:upload", DirectoryID: directoryID}
+
+	tests := []struct {
+		name       string
+		statements []model.PolicyStatement
+		effect     string
+	}{
+		{
+			name:       "deny on every file blocks uploads",
+			statements: []model.PolicyStatement{{Effect: "allow", Actions: []string{"*"}, Resources: []string{"*"}}, {Effect: "deny", Actions: []string{"file:*"}, Resources: []string{"file:*"}}},
+			effect:     "deny",
+		},
+		{
+			name:       "allow on every file covers uploads",
+			statements: []model.PolicyStatement{{Effect: "allow", Actions: []string{"file:*"}, Resources: []string{"file:*"}}},
+			effect:     "allow",
+		},
+		{
+			name:       "allow on the receiving directory covers uploads",
+			statements: []model.PolicyStatement{{Effect: "allow", Actions: []string{"file:upload"}, Resources: []string{"directory:" + directoryID}}},
+			effect:     "allow",
+		},
+		{
+			name:       "deny on the receiving directory blocks uploads",
+			statements: []model.PolicyStatement{{Effect: "allow", Actions: []string{"*"}, Resources: []string{"*"}}, {Effect: "deny", Actions: []string{"file:upload"}, Resources: []string{"directory:" + directoryID}}},
+			effect:     "deny",
+		},
+	}
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			policy := model.Policy{ID: primitive.NewObjectID(), Name: "uploads", Statements: test.statements}
+			mt.AddMockResponses(found(mt, "db.policies", policy), notFound("db.policy_permission"))
+
+			decision, err := newTestPolicyService(mt).Evaluate(context.Background(), policy.ID.Hex(), upload)
+			if err != nil {
+				mt.Fatalf("expected the upload to be evaluated, got %v", err)
+			}
+			if decision.Effect != test.effect {
+				mt.Fatalf("expected %s, got %s (%s)", test.effect, decision.Effect, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestValidateStatementsRejectsFileResourcesOnUploads(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	permissions := []model.Permission{
+		{ID: primitive.NewObjectID(), Name: "file:upload"},
+		{ID: primitive.NewObjectID(), Name: "file:download"},
+	}
+	tests := []struct {
+		name      string
+		statement model.PolicyStatement
+		valid     bool
+	}{
+		{"deny upload on a file pattern", model.PolicyStatement{Effect: "deny", Actions: []string{"file:upload"}, Resources: []string{"file:65fc*"}}, false},
+		{"allow upload on a file pattern", model.PolicyStatement{Effect: "allow", Actions: []string{"file:upload", "file:download"}, Resources: []string{"file:65fc*"}}, false},
+		{"file wildcard action on a file pattern", model.PolicyStatement{Effect: "allow", Actions: []string{"file:*"}, Resources: []string{"file:abc*"}}, false},
+		{"any action on a file pattern", model.PolicyStatement{Effect: "deny", Actions: []string{"*"}, Resources: []string{"file:abc*"}}, false},
+		{"allow upload on every file", model.PolicyStatement{Effect: "allow", Actions: []string{"file:upload"}, Resources: []string{"file:*"}}, true},
+		{"allow upload on a directory", model.PolicyStatement{Effect: "allow", Actions: []string{"file:upload"}, Resources: []string{"directory:" + primitive.NewObjectID().Hex()}}, true},
+		{"allow download on a file pattern", model.PolicyStatement{Effect: "allow", Actions: []string{"file:download"}, Resources: []string{"file:65fc*"}}, true},
+	}
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.permissions", mtest.FirstBatch, document(mt, permissions[0]), document(mt, permissions[1])))
+
+			err := newTestPolicyService(mt).ValidateStatements(context.Background(), []model.PolicyStatement{test.statement})
+			if test.valid && err != nil {
+				mt.Fatalf("expected the statement to be accepted, got %v", err)
+			}
+			if !test.valid && (err == nil || !strings.Contains(err.Error(), "use a directory resource")) {
+				mt.Fatalf("expected the statement to be rejected, got %v", err)
+			}
+		})
+	}
+}
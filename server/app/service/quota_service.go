@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuotaService accounts stored bytes against user limits and organization pools
+type QuotaService struct {
+	limitRepo       *repository.LimitRepository
+	orgRepo         *repository.OrganizationRepository
+	orgUserRoleRepo *repository.OrganizationUserRoleRepository
+	userRepo        *repository.UserRepository
+}
+
+// MemberStorageUsage is one member's share of an organization's storage
+type MemberStorageUsage struct {
+	UserID     primitive.ObjectID `json:"user_id"`
+	Email      string             `json:"email"`
+	BytesUsage int64              `json:"bytes_usage"`
+	BytesLimit int64              `json:"bytes_limit"` // Member sub-cap, 0 for none
+	IsPooled   bool               `json:"is_pooled"`
+}
+
+// NewQuotaService initializes a new QuotaService
+func NewQuotaService(limitRepo *repository.LimitRepository, orgRepo *repository.OrganizationRepository, orgUserRoleRepo *repository.OrganizationUserRoleRepository, userRepo *repository.UserRepository) *QuotaService {
+	return &QuotaService{
+		limitRepo:       limitRepo,
+		orgRepo:         orgRepo,
+		orgUserRoleRepo: orgUserRoleRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// ReserveStorage counts bytes about to be stored against the user's limit, or against the member
+// sub-cap and the organization pool for pooled members. Nothing is counted when a level is full.
+func (qs *QuotaService) ReserveStorage(ctx context.Context, userID string, bytes int64) error {
+	limit, err := qs.readUserLimit(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !limit.IsPooled() {
+		ok, err := qs.limitRepo.IncrementUsageByUserID(ctx, userID, bytes, limit.BytesLimit)
+		if err != nil {
+			return errors.New("failed to update user usage")
+		}
+		if !ok {
+			return errors.New("upload limit reached")
+		}
+		return nil
+	}
+
+	memberCap := int64(-1)
+	if limit.MemberBytesLimit > 0 {
+		memberCap = limit.MemberBytesLimit
+	}
+	ok, err := qs.limitRepo.IncrementUsageByUserID(ctx, userID, bytes, memberCap)
+	if err != nil {
+		return errors.New("failed to update user usage")
+	}
+	if !ok {
+		return errors.New("member storage limit reached")
+	}
+
+	orgID := limit.OrganizationID.Hex()
+	pool, err := qs.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err == nil && pool != nil {
+		ok, err = qs.limitRepo.IncrementUsageByOrganizationID(ctx, orgID, bytes, pool.BytesLimit)
+	}
+	if err != nil || pool == nil || !ok {
+		// Give the member's share back so both levels stay in step
+		if _, err := qs.limitRepo.IncrementUsageByUserID(ctx, userID, -bytes, -1); err != nil {
+			logger.Log.Error("failed to roll back member usage", logger.Error(err))
+		}
+		if err == nil && pool != nil {
+			return errors.New("organization storage limit reached")
+		}
+		return errors.New("failed to update organization usage")
+	}
+
+	return nil
+}
+
+// ReleaseStorage gives back bytes that are no longer stored
+func (qs *QuotaService) ReleaseStorage(ctx context.Context, userID string, bytes int64) error {
+	limit, err := qs.readUserLimit(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qs.limitRepo.IncrementUsageByUserID(ctx, userID, -bytes, -1); err != nil {
+		return errors.New("failed to update user usage")
+	}
+	if limit.IsPooled() {
+		if _, err := qs.limitRepo.IncrementUsageByOrganizationID(ctx, limit.OrganizationID.Hex(), -bytes, -1); err != nil {
+			return errors.New("failed to update organization usage")
+		}
+	}
+
+	return nil
+}
+
+// SetOrganizationLimit sets the size of the organization's shared pool. The first call creates the pool
+// and moves every member onto it, counting what they already store.
+func (qs *QuotaService) SetOrganizationLimit(ctx context.Context, orgID string, bytesLimit int64) (*model.Limit, error) {
+	return qs.setOrganizationLimit(ctx, orgID, bytesLimit, false)
+}
+
+// ResizeOrganizationPool sets the pool size a subscription plan grants, creating the pool when needed.
+// A downgrade below what is stored is kept, members can't upload until they are back under it.
+func (qs *QuotaService) ResizeOrganizationPool(ctx context.Context, orgID string, bytesLimit int64) (*model.Limit, error) {
+	return qs.setOrganizationLimit(ctx, orgID, bytesLimit, true)
+}
+
+// ResizeUserLimit sets the storage a subscription plan grants the user, even below what they store.
+// Pooled members keep it for when they leave their organization.
+func (qs *QuotaService) ResizeUserLimit(ctx context.Context, userID string, bytesLimit int64) error {
+	if _, err := qs.readUserLimit(ctx, userID); err != nil {
+		return err
+	}
+	if err := qs.limitRepo.UpdateByUserID(ctx, userID, bson.M{"bytes_limit": bytesLimit}); err != nil {
+		return errors.New("failed to update user limit")
+	}
+	return nil
+}
+
+// setOrganizationLimit creates or resizes the pool, refusing a size below the usage unless allowOverage is set
+func (qs *QuotaService) setOrganizationLimit(ctx context.Context, orgID string, bytesLimit int64, allowOverage bool) (*model.Limit, error) {
+	org, err := qs.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	pool, err := qs.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization limit")
+	}
+	if pool != nil {
+		if pool.BytesUsage > bytesLimit && !allowOverage {
+			return nil, errors.New("organization usage exceeds new limit")
+		}
+		if err := qs.limitRepo.UpdateByOrganizationID(ctx, orgID, bson.M{"bytes_limit": bytesLimit}); err != nil {
+			return nil, errors.New("failed to update organization limit")
+		}
+		pool.BytesLimit = bytesLimit
+		return pool, nil
+	}
+
+	members, err := qs.orgUserRoleRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization members")
+	}
+	memberLimits := []*model.Limit{}
+	usage := int64(0)
+	for _, member := range members {
+		limit, err := qs.limitRepo.ReadByUserID(ctx, member.UserID.Hex())
+		if err != nil {
+			return nil, errors.New("failed to retrieve user limit")
+		}
+		if limit == nil {
+			continue
+		}
+		memberLimits = append(memberLimits, limit)
+		usage += limit.BytesUsage
+	}
+	if usage > bytesLimit && !allowOverage {
+		return nil, errors.New("organization usage exceeds new limit")
+	}
+
+	pool = &model.Limit{
+		UserID:         org.ID,
+		OrganizationID: &org.ID,
+		BytesLimit:     bytesLimit,
+		BytesUsage:     usage,
+	}
+	if err := qs.limitRepo.Create(ctx, pool); err != nil {
+		return nil, errors.New("failed to create organization limit")
+	}
+	for _, limit := range memberLimits {
+		if err := qs.limitRepo.UpdateByUserID(ctx, limit.UserID.Hex(), bson.M{"organization_id": org.ID}); err != nil {
+			return nil, errors.New("failed to update user limit")
+		}
+	}
+
+	return pool, nil
+}
+
+// SetMemberLimit caps how much of the pool one member can use, 0 removes the cap
+func (qs *QuotaService) SetMemberLimit(ctx context.Context, orgID, userID string, bytesLimit int64) error {
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return errors.New("user is not a member")
+	}
+	member, err := qs.orgUserRoleRepo.ReadByUserIDOrganizationID(ctx, userID, orgID)
+	if err != nil {
+		return errors.New("error checking existing member")
+	}
+	if member == nil {
+		return errors.New("user is not a member")
+	}
+
+	pool, err := qs.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return errors.New("failed to retrieve organization limit")
+	}
+	if pool == nil {
+		return errors.New("organization has no shared storage")
+	}
+	if bytesLimit > pool.BytesLimit {
+		return errors.New("member limit exceeds organization limit")
+	}
+
+	limit, err := qs.readUserLimit(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if bytesLimit > 0 && limit.BytesUsage > bytesLimit {
+		return errors.New("member usage exceeds new limit")
+	}
+
+	if err := qs.limitRepo.UpdateByUserID(ctx, userID, bson.M{"member_bytes_limit": bytesLimit}); err != nil {
+		return errors.New("failed to update user limit")
+	}
+	return nil
+}
+
+// GetOrganizationUsage returns the organization's pool, nil when it has none, and what each member stores
+func (qs *QuotaService) GetOrganizationUsage(ctx context.Context, orgID string) (*model.Limit, []MemberStorageUsage, error) {
+	pool, err := qs.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve organization limit")
+	}
+
+	members, err := qs.orgUserRoleRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve organization members")
+	}
+
+	usage := make([]MemberStorageUsage, 0, len(members))
+	for _, member := range members {
+		limit, err := qs.limitRepo.ReadByUserID(ctx, member.UserID.Hex())
+		if err != nil {
+			return nil, nil, errors.New("failed to retrieve user limit")
+		}
+		if limit == nil {
+			continue
+		}
+		user, err := qs.userRepo.Read(ctx, member.UserID.Hex())
+		if err != nil {
+			return nil, nil, errors.New("failed to retrieve user")
+		}
+
+		memberUsage := MemberStorageUsage{
+			UserID:     member.UserID,
+			BytesUsage: limit.BytesUsage,
+			BytesLimit: limit.MemberBytesLimit,
+			IsPooled:   limit.IsPooled(),
+		}
+		if !memberUsage.IsPooled {
+			memberUsage.BytesLimit = limit.BytesLimit
+		}
+		if user != nil {
+			memberUsage.Email = user.Email
+		}
+		usage = append(usage, memberUsage)
+	}
+
+	return pool, usage, nil
+}
+
+// JoinOrganizationPool moves a new member onto the organization's pool, if it has one
+func (qs *QuotaService) JoinOrganizationPool(ctx context.Context, orgID, userID string) error {
+	pool, err := qs.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return errors.New("failed to retrieve organization limit")
+	}
+	if pool == nil {
+		return nil
+	}
+
+	limit, err := qs.readUserLimit(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limit.IsPooled() {
+		return nil
+	}
+
+	// What the member already stores counts against the pool, even past its limit
+	if _, err := qs.limitRepo.IncrementUsageByOrganizationID(ctx, orgID, limit.BytesUsage, -1); err != nil {
+		return errors.New("failed to update organization usage")
+	}
+	if err := qs.limitRepo.UpdateByUserID(ctx, userID, bson.M{"organization_id": pool.OrganizationID, "member_bytes_limit": 0}); err != nil {
+		return errors.New("failed to update user limit")
+	}
+	return nil
+}
+
+// LeaveOrganizationPool takes a member's usage out of their organization's pool,
+// the user is back on their own limit
+func (qs *QuotaService) LeaveOrganizationPool(ctx context.Context, userID string) error {
+	limit, err := qs.limitRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		return errors.New("failed to retrieve user limit")
+	}
+	if limit == nil || !limit.IsPooled() {
+		return nil
+	}
+
+	// Unpool the member first, so a retry after a partial run never takes their usage off the pool twice
+	if err := qs.limitRepo.UpdateByUserID(ctx, userID, bson.M{"organization_id": nil, "member_bytes_limit": 0}); err != nil {
+		return errors.New("failed to update user limit")
+	}
+	if _, err := qs.limitRepo.IncrementUsageByOrganizationID(ctx, limit.OrganizationID.Hex(), -limit.BytesUsage, -1); err != nil {
+		return errors.New("failed to update organization usage")
+	}
+	return nil
+}
+
+// readUserLimit reads the user's limit, which every user has
+func (qs *QuotaService) readUserLimit(ctx context.Context, userID string) (*model.Limit, error) {
+	limit, err := qs.limitRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		logger.Log.Error("failed to retrieve user limit", logger.Error(err))
+		return nil, errors.New("failed to retrieve user limit")
+	}
+	if limit == nil {
+		return nil, errors.New("user limit not found")
+	}
+	return limit, nil
+}
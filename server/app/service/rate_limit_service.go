@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/config"
+)
+
+// slidingWindowScript drops hits older than the window, records the hit if the limit allows it and
+// returns whether it was allowed, the hits in the window and milliseconds until the oldest one expires
+const slidingWindowScript = `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`
+
+// RateLimitResult describes the state of a key after a request was counted
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	Window    time.Duration
+	Reset     time.Duration
+}
+
+// RateLimitService counts requests per key in a Redis sliding window
+type RateLimitService struct {
+	redisProvider *provider.RedisProvider
+}
+
+// NewRateLimitService initializes a new RateLimitService
+func NewRateLimitService(redisProvider *provider.RedisProvider) *RateLimitService {
+	return &RateLimitService{
+		redisProvider: redisProvider,
+	}
+}
+
+// Policy returns the named policy and the rule that applies to callers on the subscription plan, nil for anonymous callers
+func (rls *RateLimitService) Policy(policyName string, plan *model.SubscriptionPlan) (*config.RateLimitPolicy, *config.RateLimitRule, error) {
+	rateLimitConfig := config.LoadRateLimitConfig()
+	if !rateLimitConfig.Enabled {
+		return nil, nil, nil
+	}
+
+	policy, ok := rateLimitConfig.Policies[policyName]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown rate limit policy %q", policyName)
+	}
+
+	rule := policy.Rule
+	if plan != nil {
+		if planRule, ok := plan.RateLimits[policyName]; ok {
+			rule = config.RateLimitRule{Limit: planRule.Limit, Window: time.Duration(planRule.WindowSeconds) * time.Second}
+		}
+	}
+	return &policy, &rule, nil
+}
+
+// Hit counts a request for the key under the policy and reports whether it is within the rule
+func (rls *RateLimitService) Hit(ctx context.Context, policyName, key string, rule config.RateLimitRule) (*RateLimitResult, error) {
+	member, err := helper.GenerateCode(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate request ID: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	raw, err := rls.redisProvider.Eval(ctx, slidingWindowScript, []string{fmt.Sprintf("rate_limit:%s:%s", policyName, key)},
+		now, rule.Window.Milliseconds(), rule.Limit, fmt.Sprintf("%d-%s", now, member))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count request: %w", err)
+	}
+
+	values, ok := raw.([]any)
+	if !ok || len(values) != 3 {
+		return nil, errors.New("unexpected rate limit script result")
+	}
+	allowed, _ := values[0].(int64)
+	count, _ := values[1].(int64)
+	reset, _ := values[2].(int64)
+
+	return &RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     rule.Limit,
+		Remaining: max(rule.Limit-count, 0),
+		Window:    rule.Window,
+		Reset:     time.Duration(reset) * time.Millisecond,
+	}, nil
+}
@@ -2,29 +2,81 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"bongaquino/server/app/dto"
 	"bongaquino/server/app/helper"
 	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ServiceAccountService struct {
-	serviceAccountRepo *repository.ServiceAccountRepository
-	userRepo           *repository.UserRepository
-	limitRepo          *repository.LimitRepository
+	serviceAccountRepo  *repository.ServiceAccountRepository
+	userRepo            *repository.UserRepository
+	limitRepo           *repository.LimitRepository
+	directoryRepo       *repository.DirectoryRepository
+	teamDriveRepo       *repository.TeamDriveRepository
+	policyService       *PolicyService
+	subscriptionService *SubscriptionService
+	emailService        *EmailService
+	jwtProvider         *provider.JWTProvider
+	redisProvider       *provider.RedisProvider
+}
+
+// ServiceAccountKeyFile is the downloadable JSON key, the private key is only ever part of this response
+type ServiceAccountKeyFile struct {
+	Type       string `json:"type"`
+	ClientID   string `json:"client_id"`
+	KeyID      string `json:"key_id"`
+	Algorithm  string `json:"algorithm"`
+	PrivateKey string `json:"private_key"`
+	TokenURI   string `json:"token_uri"`
+}
+
+// RotatedCredentials holds the credential issued by a rotation, either a client secret or a key file
+type RotatedCredentials struct {
+	ClientSecret string
+	KeyFile      *ServiceAccountKeyFile
+	ID           string
+	ExpiresAt    *time.Time
 }
 
 func NewServiceAccountService(
 	serviceAccountRepo *repository.ServiceAccountRepository,
 	userRepo *repository.UserRepository,
 	limitRepo *repository.LimitRepository,
+	directoryRepo *repository.DirectoryRepository,
+	teamDriveRepo *repository.TeamDriveRepository,
+	policyService *PolicyService,
+	subscriptionService *SubscriptionService,
+	emailService *EmailService,
+	jwtProvider *provider.JWTProvider,
+	redisProvider *provider.RedisProvider,
 ) *ServiceAccountService {
 	return &ServiceAccountService{
-		serviceAccountRepo: serviceAccountRepo,
-		userRepo:           userRepo,
-		limitRepo:          limitRepo,
+		serviceAccountRepo:  serviceAccountRepo,
+		userRepo:            userRepo,
+		limitRepo:           limitRepo,
+		directoryRepo:       directoryRepo,
+		teamDriveRepo:       teamDriveRepo,
+		policyService:       policyService,
+		subscriptionService: subscriptionService,
+		emailService:        emailService,
+		jwtProvider:         jwtProvider,
+		redisProvider:       redisProvider,
 	}
 }
 
@@ -42,6 +94,41 @@ func GenerateClientCredentials() (string, string, error) {
 	return clientID, clientSecret, nil
 }
 
+// GenerateServiceAccountKey mints a key pair for a client, returning the key file and the PEM encoded public key to store
+func GenerateServiceAccountKey(clientID string) (*ServiceAccountKeyFile, string, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	keyID, err := helper.GenerateCode(8)
+	if err != nil {
+		return nil, "", err
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, serviceAccountConfig.KeyBits)
+	if err != nil {
+		return nil, "", err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keyFile := &ServiceAccountKeyFile{
+		Type:       "service_account",
+		ClientID:   clientID,
+		KeyID:      strings.ToLower(keyID),
+		Algorithm:  serviceAccountConfig.KeyAlgorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		TokenURI:   serviceAccountConfig.TokenURI,
+	}
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	return keyFile, publicKey, nil
+}
+
 func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, request *dto.GenerateServiceAccountDTO) (*model.ServiceAccount, error) {
 	// Convert userID string to primitive.ObjectID
 	objectID, err := primitive.ObjectIDFromHex(*request.UserID)
@@ -49,12 +136,72 @@ func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, reques
 		return nil, err
 	}
 
+	// Organization service accounts are shared by the organization's administrators and act as one of its
+	// team drives, so no member's own files are reachable through them and they outlive their creator
+	var orgObjectID primitive.ObjectID
+	var createdBy *primitive.ObjectID
+	actingID := *request.UserID
+	if request.OrganizationID != nil {
+		orgObjectID, err = primitive.ObjectIDFromHex(*request.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if request.DriveID == "" {
+			return nil, errors.New("drive ID is required")
+		}
+		if _, err := primitive.ObjectIDFromHex(request.DriveID); err != nil {
+			return nil, errors.New("team drive not found")
+		}
+		drive, err := s.teamDriveRepo.ReadByIDOrganizationID(ctx, request.DriveID, *request.OrganizationID)
+		if err != nil {
+			return nil, errors.New("failed to retrieve team drive")
+		}
+		if drive == nil {
+			return nil, errors.New("team drive not found")
+		}
+		createdBy = &objectID
+		objectID = drive.ID
+		actingID = drive.ID.Hex()
+	}
+
+	// Plans cap how many service accounts a user or an organization keeps
+	if err := s.subscriptionService.CheckServiceAccountQuota(ctx, *request.UserID, request.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	// Resolve the chosen policy, falling back to the default service account policy
+	policy, err := s.policyService.ResolvePolicy(ctx, request.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scopes may only name directories of the user or drive the account acts as
+	directoryScopes, err := s.resolveDirectoryScopes(ctx, actingID, request.DirectoryScopes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create service account
 	serviceAccount := &model.ServiceAccount{
-		UserID:       objectID,
-		Name:         request.Name,
-		ClientID:     *request.ClientID,
-		ClientSecret: *request.ClientSecret,
+		UserID:          objectID,
+		OrganizationID:  orgObjectID,
+		CreatedBy:       createdBy,
+		Name:            request.Name,
+		ClientID:        *request.ClientID,
+		DirectoryScopes: directoryScopes,
+		PolicyID:        policy.ID,
+	}
+
+	// Only the hash of a secret and the public half of a key are stored
+	if request.ClientSecret != nil {
+		secret, err := newServiceAccountSecret(*request.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+		serviceAccount.Secrets = []model.ServiceAccountSecret{*secret}
+	}
+	if request.PublicKey != nil && request.KeyID != nil {
+		serviceAccount.Keys = []model.ServiceAccountKey{*newServiceAccountKey(*request.KeyID, *request.PublicKey)}
 	}
 
 	err = s.serviceAccountRepo.Create(ctx, serviceAccount)
@@ -82,5 +229,450 @@ func (s *ServiceAccountService) DeleteServiceAccount(ctx context.Context, userID
 		return err
 	}
 
+	// Reject bearer tokens already issued to the client until they would have expired
+	err = s.jwtProvider.RevokeClientTokens(clientID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke client tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ServiceAccountService) ListOrganizationServiceAccounts(ctx context.Context, orgID string) ([]*model.ServiceAccount, error) {
+	// List service accounts by organization ID
+	serviceAccounts, err := s.serviceAccountRepo.ListByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceAccounts, nil
+}
+
+func (s *ServiceAccountService) DeleteOrganizationServiceAccount(ctx context.Context, orgID string, clientID string) error {
+	// Revoke service account by client ID, scoped to the organization
+	err := s.serviceAccountRepo.DeleteByOrganizationIDClientID(ctx, orgID, clientID)
+	if err != nil {
+		return err
+	}
+
+	// Reject bearer tokens already issued to the client until they would have expired
+	err = s.jwtProvider.RevokeClientTokens(clientID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke client tokens: %w", err)
+	}
+
 	return nil
 }
+
+// Authenticate checks a client secret against the account's unexpired secrets and records the use,
+// the account is returned with the error when it exists so failed attempts can be attributed to its owner
+func (s *ServiceAccountService) Authenticate(ctx context.Context, clientID, clientSecret string) (*model.ServiceAccount, error) {
+	serviceAccount, err := s.serviceAccountRepo.ReadByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.New("failed to validate credentials")
+	}
+	if serviceAccount == nil {
+		return nil, errors.New("invalid client credentials")
+	}
+	if serviceAccount.IsDisabled() {
+		return serviceAccount, errors.New("service account is disabled")
+	}
+	if err := s.checkIdentity(ctx, serviceAccount); err != nil {
+		return serviceAccount, err
+	}
+
+	// Accounts created before rotation have a single secret that never expires
+	secretID := ""
+	matched := serviceAccount.ClientSecret != "" && helper.CheckHash(clientSecret, serviceAccount.ClientSecret)
+	for _, secret := range serviceAccount.Secrets {
+		if !matched && time.Now().Before(secret.ExpiresAt) && helper.CheckHash(clientSecret, secret.Hash) {
+			secretID, matched = secret.ID, true
+		}
+	}
+	if !matched {
+		return serviceAccount, errors.New("invalid client credentials")
+	}
+
+	if err := s.serviceAccountRepo.TouchByClientID(ctx, clientID, secretID); err != nil {
+		return nil, errors.New("failed to update service account")
+	}
+
+	return serviceAccount, nil
+}
+
+// AuthenticateAssertion verifies a JWT assertion signed with one of the account's keys and records the use.
+// The issuer and subject name the client, the audience is the token endpoint and each assertion is accepted once.
+func (s *ServiceAccountService) AuthenticateAssertion(ctx context.Context, assertion string) (*model.ServiceAccount, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	var serviceAccount *model.ServiceAccount
+	var keyID string
+	claims := &jwt.RegisteredClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{serviceAccountConfig.KeyAlgorithm}))
+	_, err := parser.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		account, err := s.serviceAccountRepo.ReadByClientID(ctx, claims.Issuer)
+		if err != nil || account == nil {
+			return nil, errors.New("unknown client")
+		}
+		serviceAccount = account
+
+		for _, key := range account.Keys {
+			if key.ID != kid || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+				continue
+			}
+			keyID = key.ID
+			return jwt.ParseRSAPublicKeyFromPEM([]byte(key.PublicKey))
+		}
+		return nil, errors.New("unknown key")
+	})
+	if err != nil {
+		if serviceAccount != nil && serviceAccount.IsDisabled() {
+			return serviceAccount, errors.New("service account is disabled")
+		}
+		return serviceAccount, errors.New("invalid client credentials")
+	}
+
+	// Short-lived, single-use assertions limit what a leaked one is worth
+	if claims.Subject != claims.Issuer || !claims.VerifyAudience(serviceAccountConfig.TokenURI, true) ||
+		claims.ExpiresAt == nil || claims.ExpiresAt.After(time.Now().Add(serviceAccountConfig.AssertionMaxAge)) || claims.ID == "" {
+		return serviceAccount, errors.New("invalid client credentials")
+	}
+	if serviceAccount.IsDisabled() {
+		return serviceAccount, errors.New("service account is disabled")
+	}
+	if err := s.checkIdentity(ctx, serviceAccount); err != nil {
+		return serviceAccount, err
+	}
+
+	fresh, err := s.redisProvider.SetNX(ctx, fmt.Sprintf("client_assertion:%s:%s", serviceAccount.ClientID, claims.ID), "1", time.Until(claims.ExpiresAt.Time))
+	if err != nil {
+		return nil, errors.New("failed to validate credentials")
+	}
+	if !fresh {
+		return serviceAccount, errors.New("invalid client credentials")
+	}
+
+	if err := s.serviceAccountRepo.TouchKeyByClientID(ctx, serviceAccount.ClientID, keyID); err != nil {
+		return nil, errors.New("failed to update service account")
+	}
+
+	return serviceAccount, nil
+}
+
+// CheckDirectoryScope confines scoped accounts to their directory subtrees. The path runs from the target
+// directory up to the owner's root and the nearest scope decides, access is "read", "write" or "manage".
+func (s *ServiceAccountService) CheckDirectoryScope(ctx context.Context, clientID string, directoryPath []string, access string) error {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	serviceAccount, err := s.serviceAccountRepo.ReadByClientID(ctx, clientID)
+	if err != nil {
+		return errors.New("failed to check directory scope")
+	}
+	if serviceAccount == nil {
+		return errors.New("service account not found")
+	}
+	if len(serviceAccount.DirectoryScopes) == 0 {
+		return nil
+	}
+
+	for _, directoryID := range directoryPath {
+		for _, scope := range serviceAccount.DirectoryScopes {
+			if scope.DirectoryID.Hex() != directoryID {
+				continue
+			}
+			switch {
+			case scope.Access == serviceAccountConfig.ReadWriteAccess,
+				scope.Access == serviceAccountConfig.ReadOnlyAccess && access == "read",
+				scope.Access == serviceAccountConfig.WriteOnlyAccess && access == "write":
+				return nil
+			}
+			return errors.New("operation not allowed by directory scope")
+		}
+	}
+
+	// Anything outside the scopes is reported as missing so its existence is not revealed
+	return errors.New("directory not found")
+}
+
+// RotateServiceAccountSecret issues a new secret, or a new key for key accounts, for one of the user's service accounts
+func (s *ServiceAccountService) RotateServiceAccountSecret(ctx context.Context, userID, clientID string, gracePeriod time.Duration) (*RotatedCredentials, error) {
+	serviceAccount, err := s.serviceAccountRepo.ReadByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.New("failed to rotate secret")
+	}
+	if serviceAccount == nil || serviceAccount.IsOrganizationAccount() || serviceAccount.UserID.Hex() != userID {
+		return nil, errors.New("service account not found")
+	}
+
+	if serviceAccount.UsesKeys() {
+		return s.rotateKey(ctx, serviceAccount, gracePeriod)
+	}
+	return s.rotateSecret(ctx, serviceAccount, gracePeriod)
+}
+
+// RotateOrganizationServiceAccountSecret issues a new secret, or a new key for key accounts, for one of the organization's service accounts
+func (s *ServiceAccountService) RotateOrganizationServiceAccountSecret(ctx context.Context, orgID, clientID string, gracePeriod time.Duration) (*RotatedCredentials, error) {
+	serviceAccount, err := s.serviceAccountRepo.ReadByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.New("failed to rotate secret")
+	}
+	if serviceAccount == nil || serviceAccount.OrganizationID.Hex() != orgID {
+		return nil, errors.New("service account not found")
+	}
+
+	if serviceAccount.UsesKeys() {
+		return s.rotateKey(ctx, serviceAccount, gracePeriod)
+	}
+	return s.rotateSecret(ctx, serviceAccount, gracePeriod)
+}
+
+// rotateSecret adds a new secret and keeps the newest previous one for the grace period, older ones are dropped.
+// Rotating also re-enables a disabled account since the new secret has to be deployed anyway.
+func (s *ServiceAccountService) rotateSecret(ctx context.Context, serviceAccount *model.ServiceAccount, gracePeriod time.Duration) (*RotatedCredentials, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	_, clientSecret, err := GenerateClientCredentials()
+	if err != nil {
+		return nil, errors.New("failed to generate client secret")
+	}
+	secret, err := newServiceAccountSecret(clientSecret)
+	if err != nil {
+		return nil, errors.New("failed to generate client secret")
+	}
+
+	// Collect the secrets still in use, newest first, the legacy secret being the oldest
+	graceUntil := time.Now().Add(gracePeriod)
+	previous := []model.ServiceAccountSecret{}
+	for i := len(serviceAccount.Secrets) - 1; i >= 0; i-- {
+		if time.Now().Before(serviceAccount.Secrets[i].ExpiresAt) {
+			previous = append(previous, serviceAccount.Secrets[i])
+		}
+	}
+	if serviceAccount.ClientSecret != "" {
+		previous = append(previous, model.ServiceAccountSecret{
+			ID:        "legacy",
+			Hash:      serviceAccount.ClientSecret,
+			ExpiresAt: graceUntil,
+			CreatedAt: serviceAccount.CreatedAt,
+		})
+	}
+
+	// Keep room for the new secret, the kept ones expire after the grace period at the latest.
+	// The owner has just rotated, so no expiry warning is sent for them.
+	now := time.Now()
+	secrets := []model.ServiceAccountSecret{}
+	for _, kept := range previous {
+		if len(secrets) == serviceAccountConfig.MaxSecrets-1 || gracePeriod <= 0 {
+			break
+		}
+		if kept.ExpiresAt.After(graceUntil) {
+			kept.ExpiresAt = graceUntil
+		}
+		kept.WarnedAt = &now
+		secrets = append(secrets, kept)
+	}
+	secrets = append(secrets, *secret)
+
+	update := bson.M{
+		"secrets":         secrets,
+		"client_secret":   "",
+		"disabled_at":     nil,
+		"disabled_reason": "",
+	}
+	if err := s.serviceAccountRepo.UpdateByClientID(ctx, serviceAccount.ClientID, update); err != nil {
+		return nil, errors.New("failed to rotate secret")
+	}
+
+	return &RotatedCredentials{ClientSecret: clientSecret, ID: secret.ID, ExpiresAt: &secret.ExpiresAt}, nil
+}
+
+// rotateKey mints a new key pair and keeps the newest previous key for the grace period, older ones are dropped
+func (s *ServiceAccountService) rotateKey(ctx context.Context, serviceAccount *model.ServiceAccount, gracePeriod time.Duration) (*RotatedCredentials, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	keyFile, publicKey, err := GenerateServiceAccountKey(serviceAccount.ClientID)
+	if err != nil {
+		return nil, errors.New("failed to generate key")
+	}
+
+	graceUntil := time.Now().Add(gracePeriod)
+	keys := []model.ServiceAccountKey{}
+	for i := len(serviceAccount.Keys) - 1; i >= 0; i-- {
+		kept := serviceAccount.Keys[i]
+		if len(keys) == serviceAccountConfig.MaxSecrets-1 || gracePeriod <= 0 {
+			break
+		}
+		if kept.ExpiresAt != nil && time.Now().After(*kept.ExpiresAt) {
+			continue
+		}
+		if kept.ExpiresAt == nil || kept.ExpiresAt.After(graceUntil) {
+			kept.ExpiresAt = &graceUntil
+		}
+		keys = append(keys, kept)
+	}
+	keys = append(keys, *newServiceAccountKey(keyFile.KeyID, publicKey))
+
+	update := bson.M{
+		"keys":            keys,
+		"disabled_at":     nil,
+		"disabled_reason": "",
+	}
+	if err := s.serviceAccountRepo.UpdateByClientID(ctx, serviceAccount.ClientID, update); err != nil {
+		return nil, errors.New("failed to rotate secret")
+	}
+
+	return &RotatedCredentials{KeyFile: keyFile, ID: keyFile.KeyID}, nil
+}
+
+// WarnExpiringSecrets emails owners whose secrets expire within the warning period, once per secret
+func (s *ServiceAccountService) WarnExpiringSecrets(ctx context.Context) error {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	deadline := time.Now().Add(serviceAccountConfig.ExpiryWarning)
+	serviceAccounts, err := s.serviceAccountRepo.ListWithSecretsExpiringBefore(ctx, deadline)
+	if err != nil {
+		return err
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		owner, err := s.userRepo.Read(ctx, notifiedUserID(serviceAccount))
+		if err != nil || owner == nil {
+			continue
+		}
+
+		for _, secret := range serviceAccount.Secrets {
+			if secret.WarnedAt != nil || !secret.ExpiresAt.After(time.Now()) || secret.ExpiresAt.After(deadline) {
+				continue
+			}
+
+			if err := s.emailService.SendServiceAccountSecretExpiring(owner.Email, serviceAccount.Name, serviceAccount.ClientID, secret.ExpiresAt); err != nil {
+				logger.Log.Error("failed to send secret expiry warning", logger.Error(err))
+				continue
+			}
+			if err := s.serviceAccountRepo.MarkSecretWarnedByClientID(ctx, serviceAccount.ClientID, secret.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DisableInactiveAccounts disables accounts unused for longer than the inactivity limit and tells their owners
+func (s *ServiceAccountService) DisableInactiveAccounts(ctx context.Context) error {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	serviceAccounts, err := s.serviceAccountRepo.ListInactiveSince(ctx, time.Now().Add(-serviceAccountConfig.InactivityLimit))
+	if err != nil {
+		return err
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		update := bson.M{
+			"disabled_at":     time.Now(),
+			"disabled_reason": "inactive",
+		}
+		if err := s.serviceAccountRepo.UpdateByClientID(ctx, serviceAccount.ClientID, update); err != nil {
+			return err
+		}
+
+		// Reject bearer tokens already issued to the client until they would have expired
+		err = s.jwtProvider.RevokeClientTokens(serviceAccount.ClientID)
+		if err != nil {
+			logger.Log.Error("failed to revoke client tokens", logger.Error(err))
+		}
+
+		owner, err := s.userRepo.Read(ctx, notifiedUserID(serviceAccount))
+		if err != nil || owner == nil {
+			continue
+		}
+		if err := s.emailService.SendServiceAccountDisabled(owner.Email, serviceAccount.Name, serviceAccount.ClientID, serviceAccountConfig.InactivityLimit); err != nil {
+			logger.Log.Error("failed to send service account disabled notice", logger.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// checkIdentity rejects organization accounts that do not act as one of their organization's team drives,
+// those created before organization accounts had their own identity act as a member and must be recreated
+func (s *ServiceAccountService) checkIdentity(ctx context.Context, serviceAccount *model.ServiceAccount) error {
+	if !serviceAccount.IsOrganizationAccount() {
+		return nil
+	}
+
+	drive, err := s.teamDriveRepo.ReadByIDOrganizationID(ctx, serviceAccount.UserID.Hex(), serviceAccount.OrganizationID.Hex())
+	if err != nil {
+		return errors.New("failed to validate credentials")
+	}
+	if drive == nil {
+		return errors.New("invalid client credentials")
+	}
+	return nil
+}
+
+// notifiedUserID is who hears about the account's secrets and state, the creator of an organization account
+func notifiedUserID(serviceAccount *model.ServiceAccount) string {
+	if serviceAccount.CreatedBy != nil {
+		return serviceAccount.CreatedBy.Hex()
+	}
+	return serviceAccount.UserID.Hex()
+}
+
+// newServiceAccountSecret hashes a client secret into a secret valid for the configured lifetime
+func newServiceAccountSecret(clientSecret string) (*model.ServiceAccountSecret, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	id, err := helper.GenerateCode(4)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := helper.Hash(clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ServiceAccountSecret{
+		ID:        id,
+		Hash:      hash,
+		ExpiresAt: time.Now().Add(serviceAccountConfig.SecretLifetime),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// newServiceAccountKey wraps a PEM encoded public key, keys stay valid until rotated out
+func newServiceAccountKey(keyID, publicKey string) *model.ServiceAccountKey {
+	return &model.ServiceAccountKey{
+		ID:        keyID,
+		Algorithm: config.LoadServiceAccountConfig().KeyAlgorithm,
+		PublicKey: publicKey,
+		CreatedAt: time.Now(),
+	}
+}
+
+// resolveDirectoryScopes checks the requested directories belong to the user and applies the default access
+func (s *ServiceAccountService) resolveDirectoryScopes(ctx context.Context, userID string, requested []dto.DirectoryScopeDTO) ([]model.ServiceAccountDirectoryScope, error) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+
+	scopes := []model.ServiceAccountDirectoryScope{}
+	for _, scope := range requested {
+		if _, err := primitive.ObjectIDFromHex(scope.DirectoryID); err != nil {
+			return nil, errors.New("directory not found")
+		}
+		directory, err := s.directoryRepo.ReadByIDUserID(ctx, scope.DirectoryID, userID)
+		if err != nil {
+			return nil, errors.New("failed to read directory")
+		}
+		if directory == nil || directory.IsDeleted {
+			return nil, errors.New("directory not found")
+		}
+
+		access := scope.Access
+		if access == "" {
+			access = serviceAccountConfig.ReadWriteAccess
+		}
+		scopes = append(scopes, model.ServiceAccountDirectoryScope{DirectoryID: directory.ID, Access: access})
+	}
+	return scopes, nil
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ssoState is kept in Redis between redirecting to the IdP and its callback
+type ssoState struct {
+	OrganizationID string `json:"organization_id"`
+	Nonce          string `json:"nonce"`
+	CodeVerifier   string `json:"code_verifier"`
+}
+
+// idTokenClaims holds the ID token claims used to sign users in
+type idTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	AuthorizedBy  string `json:"azp"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // Some IdPs send "true" as a string
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	jwt.RegisteredClaims
+}
+
+// SSOService signs users in through their organization's OpenID Connect identity provider
+type SSOService struct {
+	ssoRepo             *repository.OrganizationSSORepository
+	orgRepo             *repository.OrganizationRepository
+	orgUserRoleRepo     *repository.OrganizationUserRoleRepository
+	userRepo            *repository.UserRepository
+	roleRepo            *repository.RoleRepository
+	userService         *UserService
+	organizationService *OrganizationService
+	loginEvents         *LoginEventService
+	oidcProvider        *provider.OIDCProvider
+	jwtProvider         *provider.JWTProvider
+	redisProvider       *provider.RedisProvider
+}
+
+// NewSSOService initializes a new SSOService
+func NewSSOService(ssoRepo *repository.OrganizationSSORepository, orgRepo *repository.OrganizationRepository, orgUserRoleRepo *repository.OrganizationUserRoleRepository, userRepo *repository.UserRepository, roleRepo *repository.RoleRepository, userService *UserService, organizationService *OrganizationService, loginEvents *LoginEventService, oidcProvider *provider.OIDCProvider, jwtProvider *provider.JWTProvider, redisProvider *provider.RedisProvider) *SSOService {
+	return &SSOService{
+		ssoRepo:             ssoRepo,
+		orgRepo:             orgRepo,
+		orgUserRoleRepo:     orgUserRoleRepo,
+		userRepo:            userRepo,
+		roleRepo:            roleRepo,
+		userService:         userService,
+		organizationService: organizationService,
+		loginEvents:         loginEvents,
+		oidcProvider:        oidcProvider,
+		jwtProvider:         jwtProvider,
+		redisProvider:       redisProvider,
+	}
+}
+
+// ReadConfig returns the organization's identity provider configuration
+func (ss *SSOService) ReadConfig(ctx context.Context, orgID string) (*model.OrganizationSSO, error) {
+	sso, err := ss.ssoRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("error fetching SSO configuration")
+	}
+	if sso == nil {
+		return nil, errors.New("SSO is not configured")
+	}
+	return sso, nil
+}
+
+// UpdateConfig creates or replaces the organization's identity provider configuration after checking the issuer is reachable
+func (ss *SSOService) UpdateConfig(ctx context.Context, orgID string, request *dto.UpdateOrganizationSSODTO) (*model.OrganizationSSO, error) {
+	org, err := ss.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		logger.Log.Error("error fetching organization", logger.Error(err))
+		return nil, errors.New("error fetching organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	issuer := strings.TrimSuffix(request.Issuer, "/")
+	if !issuerAllowed(issuer) {
+		return nil, errors.New("issuer must use https on a public host")
+	}
+	if _, err := ss.discover(ctx, issuer); err != nil {
+		logger.Log.Error("error discovering issuer", logger.Error(err))
+		return nil, errors.New("issuer discovery failed")
+	}
+
+	var domains []string
+	for _, domain := range request.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("allowed domains are required")
+	}
+
+	var defaultRoleID primitive.ObjectID
+	if request.DefaultRoleID != "" {
+		role, err := ss.roleRepo.Read(ctx, request.DefaultRoleID)
+		if err != nil || role == nil {
+			return nil, errors.New("role not found")
+		}
+		if _, ok := config.LoadOrganizationConfig().RoleRanks[role.Name]; !ok {
+			return nil, errors.New("role is not an organization role")
+		}
+		defaultRoleID = role.ID
+	}
+
+	existing, err := ss.ssoRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("error fetching SSO configuration")
+	}
+
+	var clientSecret string
+	if request.ClientSecret != "" {
+		clientSecret, err = helper.Encrypt(request.ClientSecret)
+		if err != nil {
+			logger.Log.Error("error encrypting client secret", logger.Error(err))
+			return nil, errors.New("failed to encrypt client secret")
+		}
+	} else if existing != nil {
+		clientSecret = existing.ClientSecret
+	} else {
+		return nil, errors.New("client secret is required")
+	}
+
+	if existing == nil {
+		sso := &model.OrganizationSSO{
+			OrganizationID: org.ID,
+			Issuer:         issuer,
+			ClientID:       request.ClientID,
+			ClientSecret:   clientSecret,
+			AllowedDomains: domains,
+			DefaultRoleID:  defaultRoleID,
+			IsEnabled:      request.IsEnabled,
+		}
+		if err := ss.ssoRepo.Create(ctx, sso); err != nil {
+			return nil, errors.New("error saving SSO configuration")
+		}
+		return sso, nil
+	}
+
+	err = ss.ssoRepo.Update(ctx, existing.ID, bson.M{
+		"issuer":          issuer,
+		"client_id":       request.ClientID,
+		"client_secret":   clientSecret,
+		"allowed_domains": domains,
+		"default_role_id": defaultRoleID,
+		"is_enabled":      request.IsEnabled,
+	})
+	if err != nil {
+		return nil, errors.New("error saving SSO configuration")
+	}
+	return ss.ssoRepo.ReadByOrganizationID(ctx, orgID)
+}
+
+// DeleteConfig removes the organization's identity provider
+func (ss *SSOService) DeleteConfig(ctx context.Context, orgID string) error {
+	if _, err := ss.ReadConfig(ctx, orgID); err != nil {
+		return err
+	}
+	if err := ss.ssoRepo.DeleteByOrganizationID(ctx, orgID); err != nil {
+		return errors.New("error deleting SSO configuration")
+	}
+	return nil
+}
+
+// Begin starts an authorization code flow with PKCE for the organization, or for the one whose SSO accepts the email's domain
+func (ss *SSOService) Begin(ctx context.Context, orgID, email string) (string, error) {
+	ssoConfig := config.LoadSSOConfig()
+
+	var sso *model.OrganizationSSO
+	var err error
+	if orgID != "" {
+		sso, err = ss.ssoRepo.ReadByOrganizationID(ctx, orgID)
+	} else if domain := emailDomain(email); domain != "" {
+		sso, err = ss.ssoRepo.ReadEnabledByDomain(ctx, domain)
+	}
+	if err != nil {
+		return "", errors.New("error fetching SSO configuration")
+	}
+	if sso == nil || !sso.IsEnabled {
+		return "", errors.New("SSO is not available")
+	}
+
+	discovery, err := ss.discover(ctx, sso.Issuer)
+	if err != nil {
+		logger.Log.Error("error discovering issuer", logger.Error(err))
+		return "", errors.New("identity provider unavailable")
+	}
+
+	state, err := randomURLString(32)
+	if err != nil {
+		return "", errors.New("failed to generate state")
+	}
+	nonce, err := randomURLString(32)
+	if err != nil {
+		return "", errors.New("failed to generate nonce")
+	}
+	codeVerifier, err := randomURLString(32)
+	if err != nil {
+		return "", errors.New("failed to generate code verifier")
+	}
+
+	stored, err := json.Marshal(ssoState{
+		OrganizationID: sso.OrganizationID.Hex(),
+		Nonce:          nonce,
+		CodeVerifier:   codeVerifier,
+	})
+	if err != nil {
+		return "", errors.New("failed to store state")
+	}
+	if err := ss.redisProvider.Set(ctx, fmt.Sprintf("sso_state:%s", helper.HashToken(state)), string(stored), ssoConfig.StateExpiry); err != nil {
+		return "", errors.New("failed to store state")
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {sso.ClientID},
+		"redirect_uri":          {ssoConfig.RedirectURL},
+		"scope":                 {strings.Join(ssoConfig.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {pkceChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	if email != "" {
+		query.Set("login_hint", email)
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Complete redeems the IdP's authorization code, validates the ID token and signs in the matching or newly provisioned
+// user, the user is returned so callers can still ask for a second factor
+func (ss *SSOService) Complete(ctx context.Context, code, state string) (user *model.User, accessToken string, refreshToken string, err error) {
+	var email string
+	defer func() {
+		var userID *primitive.ObjectID
+		if user != nil {
+			userID = &user.ID
+		}
+		ss.loginEvents.Record(ctx, "sso", userID, email, err)
+	}()
+
+	// States are single-use
+	key := fmt.Sprintf("sso_state:%s", helper.HashToken(state))
+	stored, err := ss.redisProvider.GetDel(ctx, key)
+	if err != nil || stored == "" {
+		return nil, "", "", errors.New("invalid or expired state")
+	}
+	var pending ssoState
+	if err := json.Unmarshal([]byte(stored), &pending); err != nil {
+		return nil, "", "", errors.New("invalid or expired state")
+	}
+
+	sso, err := ss.ssoRepo.ReadByOrganizationID(ctx, pending.OrganizationID)
+	if err != nil {
+		return nil, "", "", errors.New("error fetching SSO configuration")
+	}
+	if sso == nil || !sso.IsEnabled {
+		return nil, "", "", errors.New("SSO is not available")
+	}
+
+	discovery, err := ss.discover(ctx, sso.Issuer)
+	if err != nil {
+		logger.Log.Error("error discovering issuer", logger.Error(err))
+		return nil, "", "", errors.New("identity provider unavailable")
+	}
+
+	claims, err := ss.redeemCode(ctx, sso, discovery, code, &pending)
+	if err != nil {
+		return nil, "", "", err
+	}
+	email = claims.Email
+
+	// Only verified emails in the allowed domains may map to accounts
+	if !emailVerified(claims) {
+		return nil, "", "", errors.New("email is not verified by the identity provider")
+	}
+	if !slices.Contains(sso.AllowedDomains, emailDomain(claims.Email)) {
+		return nil, "", "", errors.New("email domain is not allowed")
+	}
+
+	user, err = ss.resolveUser(ctx, sso, claims)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	// Check if user account is locked due to too many failed login attempts
+	if user.IsLockActive() {
+		return user, "", "", errors.New("account locked due to multiple failed login attempts")
+	}
+
+	// Generate tokens
+	accessToken, refreshToken, err = ss.jwtProvider.GenerateTokens(user.ID.Hex(), &user.Email, nil)
+	if err != nil {
+		return user, "", "", errors.New("failed to generate tokens")
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// discover fetches the issuer's discovery document and rejects endpoints the server should not call, the document
+// is controlled by whoever configured the issuer
+func (ss *SSOService) discover(ctx context.Context, issuer string) (*provider.OIDCDiscovery, error) {
+	if !issuerAllowed(issuer) {
+		return nil, fmt.Errorf("issuer %q is not allowed", issuer)
+	}
+	discovery, err := ss.oidcProvider.Discover(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range []string{discovery.AuthorizationEndpoint, discovery.TokenEndpoint, discovery.JWKSURI} {
+		if !issuerAllowed(endpoint) {
+			return nil, fmt.Errorf("discovery endpoint %q is not allowed", endpoint)
+		}
+	}
+	return discovery, nil
+}
+
+// redeemCode exchanges the authorization code with the PKCE verifier and returns the validated ID token claims
+func (ss *SSOService) redeemCode(ctx context.Context, sso *model.OrganizationSSO, discovery *provider.OIDCDiscovery, code string, pending *ssoState) (*idTokenClaims, error) {
+	ssoConfig := config.LoadSSOConfig()
+
+	clientSecret, err := helper.Decrypt(sso.ClientSecret)
+	if err != nil {
+		logger.Log.Error("error decrypting client secret", logger.Error(err))
+		return nil, errors.New("failed to decrypt client secret")
+	}
+
+	tokenResponse, err := ss.oidcProvider.ExchangeCode(ctx, discovery.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {ssoConfig.RedirectURL},
+		"client_id":     {sso.ClientID},
+		"client_secret": {clientSecret},
+		"code_verifier": {pending.CodeVerifier},
+	})
+	if err != nil {
+		logger.Log.Error("error exchanging authorization code", logger.Error(err))
+		return nil, errors.New("authorization code exchange failed")
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, errors.New("identity provider returned no ID token")
+	}
+
+	claims, err := ss.validateIDToken(ctx, sso, discovery, tokenResponse.IDToken, pending.Nonce)
+	if err != nil {
+		logger.Log.Error("invalid ID token", logger.Error(err))
+		return nil, errors.New("invalid ID token")
+	}
+	return claims, nil
+}
+
+// validateIDToken checks the ID token's signature against the issuer's JWKS and its issuer, audience, expiry and nonce
+func (ss *SSOService) validateIDToken(ctx context.Context, sso *model.OrganizationSSO, discovery *provider.OIDCDiscovery, idToken, nonce string) (*idTokenClaims, error) {
+	keyFunc := func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, refresh := range []bool{false, true} {
+			keys, err := ss.oidcProvider.JWKS(ctx, discovery.JWKSURI, refresh)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range keys {
+				if (kid == "" || key.Kid == kid) && (key.Use == "" || key.Use == "sig") && (key.Alg == "" || key.Alg == token.Method.Alg()) {
+					return key.PublicKey()
+				}
+			}
+		}
+		return nil, fmt.Errorf("no signing key %q", kid)
+	}
+
+	var claims idTokenClaims
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}))
+	if _, err := parser.ParseWithClaims(idToken, &claims, keyFunc); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(discovery.Issuer, "/") {
+		return nil, errors.New("issuer mismatch")
+	}
+	if !claims.VerifyAudience(sso.ClientID, true) {
+		return nil, errors.New("audience mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != sso.ClientID {
+		return nil, errors.New("authorized party mismatch")
+	}
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("missing expiry")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	return &claims, nil
+}
+
+// resolveUser maps the IdP identity to an account. The organization must have verified the email's domain, so an IdP
+// can neither take over accounts nor claim addresses on domains the organization does not own. Existing accounts must
+// already belong to the organization, unknown emails are provisioned and join it.
+func (ss *SSOService) resolveUser(ctx context.Context, sso *model.OrganizationSSO, claims *idTokenClaims) (*model.User, error) {
+	ssoConfig := config.LoadSSOConfig()
+
+	org, err := ss.orgRepo.Read(ctx, sso.OrganizationID.Hex())
+	if err != nil || org == nil {
+		return nil, errors.New("error fetching organization")
+	}
+	if org.DomainVerifiedAt == nil || !strings.EqualFold(org.Domain, emailDomain(claims.Email)) {
+		return nil, errors.New("email domain is not verified by the organization")
+	}
+
+	user, err := ss.userRepo.ReadByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, errors.New("error fetching user")
+	}
+
+	if user != nil {
+		member, err := ss.orgUserRoleRepo.ReadByUserIDOrganizationID(ctx, user.ID.Hex(), sso.OrganizationID.Hex())
+		if err != nil {
+			return nil, errors.New("error checking existing member")
+		}
+		if member == nil {
+			return nil, errors.New("account is not a member of the organization")
+		}
+
+		if !user.IsVerified {
+			// The IdP vouched for the email
+			if err := ss.userRepo.Update(ctx, user.ID.Hex(), bson.M{"is_verified": true}); err != nil {
+				return nil, errors.New("failed to verify user")
+			}
+		}
+		return user, nil
+	}
+
+	// Provisioned users sign in through the IdP, the random password is never shared
+	password, err := helper.GenerateCode(32)
+	if err != nil {
+		return nil, errors.New("failed to generate password")
+	}
+	user, _, _, _, err = ss.userService.CreateUser(ctx, &dto.CreateUserDTO{
+		FirstName:  claims.GivenName,
+		LastName:   claims.FamilyName,
+		Email:      claims.Email,
+		Password:   password,
+		Role:       ssoConfig.SystemRole,
+		IsVerified: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roleID := sso.DefaultRoleID.Hex()
+	if sso.DefaultRoleID.IsZero() {
+		role, err := ss.roleRepo.ReadByName(ctx, ssoConfig.DefaultRole)
+		if err != nil || role == nil {
+			return nil, errors.New("role not found")
+		}
+		roleID = role.ID.Hex()
+	}
+	if err := ss.organizationService.AddMember(ctx, sso.OrganizationID.Hex(), user.ID.Hex(), roleID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// emailVerified reports whether the IdP vouched for the email, some IdPs send "true" as a string
+func emailVerified(claims *idTokenClaims) bool {
+	return claims.Email != "" && (claims.EmailVerified == true || claims.EmailVerified == "true")
+}
+
+// emailDomain returns the lowercased domain of an email, empty when it has none
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// pkceChallenge derives the S256 code challenge sent to the IdP from the code verifier kept for the callback
+func pkceChallenge(codeVerifier string) string {
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(challenge[:])
+}
+
+// issuerAllowed requires an https URL on a public host unless insecure issuers such as a local mock IdP are allowed,
+// hostnames are checked again by the OIDC provider once they are resolved
+func issuerAllowed(issuer string) bool {
+	parsed, err := url.Parse(issuer)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	if config.LoadSSOConfig().AllowInsecureIssuers {
+		return parsed.Scheme == "https" || parsed.Scheme == "http"
+	}
+	if parsed.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if ip := net.ParseIP(host); ip != nil {
+		return helper.IsPublicIP(ip)
+	}
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+// randomURLString returns n random bytes encoded for use in URLs
+func randomURLString(n int) (string, error) {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
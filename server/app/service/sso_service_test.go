@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// mockIdP is a local OpenID Connect provider that issues one ID token per authorization code
+type mockIdP struct {
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	clientID     string
+	clientSecret string
+	code         string
+	codeVerifier string
+	nonce        string
+	email        string
+	jwksURI      string // Overrides the published JWKS location when set
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{
+		key:          key,
+		clientID:     "client",
+		clientSecret: "secret",
+		code:         "code",
+		codeVerifier: "verifier",
+		nonce:        "nonce",
+		email:        "jane@acme.com",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		jwksURI := idp.jwksURI
+		if jwksURI == "" {
+			jwksURI = idp.server.URL + "/jwks"
+		}
+		json.NewEncoder(w).Encode(provider.OIDCDiscovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               jwksURI,
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		encode := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{"keys": []provider.JSONWebKey{{
+			Kid: "key-1",
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   encode(key.N.Bytes()),
+			E:   encode(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("code") != idp.code || r.PostForm.Get("code_verifier") != idp.codeVerifier ||
+			r.PostForm.Get("client_id") != idp.clientID || r.PostForm.Get("client_secret") != idp.clientSecret {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(provider.OIDCTokenResponse{IDToken: idp.idToken(t), TokenType: "Bearer"})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *mockIdP) idToken(t *testing.T) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idTokenClaims{
+		Nonce:         idp.nonce,
+		Email:         idp.email,
+		EmailVerified: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.server.URL,
+			Subject:   "jane",
+			Audience:  jwt.ClaimStrings{idp.clientID},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// setSSOTestEnv provides the environment the configuration loaders read, without a .env file
+func setSSOTestEnv(t *testing.T) {
+	t.Setenv("MODE", "release")
+	t.Setenv("APP_KEY", "0123456789abcdef0123456789abcdef")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("REDIS_PREFIX", "test")
+	t.Setenv("POSTMARK_API_KEY", "key")
+	t.Setenv("POSTMARK_FROM", "noreply@example.com")
+	t.Setenv("IPFS_NODE_URL", "http://ipfs")
+	t.Setenv("IPFS_DOWNLOAD_URL", "http://ipfs")
+	t.Setenv("SSO_ALLOW_INSECURE_ISSUERS", "true") // The mock IdP serves http on loopback
+}
+
+func TestSSORedeemCode(t *testing.T) {
+	setSSOTestEnv(t)
+	idp := newMockIdP(t)
+
+	clientSecret, err := helper.Encrypt(idp.clientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sso := &model.OrganizationSSO{Issuer: idp.server.URL, ClientID: idp.clientID, ClientSecret: clientSecret, IsEnabled: true}
+	ss := &SSOService{oidcProvider: provider.NewOIDCProvider()}
+
+	discovery, err := ss.discover(context.Background(), sso.Issuer)
+	if err != nil {
+		t.Fatalf("expected the mock IdP to be discovered, got %v", err)
+	}
+
+	t.Run("valid code returns the ID token claims", func(t *testing.T) {
+		claims, err := ss.redeemCode(context.Background(), sso, discovery, idp.code, &ssoState{Nonce: idp.nonce, CodeVerifier: idp.codeVerifier})
+		if err != nil {
+			t.Fatalf("expected the code to be redeemed, got %v", err)
+		}
+		if claims.Email != idp.email || !emailVerified(claims) {
+			t.Fatalf("expected a verified %s, got %q", idp.email, claims.Email)
+		}
+	})
+
+	t.Run("wrong code verifier is rejected", func(t *testing.T) {
+		_, err := ss.redeemCode(context.Background(), sso, discovery, idp.code, &ssoState{Nonce: idp.nonce, CodeVerifier: "other"})
+		if err == nil || err.Error() != "authorization code exchange failed" {
+			t.Fatalf("expected authorization code exchange failed, got %v", err)
+		}
+	})
+
+	t.Run("mismatched nonce is rejected", func(t *testing.T) {
+		_, err := ss.redeemCode(context.Background(), sso, discovery, idp.code, &ssoState{Nonce: "other", CodeVerifier: idp.codeVerifier})
+		if err == nil || err.Error() != "invalid ID token" {
+			t.Fatalf("expected invalid ID token, got %v", err)
+		}
+	})
+}
+
+func TestSSORejectsInternalIssuers(t *testing.T) {
+	setSSOTestEnv(t)
+	t.Setenv("SSO_ALLOW_INSECURE_ISSUERS", "false")
+
+	tests := []struct {
+		issuer  string
+		allowed bool
+	}{
+		{"https://idp.example.com", true},
+		{"http://idp.example.com", false},
+		{"https://127.0.0.1", false},
+		{"https://[::1]", false},
+		{"https://localhost", false},
+		{"https://169.254.169.254", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"https://10.0.0.1", false},
+		{"https://192.168.1.1:8443", false},
+		{"https://0.0.0.0", false},
+	}
+	for _, test := range tests {
+		if allowed := issuerAllowed(test.issuer); allowed != test.allowed {
+			t.Errorf("issuerAllowed(%q) = %v, expected %v", test.issuer, allowed, test.allowed)
+		}
+	}
+
+	ss := &SSOService{oidcProvider: provider.NewOIDCProvider()}
+	for _, issuer := range []string{"https://127.0.0.1", "https://169.254.169.254"} {
+		if _, err := ss.discover(context.Background(), issuer); err == nil {
+			t.Errorf("expected issuer %s to be rejected", issuer)
+		}
+	}
+}
+
+func TestSSORefusesInternalConnections(t *testing.T) {
+	setSSOTestEnv(t)
+	t.Setenv("SSO_ALLOW_INSECURE_ISSUERS", "false")
+
+	// A public-looking issuer can still resolve or redirect to an internal address, the dialer refuses those
+	idp := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(idp.Close)
+
+	_, err := provider.NewOIDCProvider().Discover(context.Background(), idp.URL)
+	if err == nil || !strings.Contains(err.Error(), "is not allowed") {
+		t.Fatalf("expected the connection to 127.0.0.1 to be refused, got %v", err)
+	}
+}
+
+func TestSSOResolveUser(t *testing.T) {
+	setSSOTestEnv(t)
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	now := time.Now()
+	org := model.Organization{ID: primitive.NewObjectID(), Name: "Acme", Domain: "acme.com", DomainVerifiedAt: &now}
+	user := model.User{ID: primitive.NewObjectID(), Email: "jane@acme.com", IsVerified: true}
+	sso := &model.OrganizationSSO{OrganizationID: org.ID, AllowedDomains: []string{"acme.com", "gmail.com"}, IsEnabled: true}
+
+	newService := func(mt *mtest.T) *SSOService {
+		mongoProvider := provider.NewMongoProviderFromDatabase(mt.DB)
+		return &SSOService{
+			orgRepo:         repository.NewOrganizationRepository(mongoProvider),
+			orgUserRoleRepo: repository.NewOrganizationUserRoleRepository(mongoProvider),
+			userRepo:        repository.NewUserRepository(mongoProvider),
+		}
+	}
+
+	mt.Run("unverified domain cannot provision accounts", func(mt *mtest.T) {
+		unverified := org
+		unverified.DomainVerifiedAt = nil
+		mt.AddMockResponses(found(mt, "db.organizations", unverified))
+
+		_, err := newService(mt).resolveUser(context.Background(), sso, &idTokenClaims{Email: "jane@acme.com"})
+		if err == nil || err.Error() != "email domain is not verified by the organization" {
+			mt.Fatalf("expected email domain is not verified by the organization, got %v", err)
+		}
+	})
+
+	mt.Run("allowed but unowned domain cannot provision accounts", func(mt *mtest.T) {
+		mt.AddMockResponses(found(mt, "db.organizations", org))
+
+		_, err := newService(mt).resolveUser(context.Background(), sso, &idTokenClaims{Email: "someone@gmail.com"})
+		if err == nil || err.Error() != "email domain is not verified by the organization" {
+			mt.Fatalf("expected email domain is not verified by the organization, got %v", err)
+		}
+	})
+
+	mt.Run("existing account outside the organization is rejected", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			found(mt, "db.organizations", org),
+			found(mt, "db.users", user),
+			notFound("db.organization_user_role"),
+		)
+
+		_, err := newService(mt).resolveUser(context.Background(), sso, &idTokenClaims{Email: user.Email})
+		if err == nil || err.Error() != "account is not a member of the organization" {
+			mt.Fatalf("expected account is not a member of the organization, got %v", err)
+		}
+	})
+
+	mt.Run("existing member signs in", func(mt *mtest.T) {
+		member := model.OrganizationUserRole{ID: primitive.NewObjectID(), OrganizationID: org.ID, UserID: user.ID, RoleID: primitive.NewObjectID()}
+		mt.AddMockResponses(
+			found(mt, "db.organizations", org),
+			found(mt, "db.users", user),
+			found(mt, "db.organization_user_role", member),
+		)
+
+		resolved, err := newService(mt).resolveUser(context.Background(), sso, &idTokenClaims{Email: user.Email})
+		if err != nil {
+			mt.Fatalf("expected the member to sign in, got %v", err)
+		}
+		if resolved.ID != user.ID {
+			mt.Fatalf("expected user %s, got %s", user.ID.Hex(), resolved.ID.Hex())
+		}
+	})
+}
@@ -0,0 +1,567 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SubscriptionService manages the plan catalog, assigns plans to users and organizations
+// and resolves the plan whose limits apply to a user
+type SubscriptionService struct {
+	planRepo           *repository.SubscriptionPlanRepository
+	statusRepo         *repository.SubscriptionStatusRepository
+	userRepo           *repository.UserRepository
+	orgRepo            *repository.OrganizationRepository
+	orgUserRoleRepo    *repository.OrganizationUserRoleRepository
+	teamDriveRepo      *repository.TeamDriveRepository
+	serviceAccountRepo *repository.ServiceAccountRepository
+	quotaService       *QuotaService
+	redisProvider      *provider.RedisProvider
+}
+
+// Subscription is the plan and status assigned to a user or an organization
+type Subscription struct {
+	Plan   *model.SubscriptionPlan `json:"plan"`
+	Status string                  `json:"status"`
+}
+
+// NewSubscriptionService initializes a new SubscriptionService
+func NewSubscriptionService(
+	planRepo *repository.SubscriptionPlanRepository,
+	statusRepo *repository.SubscriptionStatusRepository,
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrganizationRepository,
+	orgUserRoleRepo *repository.OrganizationUserRoleRepository,
+	teamDriveRepo *repository.TeamDriveRepository,
+	serviceAccountRepo *repository.ServiceAccountRepository,
+	quotaService *QuotaService,
+	redisProvider *provider.RedisProvider,
+) *SubscriptionService {
+	return &SubscriptionService{
+		planRepo:           planRepo,
+		statusRepo:         statusRepo,
+		userRepo:           userRepo,
+		orgRepo:            orgRepo,
+		orgUserRoleRepo:    orgUserRoleRepo,
+		teamDriveRepo:      teamDriveRepo,
+		serviceAccountRepo: serviceAccountRepo,
+		quotaService:       quotaService,
+		redisProvider:      redisProvider,
+	}
+}
+
+// ListPlans returns the plan catalog
+func (ss *SubscriptionService) ListPlans(ctx context.Context) ([]model.SubscriptionPlan, error) {
+	plans, err := ss.planRepo.List(ctx)
+	if err != nil {
+		return nil, errors.New("failed to list plans")
+	}
+	if plans == nil {
+		plans = []model.SubscriptionPlan{}
+	}
+	return plans, nil
+}
+
+// ReadPlan returns a plan of the catalog
+func (ss *SubscriptionService) ReadPlan(ctx context.Context, planID string) (*model.SubscriptionPlan, error) {
+	if _, err := primitive.ObjectIDFromHex(planID); err != nil {
+		return nil, errors.New("invalid plan ID")
+	}
+	plan, err := ss.planRepo.Read(ctx, planID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve plan")
+	}
+	if plan == nil {
+		return nil, errors.New("plan not found")
+	}
+	return plan, nil
+}
+
+// CreatePlan adds a plan to the catalog
+func (ss *SubscriptionService) CreatePlan(ctx context.Context, request *dto.CreateSubscriptionPlanDTO) (*model.SubscriptionPlan, error) {
+	if err := validatePlanRateLimits(request.RateLimits); err != nil {
+		return nil, err
+	}
+
+	plan := &model.SubscriptionPlan{
+		Name:                 request.Name,
+		Description:          request.Description,
+		BytesLimit:           request.BytesLimit,
+		MaxFileSize:          request.MaxFileSize,
+		MaxEncryptedFileSize: request.MaxEncryptedFileSize,
+		MaxServiceAccounts:   request.MaxServiceAccounts,
+		RateLimits:           request.RateLimits,
+	}
+	if err := ss.planRepo.Create(ctx, plan); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("plan already exists")
+		}
+		return nil, errors.New("failed to create plan")
+	}
+
+	return plan, nil
+}
+
+// UpdatePlan changes a plan of the catalog. A new storage size is applied to the users and organizations
+// holding the plan; users on the default plan without a subscription keep the storage they registered with.
+func (ss *SubscriptionService) UpdatePlan(ctx context.Context, planID string, request *dto.UpdateSubscriptionPlanDTO) (*model.SubscriptionPlan, error) {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	plan, err := ss.ReadPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{}
+	if request.Name != nil && *request.Name != plan.Name {
+		if plan.Name == subscriptionConfig.DefaultPlan {
+			return nil, errors.New("default plan cannot be renamed")
+		}
+		update["name"] = *request.Name
+	}
+	if request.Description != nil {
+		update["description"] = *request.Description
+	}
+	if request.BytesLimit != nil {
+		update["bytes_limit"] = *request.BytesLimit
+	}
+	if request.MaxFileSize != nil {
+		update["max_file_size"] = *request.MaxFileSize
+	}
+	if request.MaxEncryptedFileSize != nil {
+		update["max_encrypted_file_size"] = *request.MaxEncryptedFileSize
+	}
+	if request.MaxServiceAccounts != nil {
+		update["max_service_accounts"] = *request.MaxServiceAccounts
+	}
+	if request.RateLimits != nil {
+		if err := validatePlanRateLimits(*request.RateLimits); err != nil {
+			return nil, err
+		}
+		update["rate_limits"] = *request.RateLimits
+	}
+	if len(update) == 0 {
+		return plan, nil
+	}
+
+	if err := ss.planRepo.Update(ctx, planID, update); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("plan already exists")
+		}
+		return nil, errors.New("failed to update plan")
+	}
+
+	if request.BytesLimit != nil && *request.BytesLimit != plan.BytesLimit {
+		if err := ss.resizeHolders(ctx, planID, *request.BytesLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	return ss.ReadPlan(ctx, planID)
+}
+
+// DeletePlan removes a plan nobody is subscribed to
+func (ss *SubscriptionService) DeletePlan(ctx context.Context, planID string) error {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	plan, err := ss.ReadPlan(ctx, planID)
+	if err != nil {
+		return err
+	}
+	if plan.Name == subscriptionConfig.DefaultPlan {
+		return errors.New("default plan cannot be deleted")
+	}
+
+	users, err := ss.userRepo.ListBySubscriptionPlanID(ctx, planID)
+	if err != nil {
+		return errors.New("failed to retrieve subscribed users")
+	}
+	orgs, err := ss.orgRepo.ListBySubscriptionPlanID(ctx, planID)
+	if err != nil {
+		return errors.New("failed to retrieve subscribed organizations")
+	}
+	if len(users) > 0 || len(orgs) > 0 {
+		return errors.New("plan is in use")
+	}
+
+	if err := ss.planRepo.Delete(ctx, planID); err != nil {
+		return errors.New("failed to delete plan")
+	}
+	return nil
+}
+
+// AssignUserPlan subscribes a user to a plan and gives them the storage of the plan that applies.
+// A member of an organization with its own subscription keeps getting the organization's limits.
+func (ss *SubscriptionService) AssignUserPlan(ctx context.Context, userID, planID, status string) (*Subscription, error) {
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	user, err := ss.userRepo.Read(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve user")
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	update, err := ss.subscriptionUpdate(ctx, user.SubscriptionStatusID, planID, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.userRepo.Update(ctx, userID, update); err != nil {
+		return nil, errors.New("failed to update subscription")
+	}
+
+	plan, err := ss.effectivePlan(ctx, update["subscription_plan_id"].(primitive.ObjectID), status)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.quotaService.ResizeUserLimit(ctx, userID, plan.BytesLimit); err != nil {
+		return nil, err
+	}
+	ss.forgetPlans(ctx, userID)
+
+	return &Subscription{Plan: plan, Status: status}, nil
+}
+
+// AssignOrganizationPlan subscribes an organization to a plan, which then applies to all of its members.
+// The plan's storage becomes the organization's shared pool.
+func (ss *SubscriptionService) AssignOrganizationPlan(ctx context.Context, orgID, planID, status string) (*Subscription, error) {
+	if _, err := primitive.ObjectIDFromHex(orgID); err != nil {
+		return nil, errors.New("organization not found")
+	}
+	org, err := ss.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	update, err := ss.subscriptionUpdate(ctx, org.SubscriptionStatusID, planID, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.orgRepo.Update(ctx, orgID, update); err != nil {
+		return nil, errors.New("failed to update subscription")
+	}
+
+	plan, err := ss.effectivePlan(ctx, update["subscription_plan_id"].(primitive.ObjectID), status)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ss.quotaService.ResizeOrganizationPool(ctx, orgID, plan.BytesLimit); err != nil {
+		return nil, err
+	}
+
+	members, err := ss.orgUserRoleRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization members")
+	}
+	for _, member := range members {
+		ss.forgetPlans(ctx, member.UserID.Hex())
+	}
+
+	return &Subscription{Plan: plan, Status: status}, nil
+}
+
+// ResolvePlan returns the plan whose limits apply to the user: their organization's when it has a
+// subscription, otherwise their own. Subscriptions without an entitled status fall back to the default plan.
+func (ss *SubscriptionService) ResolvePlan(ctx context.Context, userID string) (*model.SubscriptionPlan, error) {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	// Serve from the cache when possible, the lookup runs on every upload and API request
+	key := fmt.Sprintf("subscription_plan:%s", userID)
+	if cached, err := ss.redisProvider.Get(ctx, key); err == nil && cached != "" {
+		var plan model.SubscriptionPlan
+		if err := json.Unmarshal([]byte(cached), &plan); err == nil {
+			return &plan, nil
+		}
+	}
+
+	planID, statusID, err := ss.subscriptionOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := ss.statusName(ctx, statusID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := ss.effectivePlan(ctx, planID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, err := json.Marshal(plan); err == nil {
+		if err := ss.redisProvider.Set(ctx, key, string(cached), subscriptionConfig.CacheExpiry); err != nil {
+			logger.Log.Error("failed to cache subscription plan", logger.Error(err))
+		}
+	}
+	return plan, nil
+}
+
+// CheckUpload refuses files larger than the user's plan allows
+func (ss *SubscriptionService) CheckUpload(ctx context.Context, userID string, fileSize int64, isEncrypted bool) error {
+	fileConfig := config.LoadFileConfig()
+
+	plan, err := ss.ResolvePlan(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if plan.MaxFileSize > 0 && fileSize > plan.MaxFileSize {
+		return errors.New("file size exceeds the plan limit")
+	}
+	if isEncrypted {
+		maxEncryptedFileSize := plan.MaxEncryptedFileSize
+		if maxEncryptedFileSize == 0 {
+			maxEncryptedFileSize = fileConfig.DefaultEncryptedSize
+		}
+		if fileSize > maxEncryptedFileSize {
+			return errors.New("file size exceeds the limit for encrypted uploads")
+		}
+	}
+	return nil
+}
+
+// CheckServiceAccountQuota refuses a new service account once the plan's count is reached,
+// organization accounts count against the organization and personal ones against the user
+func (ss *SubscriptionService) CheckServiceAccountQuota(ctx context.Context, userID string, orgID *string) error {
+	var plan *model.SubscriptionPlan
+	var count int64
+	var err error
+	if orgID != nil {
+		plan, err = ss.resolveOrganizationPlan(ctx, *orgID)
+		if err != nil {
+			return err
+		}
+		count, err = ss.serviceAccountRepo.CountByOrganizationID(ctx, *orgID)
+	} else {
+		plan, err = ss.ResolvePlan(ctx, userID)
+		if err != nil {
+			return err
+		}
+		count, err = ss.serviceAccountRepo.CountByUserID(ctx, userID)
+	}
+	if err != nil {
+		return errors.New("failed to count service accounts")
+	}
+
+	if plan.MaxServiceAccounts > 0 && count >= plan.MaxServiceAccounts {
+		return errors.New("service account limit reached")
+	}
+	return nil
+}
+
+// resolveOrganizationPlan returns the organization's plan, the default one without an entitled subscription
+func (ss *SubscriptionService) resolveOrganizationPlan(ctx context.Context, orgID string) (*model.SubscriptionPlan, error) {
+	org, err := ss.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization")
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+	status, err := ss.statusName(ctx, org.SubscriptionStatusID)
+	if err != nil {
+		return nil, err
+	}
+	return ss.effectivePlan(ctx, org.SubscriptionPlanID, status)
+}
+
+// subscriptionOf returns the plan and status that govern the user, their organization's when it has a plan
+func (ss *SubscriptionService) subscriptionOf(ctx context.Context, userID string) (primitive.ObjectID, primitive.ObjectID, error) {
+	memberships, err := ss.orgUserRoleRepo.ReadByUserID(ctx, userID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("failed to retrieve organization membership")
+	}
+	for _, membership := range memberships {
+		org, err := ss.orgRepo.Read(ctx, membership.OrganizationID.Hex())
+		if err != nil {
+			return primitive.NilObjectID, primitive.NilObjectID, errors.New("failed to retrieve organization")
+		}
+		if org != nil && !org.SubscriptionPlanID.IsZero() {
+			return org.SubscriptionPlanID, org.SubscriptionStatusID, nil
+		}
+	}
+
+	user, err := ss.userRepo.Read(ctx, userID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("failed to retrieve user")
+	}
+	if user != nil {
+		return user.SubscriptionPlanID, user.SubscriptionStatusID, nil
+	}
+
+	// Organization service accounts act as a team drive, which is on its organization's plan
+	drive, err := ss.teamDriveRepo.Read(ctx, userID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("failed to retrieve team drive")
+	}
+	if drive == nil {
+		return primitive.NilObjectID, primitive.NilObjectID, nil
+	}
+	org, err := ss.orgRepo.Read(ctx, drive.OrganizationID.Hex())
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("failed to retrieve organization")
+	}
+	if org == nil {
+		return primitive.NilObjectID, primitive.NilObjectID, nil
+	}
+	return org.SubscriptionPlanID, org.SubscriptionStatusID, nil
+}
+
+// subscriptionUpdate checks a plan change against the status lifecycle and returns the fields to store
+func (ss *SubscriptionService) subscriptionUpdate(ctx context.Context, currentStatusID primitive.ObjectID, planID, status string) (bson.M, error) {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	plan, err := ss.ReadPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ss.statusName(ctx, currentStatusID)
+	if err != nil {
+		return nil, err
+	}
+	if status != current && !slices.Contains(subscriptionConfig.Transitions[current], status) {
+		return nil, fmt.Errorf("invalid subscription status transition from %q to %q", current, status)
+	}
+
+	statusDoc, err := ss.statusRepo.ReadByName(ctx, status)
+	if err != nil {
+		return nil, errors.New("failed to retrieve subscription status")
+	}
+	if statusDoc == nil {
+		return nil, errors.New("subscription status not found")
+	}
+
+	return bson.M{
+		"subscription_plan_id":   plan.ID,
+		"subscription_status_id": statusDoc.ID,
+	}, nil
+}
+
+// effectivePlan returns the plan when the status entitles to it, otherwise the default plan
+func (ss *SubscriptionService) effectivePlan(ctx context.Context, planID primitive.ObjectID, status string) (*model.SubscriptionPlan, error) {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	if planID.IsZero() || !slices.Contains(subscriptionConfig.EntitledStatuses, status) {
+		return ss.defaultPlan(ctx)
+	}
+	plan, err := ss.planRepo.Read(ctx, planID.Hex())
+	if err != nil {
+		return nil, errors.New("failed to retrieve plan")
+	}
+	if plan == nil {
+		return ss.defaultPlan(ctx)
+	}
+	return plan, nil
+}
+
+// defaultPlan returns the plan of users without a subscription
+func (ss *SubscriptionService) defaultPlan(ctx context.Context) (*model.SubscriptionPlan, error) {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	plan, err := ss.planRepo.ReadByName(ctx, subscriptionConfig.DefaultPlan)
+	if err != nil {
+		return nil, errors.New("failed to retrieve plan")
+	}
+	if plan == nil {
+		return nil, errors.New("default plan not found")
+	}
+	return plan, nil
+}
+
+// statusName returns the name of a subscription status, "" when none is set
+func (ss *SubscriptionService) statusName(ctx context.Context, statusID primitive.ObjectID) (string, error) {
+	if statusID.IsZero() {
+		return "", nil
+	}
+	status, err := ss.statusRepo.Read(ctx, statusID.Hex())
+	if err != nil {
+		return "", errors.New("failed to retrieve subscription status")
+	}
+	if status == nil {
+		return "", nil
+	}
+	return status.Name, nil
+}
+
+// resizeHolders gives the new storage of a plan to its entitled subscribers
+func (ss *SubscriptionService) resizeHolders(ctx context.Context, planID string, bytesLimit int64) error {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	users, err := ss.userRepo.ListBySubscriptionPlanID(ctx, planID)
+	if err != nil {
+		return errors.New("failed to retrieve subscribed users")
+	}
+	for _, user := range users {
+		status, err := ss.statusName(ctx, user.SubscriptionStatusID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(subscriptionConfig.EntitledStatuses, status) {
+			continue
+		}
+		if err := ss.quotaService.ResizeUserLimit(ctx, user.ID.Hex(), bytesLimit); err != nil {
+			return err
+		}
+	}
+
+	orgs, err := ss.orgRepo.ListBySubscriptionPlanID(ctx, planID)
+	if err != nil {
+		return errors.New("failed to retrieve subscribed organizations")
+	}
+	for _, org := range orgs {
+		status, err := ss.statusName(ctx, org.SubscriptionStatusID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(subscriptionConfig.EntitledStatuses, status) {
+			continue
+		}
+		if _, err := ss.quotaService.ResizeOrganizationPool(ctx, org.ID.Hex(), bytesLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgetPlans drops the cached plan of the users, edits to a plan reach cached holders on expiry
+func (ss *SubscriptionService) forgetPlans(ctx context.Context, userIDs ...string) {
+	for _, userID := range userIDs {
+		if err := ss.redisProvider.Del(ctx, fmt.Sprintf("subscription_plan:%s", userID)); err != nil {
+			logger.Log.Error("failed to clear cached subscription plan", logger.Error(err))
+		}
+	}
+}
+
+// validatePlanRateLimits checks that rate limit overrides name existing policies with usable rules
+func validatePlanRateLimits(rateLimits map[string]model.PlanRateLimit) error {
+	rateLimitConfig := config.LoadRateLimitConfig()
+
+	for policyName, rule := range rateLimits {
+		if _, ok := rateLimitConfig.Policies[policyName]; !ok {
+			return fmt.Errorf("invalid rate limit: unknown policy %q", policyName)
+		}
+		if rule.Limit < 1 || rule.WindowSeconds < 1 {
+			return fmt.Errorf("invalid rate limit: %q needs a positive limit and window", policyName)
+		}
+	}
+	return nil
+}
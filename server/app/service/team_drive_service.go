@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TeamDriveService manages organization-owned drives and who can use them
+type TeamDriveService struct {
+	teamDriveRepo   *repository.TeamDriveRepository
+	orgUserRoleRepo *repository.OrganizationUserRoleRepository
+	roleRepo        *repository.RoleRepository
+	directoryRepo   *repository.DirectoryRepository
+	fileRepo        *repository.FileRepository
+	limitRepo       *repository.LimitRepository
+}
+
+// TeamDriveUsage pairs a drive with the bytes stored in it
+type TeamDriveUsage struct {
+	Drive      model.TeamDrive
+	BytesUsage int64
+}
+
+// NewTeamDriveService initializes a new TeamDriveService
+func NewTeamDriveService(
+	teamDriveRepo *repository.TeamDriveRepository,
+	orgUserRoleRepo *repository.OrganizationUserRoleRepository,
+	roleRepo *repository.RoleRepository,
+	directoryRepo *repository.DirectoryRepository,
+	fileRepo *repository.FileRepository,
+	limitRepo *repository.LimitRepository,
+) *TeamDriveService {
+	return &TeamDriveService{
+		teamDriveRepo:   teamDriveRepo,
+		orgUserRoleRepo: orgUserRoleRepo,
+		roleRepo:        roleRepo,
+		directoryRepo:   directoryRepo,
+		fileRepo:        fileRepo,
+		limitRepo:       limitRepo,
+	}
+}
+
+// CreateDrive creates a drive with its root directory. Its usage is counted against the
+// organization's shared storage, which it must have.
+func (tds *TeamDriveService) CreateDrive(ctx context.Context, orgID, actorID, name string) (*model.TeamDrive, error) {
+	pool, err := tds.limitRepo.ReadByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization limit")
+	}
+	if pool == nil {
+		return nil, errors.New("organization has no shared storage")
+	}
+
+	actorObjectID, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	drive := &model.TeamDrive{
+		OrganizationID: *pool.OrganizationID,
+		Name:           name,
+		CreatedBy:      actorObjectID,
+	}
+	if err := tds.teamDriveRepo.Create(ctx, drive); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("team drive name already in use")
+		}
+		return nil, errors.New("failed to create team drive")
+	}
+
+	root := &model.Directory{
+		UserID: drive.ID,
+		Name:   "root",
+	}
+	if err := tds.directoryRepo.Create(ctx, root); err != nil {
+		return nil, errors.New("failed to create root directory")
+	}
+
+	// A pooled limit without a member cap, the drive is only bound by the organization's pool
+	limit := &model.Limit{
+		UserID:         drive.ID,
+		OrganizationID: pool.OrganizationID,
+	}
+	if err := tds.limitRepo.Create(ctx, limit); err != nil {
+		return nil, errors.New("failed to create team drive limit")
+	}
+
+	return drive, nil
+}
+
+// ListDrives returns the organization's drives with what each one stores
+func (tds *TeamDriveService) ListDrives(ctx context.Context, orgID string) ([]TeamDriveUsage, error) {
+	drives, err := tds.teamDriveRepo.ListByOrganizationID(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to list team drives")
+	}
+
+	usage := make([]TeamDriveUsage, 0, len(drives))
+	for _, drive := range drives {
+		limit, err := tds.limitRepo.ReadByUserID(ctx, drive.ID.Hex())
+		if err != nil {
+			return nil, errors.New("failed to retrieve team drive limit")
+		}
+
+		driveUsage := TeamDriveUsage{Drive: drive}
+		if limit != nil {
+			driveUsage.BytesUsage = limit.BytesUsage
+		}
+		usage = append(usage, driveUsage)
+	}
+
+	return usage, nil
+}
+
+// RenameDrive changes a drive's name
+func (tds *TeamDriveService) RenameDrive(ctx context.Context, orgID, driveID, name string) (*model.TeamDrive, error) {
+	drive, err := tds.readDrive(ctx, orgID, driveID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tds.teamDriveRepo.Update(ctx, driveID, bson.M{"name": name}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("team drive name already in use")
+		}
+		return nil, errors.New("failed to update team drive")
+	}
+
+	drive.Name = name
+	return drive, nil
+}
+
+// DeleteDrive removes an empty drive with its root directory and limit
+func (tds *TeamDriveService) DeleteDrive(ctx context.Context, orgID, driveID string) error {
+	if _, err := tds.readDrive(ctx, orgID, driveID); err != nil {
+		return err
+	}
+
+	// Files are never dropped with the drive, they have to be deleted first so their size is given back
+	files, err := tds.fileRepo.CountByUserID(ctx, driveID)
+	if err != nil {
+		return errors.New("failed to count files")
+	}
+	directories, err := tds.directoryRepo.CountByUserID(ctx, driveID)
+	if err != nil {
+		return errors.New("failed to count directories")
+	}
+	if files > 0 || directories > 1 {
+		return errors.New("team drive is not empty")
+	}
+
+	if err := tds.directoryRepo.DeleteByUserID(ctx, driveID); err != nil {
+		return errors.New("failed to delete root directory")
+	}
+	if err := tds.limitRepo.DeleteByUserID(ctx, driveID); err != nil {
+		return errors.New("failed to delete team drive limit")
+	}
+	if err := tds.teamDriveRepo.Delete(ctx, driveID); err != nil {
+		return errors.New("failed to delete team drive")
+	}
+
+	return nil
+}
+
+// CheckDriveAccess returns the drive when the user's organization role allows the access
+// ("read", "write" or "manage"). Non-members get "team drive not found".
+func (tds *TeamDriveService) CheckDriveAccess(ctx context.Context, driveID, userID, access string) (*model.TeamDrive, error) {
+	orgConfig := config.LoadOrganizationConfig()
+
+	if _, err := primitive.ObjectIDFromHex(driveID); err != nil {
+		return nil, errors.New("team drive not found")
+	}
+	drive, err := tds.teamDriveRepo.Read(ctx, driveID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve team drive")
+	}
+	if drive == nil {
+		return nil, errors.New("team drive not found")
+	}
+
+	member, err := tds.orgUserRoleRepo.ReadByUserIDOrganizationID(ctx, userID, drive.OrganizationID.Hex())
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization membership")
+	}
+	if member == nil {
+		return nil, errors.New("team drive not found")
+	}
+
+	role, err := tds.roleRepo.Read(ctx, member.RoleID.Hex())
+	if err != nil {
+		logger.Log.Error("error fetching role", logger.Error(err))
+		return nil, errors.New("failed to retrieve organization role")
+	}
+	if role == nil || !slices.Contains(orgConfig.DriveAccess[role.Name], access) {
+		return nil, errors.New("organization role does not allow this in the team drive")
+	}
+
+	return drive, nil
+}
+
+// readDrive reads a drive of the organization
+func (tds *TeamDriveService) readDrive(ctx context.Context, orgID, driveID string) (*model.TeamDrive, error) {
+	if _, err := primitive.ObjectIDFromHex(driveID); err != nil {
+		return nil, errors.New("team drive not found")
+	}
+	drive, err := tds.teamDriveRepo.ReadByIDOrganizationID(ctx, driveID, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve team drive")
+	}
+	if drive == nil {
+		return nil, errors.New("team drive not found")
+	}
+	return drive, nil
+}
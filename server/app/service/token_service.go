@@ -4,87 +4,86 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
+	"bongaquino/server/app/dto"
 	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
 	"bongaquino/server/app/provider"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type TokenService struct {
-	userRepo      *repository.UserRepository
-	jwtProvider   *provider.JWTProvider
-	mfaService    *MFAService
-	redisProvider *provider.RedisProvider
+	userRepo              *repository.UserRepository
+	serviceAccountService *ServiceAccountService
+	jwtProvider           *provider.JWTProvider
+	mfaService            *MFAService
+	webauthnService       *WebAuthnService
+	lockoutService        *LockoutService
+	loginEvents           *LoginEventService
+	policyService         *PolicyService
+	redisProvider         *provider.RedisProvider
 }
 
-func NewTokenService(userRepo *repository.UserRepository, jwtProvider *provider.JWTProvider, mfaService *MFAService, redisProvider *provider.RedisProvider) *TokenService {
+func NewTokenService(userRepo *repository.UserRepository, serviceAccountService *ServiceAccountService, jwtProvider *provider.JWTProvider, mfaService *MFAService, webauthnService *WebAuthnService, lockoutService *LockoutService, loginEvents *LoginEventService, policyService *PolicyService, redisProvider *provider.RedisProvider) *TokenService {
 	return &TokenService{
-		userRepo:      userRepo,
-		jwtProvider:   jwtProvider,
-		mfaService:    mfaService,
-		redisProvider: redisProvider,
+		userRepo:              userRepo,
+		serviceAccountService: serviceAccountService,
+		jwtProvider:           jwtProvider,
+		mfaService:            mfaService,
+		webauthnService:       webauthnService,
+		lockoutService:        lockoutService,
+		loginEvents:           loginEvents,
+		policyService:         policyService,
+		redisProvider:         redisProvider,
 	}
 }
 
 // AuthenticateUser validates user credentials and generates tokens
-func (ts *TokenService) AuthenticateUser(ctx context.Context, email, password string) (accessToken string, refreshToken string, err error) {
-	failedLoginAttemptsKey := fmt.Sprintf("failed_login_attempts:%s", email)
+func (ts *TokenService) AuthenticateUser(ctx context.Context, email, password, ip string) (accessToken string, refreshToken string, err error) {
+	var user *model.User
+	defer func() { ts.recordLogin(ctx, "password", user, email, err) }()
 
-	user, err := ts.userRepo.ReadByEmail(ctx, email)
-	if err != nil || user == nil {
+	// Throttle addresses that keep failing before looking at the account, so stuffing does not lock out victims
+	if err := ts.lockoutService.CheckIP(ctx, ip); err != nil {
+		return "", "", err
+	}
+
+	user, err = ts.userRepo.ReadByEmail(ctx, email)
+	if err != nil {
+		return "", "", errors.New("invalid credentials")
+	}
+	if user == nil {
+		if err := ts.lockoutService.RecordFailure(ctx, nil, ip); err != nil {
+			return "", "", err
+		}
 		return "", "", errors.New("invalid credentials")
 	}
 
 	// Check if user account is locked due to too many failed login attempts
-	if user.IsLocked {
+	if user.IsLockActive() {
 		return "", "", errors.New("account locked due to multiple failed login attempts")
 	}
 
+	// Addresses that kept failing stay locked out of this account for a while
+	if err := ts.lockoutService.CheckAddress(ctx, user, ip); err != nil {
+		return "", "", err
+	}
+
 	if !helper.CheckHash(password, user.Password) {
-		// Get the current number of failed attempts
-		attemptsStr, err := ts.redisProvider.Get(ctx, failedLoginAttemptsKey)
-		if attemptsStr == "0" || err != nil {
-			// If no attempts exist or an error occurred, initialize it to 1 with a 24-hour expiration
-			err = ts.redisProvider.Set(ctx, failedLoginAttemptsKey, "1", 24*time.Hour)
-			if err != nil {
-				return "", "", fmt.Errorf("failed to increment failed attempts: %w", err)
-			}
-		} else {
-			// Convert the string to an integer
-			attempts, err := strconv.Atoi(attemptsStr)
-			if err != nil {
-				return "", "", fmt.Errorf("failed to convert failed attempts to integer: %w", err)
-			}
-
-			if attempts >= 5 {
-				update := map[string]any{
-					"is_locked": true,
-				}
-
-				if err := ts.userRepo.UpdateByEmail(ctx, email, update); err != nil {
-					return "", "", fmt.Errorf("failed to update user lock status: %w", err)
-				}
-			} else {
-				// Increment the failed attempt count
-				attempts++
-
-				// Update the failed attempt count in Redis with a 24-hour expiration
-				err = ts.redisProvider.Set(ctx, failedLoginAttemptsKey, strconv.Itoa(attempts), 24*time.Hour)
-				if err != nil {
-					return "", "", fmt.Errorf("failed to update failed attempts: %w", err)
-				}
-			}
+		if err := ts.lockoutService.RecordFailure(ctx, user, ip); err != nil {
+			return "", "", err
 		}
-
 		return "", "", errors.New("invalid credentials")
 	}
 
 	// Reset the failed attempt counter on successful login
-	err = ts.redisProvider.Del(ctx, failedLoginAttemptsKey)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to reset failed attempts: %w", err)
+	if err := ts.lockoutService.ClearFailures(ctx, user, ip); err != nil {
+		return "", "", err
 	}
 
 	accessToken, refreshToken, err = ts.jwtProvider.GenerateTokens(user.ID.Hex(), &user.Email, nil)
@@ -97,18 +96,22 @@ func (ts *TokenService) AuthenticateUser(ctx context.Context, email, password st
 
 // RefreshTokens validates the refresh token and generates new tokens
 func (ts *TokenService) RefreshTokens(ctx context.Context, refreshToken string) (accessToken, newRefreshToken string, err error) {
+	var user *model.User
+	defer func() { ts.recordLogin(ctx, "refresh", user, "", err) }()
+
 	claims, err := ts.jwtProvider.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return "", "", errors.New("invalid or expired refresh token")
 	}
 
-	user, err := ts.userRepo.ReadByEmail(ctx, *claims.Email)
+	// Sessions are tied to the account ID, not to the email the token happened to carry
+	user, err = ts.userRepo.Read(ctx, claims.Sub)
 	if err != nil || user == nil {
 		return "", "", errors.New("user no longer exists")
 	}
 
 	// Check if user account is locked due to too many failed login attempts
-	if user.IsLocked {
+	if user.IsLockActive() {
 		return "", "", errors.New("account locked due to multiple failed login attempts")
 	}
 
@@ -129,7 +132,7 @@ func (ts *TokenService) RevokeToken(ctx context.Context, refreshToken string) er
 	}
 
 	// Check if the user exists
-	user, err := ts.userRepo.ReadByEmail(ctx, *claims.Email)
+	user, err := ts.userRepo.Read(ctx, claims.Sub)
 	if err != nil || user == nil {
 		return errors.New("user no longer exists")
 	}
@@ -143,29 +146,240 @@ func (ts *TokenService) RevokeToken(ctx context.Context, refreshToken string) er
 	return nil
 }
 
-// AuthenticateLoginCode validates the login code and generates tokens
-func (ts *TokenService) AuthenticateLoginCode(ctx context.Context, loginCode, otp string) (accessToken string, refreshToken string, err error) {
-	userID, err := ts.mfaService.VerifyLoginCode(ctx, loginCode, otp)
+// AuthenticateLoginCode validates the login code and generates tokens, the user is returned so callers can remember the device
+func (ts *TokenService) AuthenticateLoginCode(ctx context.Context, loginCode string, factor LoginFactor) (user *model.User, accessToken string, refreshToken string, err error) {
+	method := "otp"
+	switch {
+	case factor.Passkey != nil:
+		method = "passkey"
+	case factor.RecoveryCode != "":
+		method = "recovery_code"
+	}
+	defer func() { ts.recordLogin(ctx, method, user, "", err) }()
+
+	userID, err := ts.mfaService.VerifyLoginCode(ctx, loginCode, factor)
 	if err != nil {
-		return "", "", errors.New("invalid login code or OTP")
+		return nil, "", "", errors.New("invalid login code or OTP")
 	}
 
 	// Check if user exists
-	user, err := ts.userRepo.Read(ctx, userID)
+	user, err = ts.userRepo.Read(ctx, userID)
+	if err != nil || user == nil {
+		return nil, "", "", errors.New("user no longer exists")
+	}
+
+	// Check if user account is locked due to too many failed login attempts
+	if user.IsLockActive() {
+		return user, "", "", errors.New("account locked due to multiple failed login attempts")
+	}
+
+	// Generate tokens
+	accessToken, refreshToken, err = ts.jwtProvider.GenerateTokens(userID, &user.Email, nil)
+	if err != nil {
+		return user, "", "", errors.New("failed to generate tokens")
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// BeginPasskeyAuthentication starts a passwordless sign-in and returns the session ID with the passkey request options
+func (ts *TokenService) BeginPasskeyAuthentication(ctx context.Context) (string, map[string]any, error) {
+	sessionID, err := helper.GenerateCode(16)
+	if err != nil {
+		return "", nil, errors.New("failed to generate session")
+	}
+
+	options, err := ts.webauthnService.BeginAssertion(ctx, "passwordless:"+sessionID, "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sessionID, options, nil
+}
+
+// AuthenticatePasskey completes a passwordless sign-in, a user-verified passkey stands in for the password and second factor
+func (ts *TokenService) AuthenticatePasskey(ctx context.Context, sessionID string, assertion *dto.WebAuthnAssertionDTO) (accessToken string, refreshToken string, err error) {
+	var user *model.User
+	defer func() { ts.recordLogin(ctx, "passkey", user, "", err) }()
+
+	userID, err := ts.webauthnService.FinishAssertion(ctx, "passwordless:"+sessionID, "", assertion)
+	if err != nil {
+		return "", "", errors.New("invalid passkey")
+	}
+
+	// Check if user exists
+	user, err = ts.userRepo.Read(ctx, userID)
 	if err != nil || user == nil {
 		return "", "", errors.New("user no longer exists")
 	}
 
 	// Check if user account is locked due to too many failed login attempts
-	if user.IsLocked {
+	if user.IsLockActive() {
 		return "", "", errors.New("account locked due to multiple failed login attempts")
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err = ts.jwtProvider.GenerateTokens(userID, nil, nil)
+	accessToken, refreshToken, err = ts.jwtProvider.GenerateTokens(userID, &user.Email, nil)
 	if err != nil {
 		return "", "", errors.New("failed to generate tokens")
 	}
 
 	return accessToken, refreshToken, nil
 }
+
+// GenerateMagicLink creates a single-use sign-in token for the account, unknown and locked accounts get none so the
+// response does not reveal which emails are registered
+func (ts *TokenService) GenerateMagicLink(ctx context.Context, email string) (string, error) {
+	magicLinkConfig := config.LoadMagicLinkConfig()
+
+	user, err := ts.userRepo.ReadByEmail(ctx, email)
+	if err != nil {
+		return "", errors.New("failed to retrieve user")
+	}
+	if user == nil || user.IsLockActive() {
+		return "", nil
+	}
+
+	// Hold back repeated requests so the inbox is not flooded
+	sentKey := fmt.Sprintf("magic_link_sent:%s", user.ID.Hex())
+	if sent, err := ts.redisProvider.Get(ctx, sentKey); err == nil && sent != "" {
+		return "", nil
+	}
+
+	token, err := helper.GenerateCode(32)
+	if err != nil {
+		return "", errors.New("failed to generate magic link")
+	}
+	if err := ts.redisProvider.Set(ctx, fmt.Sprintf("magic_link:%s", helper.HashToken(token)), user.ID.Hex(), magicLinkConfig.Expiry); err != nil {
+		return "", errors.New("failed to store magic link")
+	}
+
+	return helper.SignToken(token), nil
+}
+
+// MarkMagicLinkSent holds back further links for the account once one has been emailed, so a failed send does not
+// leave the user waiting for a link that never arrives
+func (ts *TokenService) MarkMagicLinkSent(ctx context.Context, signedToken string) error {
+	magicLinkConfig := config.LoadMagicLinkConfig()
+
+	token, ok := helper.VerifySignedToken(signedToken)
+	if !ok {
+		return errors.New("invalid or expired magic link")
+	}
+	userID, err := ts.redisProvider.Get(ctx, fmt.Sprintf("magic_link:%s", helper.HashToken(token)))
+	if err != nil || userID == "" {
+		return errors.New("invalid or expired magic link")
+	}
+
+	sentKey := fmt.Sprintf("magic_link_sent:%s", userID)
+	if err := ts.redisProvider.Set(ctx, sentKey, "1", magicLinkConfig.ResendInterval); err != nil {
+		return errors.New("failed to store magic link")
+	}
+	return nil
+}
+
+// AuthenticateMagicLink consumes a magic link and returns its user without signing them in, callers require MFA
+// first and then mint tokens through IssueMagicLinkTokens, so only failures are recorded here
+func (ts *TokenService) AuthenticateMagicLink(ctx context.Context, signedToken string) (user *model.User, err error) {
+	defer func() {
+		if err != nil {
+			ts.recordLogin(ctx, "magic_link", user, "", err)
+		}
+	}()
+
+	token, ok := helper.VerifySignedToken(signedToken)
+	if !ok {
+		return nil, errors.New("invalid or expired magic link")
+	}
+
+	// Links are single-use
+	key := fmt.Sprintf("magic_link:%s", helper.HashToken(token))
+	userID, err := ts.redisProvider.GetDel(ctx, key)
+	if err != nil || userID == "" {
+		return nil, errors.New("invalid or expired magic link")
+	}
+
+	// Check if user exists
+	user, err = ts.userRepo.Read(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user no longer exists")
+	}
+
+	// Check if user account is locked due to too many failed login attempts
+	if user.IsLockActive() {
+		return user, errors.New("account locked due to multiple failed login attempts")
+	}
+
+	return user, nil
+}
+
+// IssueMagicLinkTokens signs in a user whose magic link needs no second factor
+func (ts *TokenService) IssueMagicLinkTokens(ctx context.Context, user *model.User) (accessToken string, refreshToken string, err error) {
+	defer func() { ts.recordLogin(ctx, "magic_link", user, "", err) }()
+
+	accessToken, refreshToken, err = ts.jwtProvider.GenerateTokens(user.ID.Hex(), &user.Email, nil)
+	if err != nil {
+		return "", "", errors.New("failed to generate tokens")
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// AuthenticateClient validates service account credentials and issues a short-lived client access token.
+// Key accounts send a signed JWT assertion instead of a secret, the assertion then identifies the client.
+func (ts *TokenService) AuthenticateClient(ctx context.Context, clientID, clientSecret, clientAssertion, scope string) (accessToken string, expiresIn time.Duration, scopes []string, err error) {
+	var serviceAccount *model.ServiceAccount
+	defer func() {
+		var ownerID *primitive.ObjectID
+		if serviceAccount != nil {
+			ownerID = &serviceAccount.UserID
+			clientID = serviceAccount.ClientID
+		}
+		ts.loginEvents.Record(ctx, "service_account", ownerID, clientID, err)
+	}()
+
+	// Check the credentials against the account's unexpired secrets or keys, this records the exchange as its last use
+	if clientAssertion != "" {
+		serviceAccount, err = ts.serviceAccountService.AuthenticateAssertion(ctx, clientAssertion)
+		if err == nil && clientID != "" && clientID != serviceAccount.ClientID {
+			err = errors.New("invalid client credentials")
+		}
+	} else {
+		serviceAccount, err = ts.serviceAccountService.Authenticate(ctx, clientID, clientSecret)
+	}
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	// Resolve the service account's policy
+	policy, err := ts.policyService.ResolvePolicy(ctx, serviceAccount.PolicyID.Hex())
+	if err != nil {
+		return "", 0, nil, err
+	}
+
+	// Scopes are space-delimited as per RFC 6749 and may only narrow the policy
+	scopes = strings.Fields(scope)
+	allowed, err := ts.policyService.ScopesAllowed(ctx, policy, scopes)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if !allowed {
+		return "", 0, nil, errors.New("invalid scope")
+	}
+
+	accessToken, expiresIn, err = ts.jwtProvider.GenerateClientToken(serviceAccount.UserID.Hex(), serviceAccount.ClientID, policy.ID.Hex(), scopes)
+	if err != nil {
+		return "", 0, nil, errors.New("failed to generate tokens")
+	}
+
+	return accessToken, expiresIn, scopes, nil
+}
+
+// recordLogin stores the outcome of a user login attempt
+func (ts *TokenService) recordLogin(ctx context.Context, method string, user *model.User, identifier string, err error) {
+	var userID *primitive.ObjectID
+	if user != nil {
+		userID = &user.ID
+	}
+	ts.loginEvents.Record(ctx, method, userID, identifier, err)
+}
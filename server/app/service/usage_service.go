@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const usageDateLayout = "2006-01-02"
+
+// usageCounterMetrics are the daily counters kept in Redis until the day is snapshotted
+var usageCounterMetrics = []string{"bytes_uploaded", "bytes_downloaded", "api_calls"}
+
+// readCountersScript returns the counters at the given keys, missing counters come back as false
+const readCountersScript = `return redis.call('MGET', unpack(KEYS))`
+
+// UsageService meters storage, transfer and API calls for billing. Transfers are counted per day
+// against the storage owner in Redis and, once the day is over, recorded as daily snapshots per
+// user and per organization pool.
+type UsageService struct {
+	usageSnapshotRepo *repository.UsageSnapshotRepository
+	userRepo          *repository.UserRepository
+	orgRepo           *repository.OrganizationRepository
+	limitRepo         *repository.LimitRepository
+	fileRepo          *repository.FileRepository
+	userService       *UserService
+	redisProvider     *provider.RedisProvider
+}
+
+// UsageSummary is a subject's usage over a period
+type UsageSummary struct {
+	SubjectType     string  `json:"subject_type"`
+	SubjectID       string  `json:"subject_id"`
+	Name            string  `json:"name,omitempty"` // The user's email or the organization's name
+	From            string  `json:"from"`
+	To              string  `json:"to"`
+	Days            int     `json:"days"` // Days with a snapshot
+	PeakBytesStored int64   `json:"peak_bytes_stored"`
+	GBMonths        float64 `json:"gb_months"`
+	BytesUploaded   int64   `json:"bytes_uploaded"`
+	BytesDownloaded int64   `json:"bytes_downloaded"`
+	FileCount       int64   `json:"file_count"` // On the last day with a snapshot
+	APICalls        int64   `json:"api_calls"`
+}
+
+// UsageReport is a subject's usage over a period with its daily snapshots
+type UsageReport struct {
+	Summary   UsageSummary          `json:"summary"`
+	Snapshots []model.UsageSnapshot `json:"snapshots"`
+}
+
+// NewUsageService initializes a new UsageService
+func NewUsageService(
+	usageSnapshotRepo *repository.UsageSnapshotRepository,
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrganizationRepository,
+	limitRepo *repository.LimitRepository,
+	fileRepo *repository.FileRepository,
+	userService *UserService,
+	redisProvider *provider.RedisProvider,
+) *UsageService {
+	return &UsageService{
+		usageSnapshotRepo: usageSnapshotRepo,
+		userRepo:          userRepo,
+		orgRepo:           orgRepo,
+		limitRepo:         limitRepo,
+		fileRepo:          fileRepo,
+		userService:       userService,
+		redisProvider:     redisProvider,
+	}
+}
+
+// RecordUpload counts bytes stored by the owner today, a user or a team drive
+func (us *UsageService) RecordUpload(ctx context.Context, ownerID string, bytes int64) {
+	us.increment(ctx, "bytes_uploaded", ownerID, bytes)
+}
+
+// RecordDownload counts bytes served from the owner's storage today, whoever downloaded them
+func (us *UsageService) RecordDownload(ctx context.Context, ownerID string, bytes int64) {
+	us.increment(ctx, "bytes_downloaded", ownerID, bytes)
+}
+
+// RecordAPICall counts a request the user made to the clients API today
+func (us *UsageService) RecordAPICall(ctx context.Context, userID string) {
+	us.increment(ctx, "api_calls", userID, 1)
+}
+
+// increment adds to a daily counter. Metering never fails the request it is counting.
+func (us *UsageService) increment(ctx context.Context, metric, ownerID string, value int64) {
+	if ownerID == "" || value <= 0 {
+		return
+	}
+
+	usageConfig := config.LoadUsageConfig()
+	key := usageCounterKey(time.Now().UTC().Format(usageDateLayout), metric, ownerID)
+	if _, err := us.redisProvider.IncrBy(ctx, key, value, usageConfig.CounterExpiry); err != nil {
+		logger.Log.Error("failed to record usage", logger.String("metric", metric), logger.Error(err))
+	}
+}
+
+// SnapshotUsage records the previous UTC day for every user and every organization with a shared pool.
+// Stored bytes and file counts are read from the same counters the dashboard metrics use, so they are
+// as of the run rather than midnight. Reruns overwrite the day until it has been fully recorded.
+func (us *UsageService) SnapshotUsage(ctx context.Context) error {
+	usageConfig := config.LoadUsageConfig()
+	date := time.Now().UTC().AddDate(0, 0, -1).Format(usageDateLayout)
+	doneKey := "usage_snapshot_done:" + date
+	if done, err := us.redisProvider.Get(ctx, doneKey); err == nil && done != "" {
+		return nil
+	}
+
+	if err := us.snapshotUsers(ctx, date); err != nil {
+		return err
+	}
+	if err := us.snapshotOrganizations(ctx, date); err != nil {
+		return err
+	}
+
+	if err := us.redisProvider.Set(ctx, doneKey, "1", usageConfig.CounterExpiry); err != nil {
+		logger.Log.Error("failed to mark usage snapshot done", logger.Error(err))
+	}
+	return nil
+}
+
+// snapshotUsers records every user's own storage and transfers
+func (us *UsageService) snapshotUsers(ctx context.Context, date string) error {
+	const pageSize = 100
+	for page := 1; ; page++ {
+		users, err := us.userRepo.List(ctx, page, pageSize)
+		if err != nil {
+			return errors.New("failed to list users")
+		}
+
+		for _, user := range users {
+			limit, _, fileCount, _, err := us.userService.CollectMetrics(ctx, user.ID.Hex())
+			if err != nil {
+				// Users half-way through registration or deletion have nothing to bill
+				logger.Log.Warn("skipping usage snapshot", logger.String("user_id", user.ID.Hex()), logger.Error(err))
+				continue
+			}
+
+			counters, err := us.readCounters(ctx, date, []string{user.ID.Hex()})
+			if err != nil {
+				return err
+			}
+
+			snapshot := &model.UsageSnapshot{
+				SubjectType:     "user",
+				SubjectID:       user.ID,
+				Date:            date,
+				BytesStored:     limit.BytesUsage,
+				BytesUploaded:   counters["bytes_uploaded"],
+				BytesDownloaded: counters["bytes_downloaded"],
+				FileCount:       fileCount,
+				APICalls:        counters["api_calls"],
+			}
+			if err := us.usageSnapshotRepo.Upsert(ctx, snapshot); err != nil {
+				return errors.New("failed to save usage snapshot")
+			}
+		}
+
+		if len(users) < pageSize {
+			return nil
+		}
+	}
+}
+
+// snapshotOrganizations records each organization pool, which covers its pooled members and team drives
+func (us *UsageService) snapshotOrganizations(ctx context.Context, date string) error {
+	const pageSize = 100
+	for page := 1; ; page++ {
+		organizations, err := us.orgRepo.List(ctx, page, pageSize)
+		if err != nil {
+			return errors.New("failed to list organizations")
+		}
+
+		for _, organization := range organizations {
+			orgID := organization.ID.Hex()
+			pool, err := us.limitRepo.ReadByOrganizationID(ctx, orgID)
+			if err != nil {
+				return errors.New("failed to retrieve organization limit")
+			}
+			if pool == nil {
+				continue
+			}
+
+			members, err := us.limitRepo.ListMembersByOrganizationID(ctx, orgID)
+			if err != nil {
+				return errors.New("failed to retrieve organization members")
+			}
+
+			ownerIDs := make([]string, 0, len(members))
+			var fileCount int64
+			for _, member := range members {
+				files, err := us.fileRepo.CountByUserID(ctx, member.UserID.Hex())
+				if err != nil {
+					return errors.New("failed to count files")
+				}
+				fileCount += files
+				ownerIDs = append(ownerIDs, member.UserID.Hex())
+			}
+
+			counters, err := us.readCounters(ctx, date, ownerIDs)
+			if err != nil {
+				return err
+			}
+
+			snapshot := &model.UsageSnapshot{
+				SubjectType:     "organization",
+				SubjectID:       organization.ID,
+				Date:            date,
+				BytesStored:     pool.BytesUsage,
+				BytesUploaded:   counters["bytes_uploaded"],
+				BytesDownloaded: counters["bytes_downloaded"],
+				FileCount:       fileCount,
+				APICalls:        counters["api_calls"],
+			}
+			if err := us.usageSnapshotRepo.Upsert(ctx, snapshot); err != nil {
+				return errors.New("failed to save usage snapshot")
+			}
+		}
+
+		if len(organizations) < pageSize {
+			return nil
+		}
+	}
+}
+
+// readCounters sums the day's counters of the owners by metric. Missing counters are zero, an
+// unreachable Redis is an error so the day is not recorded as empty.
+func (us *UsageService) readCounters(ctx context.Context, date string, ownerIDs []string) (map[string]int64, error) {
+	totals := make(map[string]int64, len(usageCounterMetrics))
+	if len(ownerIDs) == 0 {
+		return totals, nil
+	}
+
+	keys := make([]string, 0, len(ownerIDs)*len(usageCounterMetrics))
+	for _, ownerID := range ownerIDs {
+		for _, metric := range usageCounterMetrics {
+			keys = append(keys, usageCounterKey(date, metric, ownerID))
+		}
+	}
+
+	raw, err := us.redisProvider.Eval(ctx, readCountersScript, keys)
+	if err != nil {
+		logger.Log.Error("failed to read usage counters", logger.Error(err))
+		return nil, errors.New("failed to read usage counters")
+	}
+	values, ok := raw.([]any)
+	if !ok || len(values) != len(keys) {
+		return nil, errors.New("unexpected usage counters result")
+	}
+
+	for i, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid usage counter")
+		}
+		totals[usageCounterMetrics[i%len(usageCounterMetrics)]] += count
+	}
+	return totals, nil
+}
+
+// ReadUserUsage returns a user's daily snapshots between two days, both included
+func (us *UsageService) ReadUserUsage(ctx context.Context, userID, from, to string) (*UsageReport, error) {
+	if _, err := primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	if err := validateUsagePeriod(from, to); err != nil {
+		return nil, err
+	}
+
+	user, err := us.userRepo.Read(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve user")
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return us.readUsage(ctx, "user", userID, user.Email, from, to)
+}
+
+// ReadOrganizationUsage returns an organization's daily snapshots between two days, both included
+func (us *UsageService) ReadOrganizationUsage(ctx context.Context, orgID, from, to string) (*UsageReport, error) {
+	if _, err := primitive.ObjectIDFromHex(orgID); err != nil {
+		return nil, errors.New("invalid organization ID")
+	}
+	if err := validateUsagePeriod(from, to); err != nil {
+		return nil, err
+	}
+
+	organization, err := us.orgRepo.Read(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve organization")
+	}
+	if organization == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	return us.readUsage(ctx, "organization", orgID, organization.Name, from, to)
+}
+
+func (us *UsageService) readUsage(ctx context.Context, subjectType, subjectID, name, from, to string) (*UsageReport, error) {
+	snapshots, err := us.usageSnapshotRepo.ListBySubject(ctx, subjectType, subjectID, from, to)
+	if err != nil {
+		return nil, errors.New("failed to retrieve usage")
+	}
+
+	summary := summarizeUsage(subjectType, subjectID, from, to, snapshots)
+	summary.Name = name
+	return &UsageReport{Summary: summary, Snapshots: snapshots}, nil
+}
+
+// ExportUsage returns the usage of every user or every organization with a snapshot between two days
+func (us *UsageService) ExportUsage(ctx context.Context, subjectType, from, to string) ([]UsageSummary, error) {
+	if subjectType != "user" && subjectType != "organization" {
+		return nil, errors.New("invalid subject type")
+	}
+	if err := validateUsagePeriod(from, to); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := us.usageSnapshotRepo.ListByPeriod(ctx, subjectType, from, to)
+	if err != nil {
+		return nil, errors.New("failed to retrieve usage")
+	}
+
+	// Snapshots come grouped by subject
+	summaries := []UsageSummary{}
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].SubjectID == snapshots[start].SubjectID {
+			end++
+		}
+
+		subjectID := snapshots[start].SubjectID.Hex()
+		summary := summarizeUsage(subjectType, subjectID, from, to, snapshots[start:end])
+		summary.Name = us.subjectName(ctx, subjectType, subjectID)
+		summaries = append(summaries, summary)
+		start = end
+	}
+	return summaries, nil
+}
+
+// subjectName labels an export row, subjects deleted since they were metered have none
+func (us *UsageService) subjectName(ctx context.Context, subjectType, subjectID string) string {
+	if subjectType == "user" {
+		if user, err := us.userRepo.Read(ctx, subjectID); err == nil && user != nil {
+			return user.Email
+		}
+		return ""
+	}
+	if organization, err := us.orgRepo.Read(ctx, subjectID); err == nil && organization != nil {
+		return organization.Name
+	}
+	return ""
+}
+
+// summarizeUsage totals the snapshots. Each day stores its bytes for 1/days-in-month of a month,
+// which sums to GB-months.
+func summarizeUsage(subjectType, subjectID, from, to string, snapshots []model.UsageSnapshot) UsageSummary {
+	usageConfig := config.LoadUsageConfig()
+	summary := UsageSummary{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		From:        from,
+		To:          to,
+		Days:        len(snapshots),
+	}
+
+	for _, snapshot := range snapshots {
+		day, err := time.Parse(usageDateLayout, snapshot.Date)
+		if err != nil {
+			continue
+		}
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+		summary.PeakBytesStored = max(summary.PeakBytesStored, snapshot.BytesStored)
+		summary.GBMonths += float64(snapshot.BytesStored) / float64(usageConfig.BytesPerGB) / float64(daysInMonth)
+		summary.BytesUploaded += snapshot.BytesUploaded
+		summary.BytesDownloaded += snapshot.BytesDownloaded
+		summary.FileCount = snapshot.FileCount
+		summary.APICalls += snapshot.APICalls
+	}
+	return summary
+}
+
+// validateUsagePeriod checks that from and to are days in order and not too far apart
+func validateUsagePeriod(from, to string) error {
+	fromDay, err := time.Parse(usageDateLayout, from)
+	if err != nil {
+		return errors.New("invalid period")
+	}
+	toDay, err := time.Parse(usageDateLayout, to)
+	if err != nil || toDay.Before(fromDay) {
+		return errors.New("invalid period")
+	}
+
+	usageConfig := config.LoadUsageConfig()
+	if int(toDay.Sub(fromDay).Hours()/24)+1 > usageConfig.MaxPeriodDays {
+		return fmt.Errorf("period too long, at most %d days", usageConfig.MaxPeriodDays)
+	}
+	return nil
+}
+
+func usageCounterKey(date, metric, ownerID string) string {
+	return fmt.Sprintf("usage:%s:%s:%s", date, metric, ownerID)
+}
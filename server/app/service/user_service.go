@@ -18,16 +18,19 @@ import (
 )
 
 type UserService struct {
-	userRepo      *repository.UserRepository
-	profileRepo   *repository.ProfileRepository
-	settingRepo   *repository.SettingRepository
-	roleRepo      *repository.RoleRepository
-	userRoleRepo  *repository.UserRoleRepository
-	limitRepo     *repository.LimitRepository
-	directoryRepo *repository.DirectoryRepository
-	fileRepo      *repository.FileRepository
-	svcAccRepo    *repository.ServiceAccountRepository
-	redisProvider *provider.RedisProvider
+	userRepo          *repository.UserRepository
+	profileRepo       *repository.ProfileRepository
+	settingRepo       *repository.SettingRepository
+	roleRepo          *repository.RoleRepository
+	userRoleRepo      *repository.UserRoleRepository
+	limitRepo         *repository.LimitRepository
+	planRepo          *repository.SubscriptionPlanRepository
+	directoryRepo     *repository.DirectoryRepository
+	fileRepo          *repository.FileRepository
+	svcAccRepo        *repository.ServiceAccountRepository
+	trustedDeviceRepo *repository.TrustedDeviceRepository
+	redisProvider     *provider.RedisProvider
+	permissionService *PermissionService
 }
 
 func NewUserService(
@@ -37,25 +40,42 @@ func NewUserService(
 	roleRepo *repository.RoleRepository,
 	userRoleRepo *repository.UserRoleRepository,
 	limitRepo *repository.LimitRepository,
+	planRepo *repository.SubscriptionPlanRepository,
 	directoryRepo *repository.DirectoryRepository,
 	fileRepo *repository.FileRepository,
 	svcAccRepo *repository.ServiceAccountRepository,
+	trustedDeviceRepo *repository.TrustedDeviceRepository,
 	redisProvider *provider.RedisProvider,
+	permissionService *PermissionService,
 ) *UserService {
 	return &UserService{
-		userRepo:      userRepo,
-		profileRepo:   profileRepo,
-		settingRepo:   settingRepo,
-		roleRepo:      roleRepo,
-		userRoleRepo:  userRoleRepo,
-		limitRepo:     limitRepo,
-		directoryRepo: directoryRepo,
-		fileRepo:      fileRepo,
-		svcAccRepo:    svcAccRepo,
-		redisProvider: redisProvider,
+		userRepo:          userRepo,
+		profileRepo:       profileRepo,
+		settingRepo:       settingRepo,
+		roleRepo:          roleRepo,
+		userRoleRepo:      userRoleRepo,
+		limitRepo:         limitRepo,
+		planRepo:          planRepo,
+		directoryRepo:     directoryRepo,
+		fileRepo:          fileRepo,
+		svcAccRepo:        svcAccRepo,
+		trustedDeviceRepo: trustedDeviceRepo,
+		redisProvider:     redisProvider,
+		permissionService: permissionService,
 	}
 }
 
+// DefaultBytesLimit is the storage of a new account, the default plan's when it is in the catalog
+func (us *UserService) DefaultBytesLimit(ctx context.Context) int64 {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	plan, err := us.planRepo.ReadByName(ctx, subscriptionConfig.DefaultPlan)
+	if err != nil || plan == nil {
+		return config.LoadUserConfig().DefaultBytesLimit
+	}
+	return plan.BytesLimit
+}
+
 func (us *UserService) ListUsers(ctx context.Context, page, limit int) ([]*model.User, error) {
 	// Fetch users from the repository
 	users, err := us.userRepo.List(ctx, page, limit)
@@ -114,6 +134,26 @@ func (us *UserService) UserExists(ctx context.Context, email string) (bool, erro
 	return user != nil, nil
 }
 
+// GetUserByEmail returns the user with the given email, or nil if there is none
+func (us *UserService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	user, err := us.userRepo.ReadByEmail(ctx, email)
+	if err != nil {
+		logger.Log.Error("failed to retrieve user", logger.Error(err))
+		return nil, errors.New("failed to retrieve user")
+	}
+	return user, nil
+}
+
+// GetUserByEmailCaseInsensitive retrieves a user by email regardless of letter case
+func (us *UserService) GetUserByEmailCaseInsensitive(ctx context.Context, email string) (*model.User, error) {
+	user, err := us.userRepo.ReadByEmailCaseInsensitive(ctx, email)
+	if err != nil {
+		logger.Log.Error("failed to retrieve user", logger.Error(err))
+		return nil, errors.New("failed to retrieve user")
+	}
+	return user, nil
+}
+
 // Create registers a new user
 func (us *UserService) CreateUser(ctx context.Context, request *dto.CreateUserDTO) (*model.User, *model.Profile, *model.UserRole, string, error) {
 	// Load user configuration
@@ -186,7 +226,7 @@ func (us *UserService) CreateUser(ctx context.Context, request *dto.CreateUserDT
 	limit := &model.Limit{
 		UserID:         user.ID,
 		OrganizationID: nil,
-		BytesLimit:     userConfig.DefaultBytesLimit,
+		BytesLimit:     us.DefaultBytesLimit(ctx),
 		BytesUsage:     0,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -212,6 +252,29 @@ func (us *UserService) CreateUser(ctx context.Context, request *dto.CreateUserDT
 	return user, profile, userRoleAssignment, userRole.Name, nil
 }
 
+// DiscardUser removes an account created by CreateUser that was never used, such as when the step it was created for fails
+func (us *UserService) DiscardUser(ctx context.Context, user *model.User) error {
+	userID := user.ID.Hex()
+	steps := []func(ctx context.Context, userID string) error{
+		us.directoryRepo.DeleteByUserID,
+		us.limitRepo.DeleteByUserID,
+		us.userRoleRepo.DeleteByUserID,
+		us.settingRepo.Delete,
+		us.profileRepo.Delete,
+	}
+	for _, step := range steps {
+		if err := step(ctx, userID); err != nil {
+			logger.Log.Error("failed to discard user", logger.Error(err))
+			return errors.New("failed to discard user")
+		}
+	}
+	if err := us.userRepo.Delete(ctx, user.Email); err != nil {
+		logger.Log.Error("failed to discard user", logger.Error(err))
+		return errors.New("failed to discard user")
+	}
+	return nil
+}
+
 // ChangePassword changes the user's password
 func (us *UserService) ChangePassword(ctx context.Context, userID string, request *dto.ChangePasswordDTO) error {
 	// Fetch the user from the repository
@@ -252,6 +315,12 @@ func (us *UserService) ChangePassword(ctx context.Context, userID string, reques
 		return errors.New("failed to update password")
 	}
 
+	// A new password revokes every remembered browser
+	if err := us.trustedDeviceRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		logger.Log.Error("failed to revoke trusted devices", logger.Error(err))
+		return errors.New("failed to revoke trusted devices")
+	}
+
 	return nil
 }
 
@@ -326,7 +395,9 @@ func (us *UserService) ResetPassword(ctx context.Context, email, resetCode, newP
 
 	// Reset the user's is_locked status if applicable
 	update := map[string]any{
-		"is_locked": false,
+		"is_locked":     false,
+		"locked_until":  nil,
+		"lockout_count": 0,
 	}
 	if err := us.userRepo.UpdateByEmail(ctx, email, update); err != nil {
 		return fmt.Errorf("failed to update user lock status")
@@ -346,6 +417,11 @@ func (us *UserService) ResetPassword(ctx context.Context, email, resetCode, newP
 		return fmt.Errorf("failed to update password")
 	}
 
+	// A new password revokes every remembered browser
+	if err := us.trustedDeviceRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		return fmt.Errorf("failed to revoke trusted devices")
+	}
+
 	return nil
 }
 func (us *UserService) GetUserInfo(ctx context.Context, userID string) (
@@ -579,16 +655,17 @@ func (us *UserService) GenerateVerificationCode(ctx context.Context, userID stri
 func (us *UserService) Update(ctx context.Context, userID string, request *dto.UpdateUserDTO) error {
 	// Prepare the update fields
 	update := bson.M{
-		"first_name":  request.FirstName,
-		"middle_name": request.MiddleName,
-		"last_name":   request.LastName,
-		"suffix":      request.Suffix,
-		"email":       request.Email,
-		"role":        request.Role,
-		"is_verified": request.IsVerified,
-		"is_locked":   request.IsLocked,
-		"is_deleted":  request.IsDeleted,
-		"updated_at":  time.Now(),
+		"first_name":   request.FirstName,
+		"middle_name":  request.MiddleName,
+		"last_name":    request.LastName,
+		"suffix":       request.Suffix,
+		"email":        request.Email,
+		"role":         request.Role,
+		"is_verified":  request.IsVerified,
+		"is_locked":    request.IsLocked,
+		"locked_until": nil, // Locks set by an admin do not expire
+		"is_deleted":   request.IsDeleted,
+		"updated_at":   time.Now(),
 	}
 
 	// Hash the password if it is being updated
@@ -646,14 +723,16 @@ func (us *UserService) UpdateUser(ctx context.Context, userID string, dto *dto.U
 			logger.Log.Error("error updating user role", logger.Error(err))
 			return nil, nil, nil, "", errors.New("failed to update user role")
 		}
+		us.permissionService.InvalidateUserPermissions(ctx, userID)
 	}
 
 	// Update user fields
 	userUpdate := bson.M{
-		"email":       dto.Email,
-		"is_verified": dto.IsVerified,
-		"is_locked":   dto.IsLocked,
-		"is_deleted":  dto.IsDeleted,
+		"email":        dto.Email,
+		"is_verified":  dto.IsVerified,
+		"is_locked":    dto.IsLocked,
+		"locked_until": nil, // Locks set by an admin do not expire
+		"is_deleted":   dto.IsDeleted,
 	}
 
 	// Hash the password if it is being updated
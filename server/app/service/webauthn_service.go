@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+
+	"bongaquino/server/app/dto"
+	"bongaquino/server/app/helper"
+	"bongaquino/server/app/model"
+	"bongaquino/server/app/provider"
+	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
+	"bongaquino/server/core/logger"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WebAuthnService runs passkey registration and assertion ceremonies.
+// Attestation statements are not verified since registration requests "none" attestation.
+type WebAuthnService struct {
+	credentialRepo *repository.WebAuthnCredentialRepository
+	userRepo       *repository.UserRepository
+	redisProvider  *provider.RedisProvider
+}
+
+// NewWebAuthnService initializes a new WebAuthnService
+func NewWebAuthnService(credentialRepo *repository.WebAuthnCredentialRepository, userRepo *repository.UserRepository, redisProvider *provider.RedisProvider) *WebAuthnService {
+	return &WebAuthnService{
+		credentialRepo: credentialRepo,
+		userRepo:       userRepo,
+		redisProvider:  redisProvider,
+	}
+}
+
+// BeginRegistration returns the creation options for a new passkey, in the browser's JSON encoding
+func (ws *WebAuthnService) BeginRegistration(ctx context.Context, userID string) (map[string]any, error) {
+	webauthnConfig := config.LoadWebAuthnConfig()
+
+	user, err := ws.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return nil, errors.New("error fetching user")
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	credentials, err := ws.credentialRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("error fetching passkeys")
+	}
+
+	challenge, err := ws.storeChallenge(ctx, "webauthn_registration:"+userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"rp": map[string]any{
+			"id":   webauthnConfig.RPID,
+			"name": webauthnConfig.RPName,
+		},
+		"user": map[string]any{
+			"id":          helper.EncodeBase64URL([]byte(userID)),
+			"name":        user.Email,
+			"displayName": user.Email,
+		},
+		"challenge": challenge,
+		"pubKeyCredParams": []map[string]any{
+			{"type": "public-key", "alg": helper.COSEAlgES256},
+			{"type": "public-key", "alg": helper.COSEAlgEdDSA},
+			{"type": "public-key", "alg": helper.COSEAlgRS256},
+		},
+		"timeout":            webauthnConfig.ChallengeExpiry.Milliseconds(),
+		"excludeCredentials": credentialDescriptors(credentials),
+		"authenticatorSelection": map[string]any{
+			"residentKey":      "preferred",
+			"userVerification": "preferred",
+		},
+		"attestation": "none",
+	}, nil
+}
+
+// FinishRegistration verifies a registration response and stores the new passkey
+func (ws *WebAuthnService) FinishRegistration(ctx context.Context, userID string, name string, attestation *dto.WebAuthnAttestationDTO) (*model.WebAuthnCredential, error) {
+	user, err := ws.userRepo.Read(ctx, userID)
+	if err != nil {
+		logger.Log.Error("error fetching user", logger.Error(err))
+		return nil, errors.New("error fetching user")
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	challenge, err := ws.consumeChallenge(ctx, "webauthn_registration:"+userID)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataJSON, err := helper.DecodeBase64URL(attestation.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	if err := verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	attestationObject, err := helper.DecodeBase64URL(attestation.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	rawAuthData, _, err := helper.ParseWebAuthnAttestationObject(attestationObject)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	authData, err := verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.CredentialID == nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	if _, _, err := helper.ParseCOSEKey(authData.PublicKey); err != nil {
+		return nil, errors.New("unsupported passkey algorithm")
+	}
+
+	credentialID := helper.EncodeBase64URL(authData.CredentialID)
+	existing, err := ws.credentialRepo.ReadByCredentialID(ctx, credentialID)
+	if err != nil {
+		return nil, errors.New("error fetching passkey")
+	}
+	if existing != nil {
+		return nil, errors.New("passkey is already registered")
+	}
+
+	if name == "" {
+		name = "Passkey"
+	}
+	credential := &model.WebAuthnCredential{
+		UserID:       user.ID,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    authData.PublicKey,
+		SignCount:    int64(authData.SignCount),
+		Transports:   attestation.Response.Transports,
+		AAGUID:       hex.EncodeToString(authData.AAGUID),
+	}
+	if err := ws.credentialRepo.Create(ctx, credential); err != nil {
+		return nil, errors.New("error saving passkey")
+	}
+
+	return credential, nil
+}
+
+// ListCredentials returns the user's passkeys
+func (ws *WebAuthnService) ListCredentials(ctx context.Context, userID string) ([]model.WebAuthnCredential, error) {
+	credentials, err := ws.credentialRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("error fetching passkeys")
+	}
+	return credentials, nil
+}
+
+// HasCredentials reports whether the user has registered any passkey
+func (ws *WebAuthnService) HasCredentials(ctx context.Context, userID string) (bool, error) {
+	count, err := ws.credentialRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return false, errors.New("error fetching passkeys")
+	}
+	return count > 0, nil
+}
+
+// DeleteCredential removes one of the user's passkeys
+func (ws *WebAuthnService) DeleteCredential(ctx context.Context, userID string, id string) error {
+	deleted, err := ws.credentialRepo.DeleteByIDUserID(ctx, id, userID)
+	if err != nil {
+		return errors.New("passkey not found")
+	}
+	if !deleted {
+		return errors.New("passkey not found")
+	}
+	return nil
+}
+
+// BeginAssertion returns request options for signing in with a passkey.
+// With a user ID the user's passkeys are listed, without one the browser offers discoverable passkeys
+// and user verification is required since the passkey is the only factor.
+func (ws *WebAuthnService) BeginAssertion(ctx context.Context, session string, userID string) (map[string]any, error) {
+	webauthnConfig := config.LoadWebAuthnConfig()
+
+	allowCredentials := []map[string]any{}
+	userVerification := "required"
+	if userID != "" {
+		userVerification = "preferred"
+		credentials, err := ws.credentialRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, errors.New("error fetching passkeys")
+		}
+		if len(credentials) == 0 {
+			return nil, errors.New("no passkeys registered")
+		}
+		allowCredentials = credentialDescriptors(credentials)
+	}
+
+	challenge, err := ws.storeChallenge(ctx, "webauthn_assertion:"+session)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"challenge":        challenge,
+		"rpId":             webauthnConfig.RPID,
+		"timeout":          webauthnConfig.ChallengeExpiry.Milliseconds(),
+		"allowCredentials": allowCredentials,
+		"userVerification": userVerification,
+	}, nil
+}
+
+// FinishAssertion verifies a passkey assertion and returns the ID of the user it belongs to.
+// When userID is set the passkey must belong to that user, otherwise user verification is required.
+func (ws *WebAuthnService) FinishAssertion(ctx context.Context, session string, userID string, assertion *dto.WebAuthnAssertionDTO) (string, error) {
+	challenge, err := ws.consumeChallenge(ctx, "webauthn_assertion:"+session)
+	if err != nil {
+		return "", err
+	}
+
+	credential, err := ws.credentialRepo.ReadByCredentialID(ctx, assertion.ID)
+	if err != nil {
+		return "", errors.New("error fetching passkey")
+	}
+	if credential == nil {
+		return "", errors.New("passkey not recognized")
+	}
+	if userID != "" && credential.UserID.Hex() != userID {
+		return "", errors.New("passkey not recognized")
+	}
+	if assertion.Response.UserHandle != "" {
+		userHandle, err := helper.DecodeBase64URL(assertion.Response.UserHandle)
+		if err != nil || string(userHandle) != credential.UserID.Hex() {
+			return "", errors.New("passkey not recognized")
+		}
+	}
+
+	clientDataJSON, err := helper.DecodeBase64URL(assertion.Response.ClientDataJSON)
+	if err != nil {
+		return "", errors.New("invalid passkey response")
+	}
+	if err := verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return "", err
+	}
+
+	rawAuthData, err := helper.DecodeBase64URL(assertion.Response.AuthenticatorData)
+	if err != nil {
+		return "", errors.New("invalid passkey response")
+	}
+	authData, err := verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return "", err
+	}
+	if userID == "" && authData.Flags&helper.WebAuthnFlagUserVerified == 0 {
+		return "", errors.New("user verification required")
+	}
+
+	signature, err := helper.DecodeBase64URL(assertion.Response.Signature)
+	if err != nil {
+		return "", errors.New("invalid passkey response")
+	}
+	if err := helper.VerifyWebAuthnSignature(credential.PublicKey, rawAuthData, clientDataJSON, signature); err != nil {
+		return "", errors.New("invalid passkey signature")
+	}
+
+	// A counter that does not move forward means the authenticator may have been cloned
+	signCount := int64(authData.SignCount)
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		logger.Log.Warn("passkey sign count did not increase", logger.String("credential_id", credential.CredentialID))
+		return "", errors.New("passkey sign count is out of sync")
+	}
+
+	err = ws.credentialRepo.Update(ctx, credential.ID, bson.M{"sign_count": signCount, "last_used_at": time.Now()})
+	if err != nil {
+		return "", errors.New("error updating passkey")
+	}
+
+	return credential.UserID.Hex(), nil
+}
+
+// storeChallenge generates a random challenge and keeps it for the ceremony's lifetime
+func (ws *WebAuthnService) storeChallenge(ctx context.Context, key string) (string, error) {
+	webauthnConfig := config.LoadWebAuthnConfig()
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("error generating challenge")
+	}
+	challenge := helper.EncodeBase64URL(raw)
+
+	if err := ws.redisProvider.Set(ctx, key, challenge, webauthnConfig.ChallengeExpiry); err != nil {
+		logger.Log.Error("error storing webauthn challenge", logger.Error(err))
+		return "", errors.New("error storing challenge")
+	}
+	return challenge, nil
+}
+
+// consumeChallenge fetches and deletes a pending challenge in one step so each one is only used once
+func (ws *WebAuthnService) consumeChallenge(ctx context.Context, key string) (string, error) {
+	challenge, err := ws.redisProvider.GetDel(ctx, key)
+	if err != nil || challenge == "" {
+		return "", errors.New("passkey challenge expired")
+	}
+	return challenge, nil
+}
+
+// verifyClientData checks the ceremony type, challenge and origin the browser signed
+func verifyClientData(clientDataJSON []byte, ceremony string, challenge string) error {
+	webauthnConfig := config.LoadWebAuthnConfig()
+
+	clientData, err := helper.ParseWebAuthnClientData(clientDataJSON)
+	if err != nil {
+		return errors.New("invalid passkey response")
+	}
+	if clientData.Type != ceremony {
+		return errors.New("invalid passkey response")
+	}
+	if clientData.Challenge != challenge {
+		return errors.New("passkey challenge mismatch")
+	}
+	if !slices.Contains(webauthnConfig.RPOrigins, clientData.Origin) {
+		return errors.New("passkey origin not allowed")
+	}
+	return nil
+}
+
+// verifyAuthenticatorData checks that the data is bound to our relying party and the user was present
+func verifyAuthenticatorData(raw []byte) (*helper.WebAuthnAuthenticatorData, error) {
+	webauthnConfig := config.LoadWebAuthnConfig()
+
+	authData, err := helper.ParseWebAuthnAuthenticatorData(raw)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	rpIDHash := sha256.Sum256([]byte(webauthnConfig.RPID))
+	if !bytes.Equal(authData.RPIDHash, rpIDHash[:]) {
+		return nil, errors.New("passkey relying party mismatch")
+	}
+	if authData.Flags&helper.WebAuthnFlagUserPresent == 0 {
+		return nil, errors.New("user presence required")
+	}
+	return authData, nil
+}
+
+// credentialDescriptors lists passkeys in the form the browser expects for allow and exclude lists
+func credentialDescriptors(credentials []model.WebAuthnCredential) []map[string]any {
+	descriptors := make([]map[string]any, len(credentials))
+	for i, credential := range credentials {
+		descriptor := map[string]any{"type": "public-key", "id": credential.CredentialID}
+		if len(credential.Transports) > 0 {
+			descriptor["transports"] = credential.Transports
+		}
+		descriptors[i] = descriptor
+	}
+	return descriptors
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"bongaquino/server/core/env"
+	"time"
+)
+
+// AccountConfig holds the data export and account deletion configuration
+type AccountConfig struct {
+	DeletionGracePeriod   time.Duration
+	DeletionJobInterval   time.Duration
+	ExportExpiry          time.Duration
+	ExportJobInterval     time.Duration
+	ExportMaxContentBytes int64
+}
+
+func LoadAccountConfig() *AccountConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &AccountConfig{
+		// DeletionGracePeriod is how long a deletion request can be cancelled before the account is erased
+		DeletionGracePeriod: time.Duration(envVars.AccountDeletionGraceDays) * 24 * time.Hour,
+
+		// DeletionJobInterval is set to 1 hour, how often accounts past their grace period are erased
+		DeletionJobInterval: time.Hour,
+
+		// ExportExpiry is how long the download link of a finished export works
+		ExportExpiry: time.Duration(envVars.DataExportDays) * 24 * time.Hour,
+
+		// ExportJobInterval is set to 15 minutes, how often requested exports are built, a run has that long to finish
+		ExportJobInterval: 15 * time.Minute,
+
+		// ExportMaxContentBytes is set to 10 GiB, exports with more file contents have to leave them out
+		ExportMaxContentBytes: 10 << 30,
+	}
+}
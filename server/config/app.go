@@ -4,11 +4,13 @@ import "bongaquino/server/core/env"
 
 // AppConfig holds the application configuration
 type AppConfig struct {
-	AppName    string
-	AppVersion string
-	AppKey     string
-	Mode       string
-	Port       int
+	AppName     string
+	AppVersion  string
+	AppKey      string
+	Mode        string
+	Port        int
+	FrontendURL string
+	AppURL      string
 }
 
 func LoadAppConfig() *AppConfig {
@@ -17,10 +19,12 @@ func LoadAppConfig() *AppConfig {
 
 	// Create the configuration from environment variables
 	return &AppConfig{
-		AppName:    envVars.AppName,
-		AppVersion: envVars.AppVersion,
-		AppKey:     envVars.AppKey,
-		Mode:       envVars.Mode,
-		Port:       envVars.Port,
+		AppName:     envVars.AppName,
+		AppVersion:  envVars.AppVersion,
+		AppKey:      envVars.AppKey,
+		Mode:        envVars.Mode,
+		Port:        envVars.Port,
+		FrontendURL: envVars.FrontendURL,
+		AppURL:      envVars.AppURL,
 	}
 }
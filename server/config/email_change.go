@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// EmailChangeConfig holds the login email change configuration
+type EmailChangeConfig struct {
+	CodeExpiry  time.Duration
+	MaxAttempts int
+}
+
+func LoadEmailChangeConfig() *EmailChangeConfig {
+	// Create the configuration from environment variables
+	return &EmailChangeConfig{
+		// CodeExpiry is set to 30 minutes, the pending change and its cancel link lapse with it
+		CodeExpiry: 30 * time.Minute,
+
+		// MaxAttempts wrong confirmation codes drop the pending change
+		MaxAttempts: 5,
+	}
+}
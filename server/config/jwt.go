@@ -7,6 +7,7 @@ type JWTConfig struct {
 	JWTSecret            string
 	JWTTokenExpiration   int
 	JWTRefreshExpiration int
+	JWTClientExpiration  int
 }
 
 func LoadJWTConfig() *JWTConfig {
@@ -18,5 +19,6 @@ func LoadJWTConfig() *JWTConfig {
 		JWTSecret:            envVars.JWTSecret,
 		JWTTokenExpiration:   envVars.JWTTokenExpiration,
 		JWTRefreshExpiration: envVars.JWTRefreshExpiration,
+		JWTClientExpiration:  envVars.JWTClientExpiration,
 	}
 }
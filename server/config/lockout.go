@@ -0,0 +1,44 @@
+package config
+
+import "time"
+
+// LockoutConfig holds the login lockout configuration
+type LockoutConfig struct {
+	MaxAttempts       int64
+	AttemptWindow     time.Duration
+	BaseDuration      time.Duration
+	MaxDuration       time.Duration
+	EscalationWindow  time.Duration
+	IPMaxAttempts     int64
+	IPWindow          time.Duration
+	UnlockTokenExpiry time.Duration
+}
+
+func LoadLockoutConfig() *LockoutConfig {
+	// Create the configuration from environment variables
+	return &LockoutConfig{
+		// MaxAttempts failed logins for one account from one address within AttemptWindow lock that address out of it
+		MaxAttempts: 5,
+
+		// AttemptWindow is set to 15 minutes
+		AttemptWindow: 15 * time.Minute,
+
+		// BaseDuration is the first address lockout, each consecutive lockout doubles it
+		BaseDuration: 15 * time.Minute,
+
+		// MaxDuration caps the lockout at 24 hours
+		MaxDuration: 24 * time.Hour,
+
+		// EscalationWindow is how long a lockout counts toward doubling the next one from the same address
+		EscalationWindow: 24 * time.Hour,
+
+		// IPMaxAttempts failed logins from one address within IPWindow throttle that address without locking accounts
+		IPMaxAttempts: 20,
+
+		// IPWindow is set to 15 minutes
+		IPWindow: 15 * time.Minute,
+
+		// UnlockTokenExpiry is set to 1 hour
+		UnlockTokenExpiry: time.Hour,
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// MagicLinkConfig holds the passwordless sign-in link configuration
+type MagicLinkConfig struct {
+	Expiry         time.Duration
+	ResendInterval time.Duration
+}
+
+func LoadMagicLinkConfig() *MagicLinkConfig {
+	// Create the configuration from environment variables
+	return &MagicLinkConfig{
+		// Expiry is set to 10 minutes
+		Expiry: 10 * time.Minute,
+
+		// ResendInterval is set to 1 minute, requests within it send no new email
+		ResendInterval: time.Minute,
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"bongaquino/server/core/env"
+	"time"
+)
+
+// MFAConfig holds the multi-factor authentication configuration
+type MFAConfig struct {
+	RecoveryCodeCount   int
+	RecoveryCodeLength  int
+	TrustedDeviceExpiry time.Duration
+	TrustedDeviceCookie string
+	TrustedDeviceHeader string
+}
+
+func LoadMFAConfig() *MFAConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &MFAConfig{
+		// RecoveryCodeCount is the number of single-use recovery codes issued at a time
+		RecoveryCodeCount: 10,
+
+		// RecoveryCodeLength is the number of random bytes per code, shown as 20 hex characters
+		RecoveryCodeLength: 10,
+
+		// TrustedDeviceExpiry is how long a remembered browser skips the second factor
+		TrustedDeviceExpiry: time.Duration(envVars.TrustedDeviceDays) * 24 * time.Hour,
+
+		// TrustedDeviceCookie is the cookie a remembered browser sends its trusted device token in
+		TrustedDeviceCookie: "trusted_device",
+
+		// TrustedDeviceHeader carries the trusted device token for clients without cookies
+		TrustedDeviceHeader: "Trusted-Device",
+	}
+}
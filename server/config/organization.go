@@ -0,0 +1,49 @@
+package config
+
+import "time"
+
+// OrganizationConfig holds the organization configuration
+type OrganizationConfig struct {
+	AdminRole        string
+	RoleRanks        map[string]int
+	InvitationExpiry time.Duration
+	DomainRecordName string
+	DomainRecordTag  string
+	AutoJoinRole     string
+	DriveAccess      map[string][]string
+}
+
+func LoadOrganizationConfig() *OrganizationConfig {
+	// Create the configuration from environment variables
+	return &OrganizationConfig{
+		// AdminRole manages members and service accounts of its own organization
+		AdminRole: "organization_admin",
+
+		// RoleRanks orders the organization roles, members can never grant a role above their own
+		RoleRanks: map[string]int{
+			"organization_admin":  3,
+			"organization_user":   2,
+			"organization_viewer": 1,
+		},
+
+		// InvitationExpiry is set to 7 days
+		InvitationExpiry: 7 * 24 * time.Hour,
+
+		// DomainRecordName is prefixed to the domain to name the TXT record holding the verification token
+		DomainRecordName: "_bongaquino-verification",
+
+		// DomainRecordTag is prefixed to the token in the TXT record value
+		DomainRecordTag: "bongaquino-verification=",
+
+		// AutoJoinRole is assigned to users joining through a verified domain unless the organization picks another
+		AutoJoinRole: "organization_viewer",
+
+		// DriveAccess is what each organization role can do in the organization's team drives:
+		// read lists and downloads, write uploads and edits, manage deletes and shares
+		DriveAccess: map[string][]string{
+			"organization_admin":  {"read", "write", "manage"},
+			"organization_user":   {"read", "write"},
+			"organization_viewer": {"read"},
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+// PermissionConfig holds the user permission configuration
+type PermissionConfig struct {
+	CacheExpiry time.Duration
+}
+
+func LoadPermissionConfig() *PermissionConfig {
+	// Create the configuration from environment variables
+	return &PermissionConfig{
+		// CacheExpiry is set to 5 minutes
+		CacheExpiry: 5 * time.Minute,
+	}
+}
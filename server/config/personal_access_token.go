@@ -0,0 +1,41 @@
+package config
+
+import "time"
+
+// PersonalAccessTokenConfig holds the personal access token configuration
+type PersonalAccessTokenConfig struct {
+	Prefix           string
+	TokenLength      int
+	MaxPerUser       int64
+	MaxExpiry        time.Duration
+	LastUsedInterval time.Duration
+	Scopes           map[string][]string
+}
+
+func LoadPersonalAccessTokenConfig() *PersonalAccessTokenConfig {
+	// Create the configuration from environment variables
+	return &PersonalAccessTokenConfig{
+		// Prefix marks bearer tokens as personal access tokens rather than JWTs
+		Prefix: "pat_",
+
+		// TokenLength is the number of random bytes per token, shown as 64 hex characters
+		TokenLength: 32,
+
+		// MaxPerUser is the number of tokens a user may hold at once
+		MaxPerUser: 50,
+
+		// MaxExpiry is set to 1 year, tokens without an expiry never expire
+		MaxExpiry: 365 * 24 * time.Hour,
+
+		// LastUsedInterval is set to 1 minute, last-used tracking is written at most this often per token
+		LastUsedInterval: time.Minute,
+
+		// Scopes maps each grantable scope to the permissions it covers
+		Scopes: map[string][]string{
+			"files:read":        {"file:read", "file:download"},
+			"files:write":       {"file:upload", "file:edit", "file:delete"},
+			"directories:read":  {"directory:read"},
+			"directories:write": {"directory:add", "directory:edit", "directory:delete"},
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// PolicyConfig holds the service account policy configuration
+type PolicyConfig struct {
+	DefaultServiceAccountPolicy string
+	PermissionCacheExpiry       time.Duration
+}
+
+func LoadPolicyConfig() *PolicyConfig {
+	// Create the configuration from environment variables
+	return &PolicyConfig{
+		// DefaultServiceAccountPolicy applies to service accounts created without a policy
+		DefaultServiceAccountPolicy: "default_service_account_policy",
+
+		// PermissionCacheExpiry is set to 5 minutes
+		PermissionCacheExpiry: 5 * time.Minute,
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"bongaquino/server/core/env"
+	"time"
+)
+
+// RateLimitRule allows Limit requests per key within a sliding Window
+type RateLimitRule struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// RateLimitPolicy applies a rule to a group of routes
+type RateLimitPolicy struct {
+	KeyBy string        // "ip", "user" or "client", falling back to the IP when the caller is anonymous
+	Rule  RateLimitRule // Subscription plans can replace it for their callers
+}
+
+// RateLimitConfig holds the rate limiting configuration
+type RateLimitConfig struct {
+	Enabled  bool
+	Policies map[string]RateLimitPolicy
+}
+
+func LoadRateLimitConfig() *RateLimitConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &RateLimitConfig{
+		// Enabled turns every rate limit on or off
+		Enabled: envVars.RateLimitEnabled,
+
+		Policies: map[string]RateLimitPolicy{
+			// global applies to every request
+			"global": {
+				KeyBy: "ip",
+				Rule:  RateLimitRule{Limit: 300, Window: time.Minute},
+			},
+
+			// auth covers login, token and passkey requests
+			"auth": {
+				KeyBy: "ip",
+				Rule:  RateLimitRule{Limit: 20, Window: time.Minute},
+			},
+
+			// recovery covers registration, password resets and unlock links
+			"recovery": {
+				KeyBy: "ip",
+				Rule:  RateLimitRule{Limit: 5, Window: 15 * time.Minute},
+			},
+
+			// public covers anonymous file reads and downloads
+			"public": {
+				KeyBy: "ip",
+				Rule:  RateLimitRule{Limit: 60, Window: time.Minute},
+			},
+
+			// upload covers file uploads by users and service accounts
+			"upload": {
+				KeyBy: "user",
+				Rule:  RateLimitRule{Limit: 30, Window: time.Minute},
+			},
+
+			// api covers every service account request
+			"api": {
+				KeyBy: "client",
+				Rule:  RateLimitRule{Limit: 600, Window: time.Minute},
+			},
+		},
+	}
+}
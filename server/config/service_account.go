@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bongaquino/server/core/env"
+	"time"
+)
+
+// ServiceAccountConfig holds the service account credential lifecycle configuration
+type ServiceAccountConfig struct {
+	MaxSecrets          int
+	SecretLifetime      time.Duration
+	RotationGracePeriod time.Duration
+	ExpiryWarning       time.Duration
+	InactivityLimit     time.Duration
+	JobInterval         time.Duration
+	KeyAlgorithm        string
+	KeyBits             int
+	TokenURI            string
+	AssertionMaxAge     time.Duration
+	ReadWriteAccess     string
+	ReadOnlyAccess      string
+	WriteOnlyAccess     string
+}
+
+func LoadServiceAccountConfig() *ServiceAccountConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &ServiceAccountConfig{
+		// MaxSecrets is the number of secrets a client ID may have active at once, so agents can move to a new one
+		MaxSecrets: 2,
+
+		// SecretLifetime is how long a newly issued secret is valid
+		SecretLifetime: time.Duration(envVars.ServiceAccountSecretDays) * 24 * time.Hour,
+
+		// RotationGracePeriod is set to 24 hours, the previous secret keeps working this long after a rotation by default
+		RotationGracePeriod: 24 * time.Hour,
+
+		// ExpiryWarning is how long before a secret expires its owner is emailed
+		ExpiryWarning: time.Duration(envVars.ServiceAccountExpiryWarningDays) * 24 * time.Hour,
+
+		// InactivityLimit is how long a service account may go unused before it is disabled
+		InactivityLimit: time.Duration(envVars.ServiceAccountInactiveDays) * 24 * time.Hour,
+
+		// JobInterval is set to 1 hour, how often expiry warnings and inactivity are checked
+		JobInterval: time.Hour,
+
+		// KeyAlgorithm is the signing algorithm of minted key pairs and the only one accepted in assertions
+		KeyAlgorithm: "RS256",
+
+		// KeyBits is the RSA modulus size of minted key pairs
+		KeyBits: 2048,
+
+		// TokenURI is written into key files and is the audience assertions must name
+		TokenURI: envVars.AppURL + "/oauth/token",
+
+		// AssertionMaxAge is set to 1 hour, assertions expiring further out are rejected
+		AssertionMaxAge: time.Hour,
+
+		// ReadWriteAccess is the default access to a directory scope, allowing every operation
+		ReadWriteAccess: "read_write",
+
+		// ReadOnlyAccess allows reading and downloading only
+		ReadOnlyAccess: "read_only",
+
+		// WriteOnlyAccess turns the scope into a drop box, allowing only uploads and new directories
+		WriteOnlyAccess: "write_only",
+	}
+}
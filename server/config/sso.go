@@ -0,0 +1,51 @@
+package config
+
+import (
+	"time"
+
+	"bongaquino/server/core/env"
+)
+
+// SSOConfig holds the OpenID Connect single sign-on configuration
+type SSOConfig struct {
+	RedirectURL          string
+	Scopes               []string
+	StateExpiry          time.Duration
+	HTTPTimeout          time.Duration
+	MetadataCacheExpiry  time.Duration
+	AllowInsecureIssuers bool
+	DefaultRole          string
+	SystemRole           string
+}
+
+func LoadSSOConfig() *SSOConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &SSOConfig{
+		// RedirectURL is the frontend page the IdP sends the authorization code back to
+		RedirectURL: envVars.FrontendURL + "/sso/callback",
+
+		// Scopes requested from the IdP, email is needed to map users
+		Scopes: []string{"openid", "email", "profile"},
+
+		// StateExpiry is set to 10 minutes
+		StateExpiry: 10 * time.Minute,
+
+		// HTTPTimeout is set to 10 seconds
+		HTTPTimeout: 10 * time.Second,
+
+		// MetadataCacheExpiry is set to 1 hour, unknown key IDs refresh the JWKS early
+		MetadataCacheExpiry: time.Hour,
+
+		// AllowInsecureIssuers accepts http issuers and issuers on loopback or private addresses such as a local mock IdP
+		AllowInsecureIssuers: envVars.SSOAllowInsecureIssuers,
+
+		// DefaultRole is the organization role of provisioned users unless the organization picks another
+		DefaultRole: "organization_user",
+
+		// SystemRole is the system role of provisioned users
+		SystemRole: "system_user",
+	}
+}
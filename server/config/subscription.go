@@ -0,0 +1,39 @@
+package config
+
+import "time"
+
+// SubscriptionConfig holds the subscription plan configuration
+type SubscriptionConfig struct {
+	DefaultPlan      string
+	Statuses         []string
+	EntitledStatuses []string
+	Transitions      map[string][]string
+	CacheExpiry      time.Duration
+}
+
+func LoadSubscriptionConfig() *SubscriptionConfig {
+	// Create the configuration from environment variables
+	return &SubscriptionConfig{
+		// DefaultPlan applies to users without a subscription and to subscriptions that lost their plan
+		DefaultPlan: "free",
+
+		// Statuses is the subscription lifecycle
+		Statuses: []string{"trial", "active", "past_due", "canceled"},
+
+		// EntitledStatuses keep the plan, past_due is a grace period while a payment is retried
+		EntitledStatuses: []string{"trial", "active", "past_due"},
+
+		// Transitions lists the statuses each status can move to, "" is a subscription that never had a plan.
+		// A status can always be kept while the plan changes.
+		Transitions: map[string][]string{
+			"":         {"trial", "active"},
+			"trial":    {"active", "canceled"},
+			"active":   {"past_due", "canceled"},
+			"past_due": {"active", "canceled"},
+			"canceled": {"trial", "active"},
+		},
+
+		// CacheExpiry is set to 5 minutes
+		CacheExpiry: 5 * time.Minute,
+	}
+}
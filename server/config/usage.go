@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// UsageConfig holds the usage metering configuration
+type UsageConfig struct {
+	SnapshotJobInterval time.Duration
+	CounterExpiry       time.Duration
+	MaxPeriodDays       int
+	BytesPerGB          int64
+}
+
+func LoadUsageConfig() *UsageConfig {
+	// Create the configuration
+	return &UsageConfig{
+		// SnapshotJobInterval is set to 1 hour, how often the previous day's snapshots are taken if they are missing
+		SnapshotJobInterval: time.Hour,
+
+		// CounterExpiry is set to 3 days, daily upload, download and API call counters outlive their day long enough to be snapshotted
+		CounterExpiry: 3 * 24 * time.Hour,
+
+		// MaxPeriodDays is set to 366, the longest period a usage report or export may cover
+		MaxPeriodDays: 366,
+
+		// BytesPerGB is set to 1 GiB, the unit of GB-months
+		BytesPerGB: 1 << 30,
+	}
+}
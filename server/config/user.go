@@ -22,7 +22,7 @@ type UserConfig struct {
 func LoadUserConfig() *UserConfig {
 	// Create the configuration from environment variables
 	return &UserConfig{
-		// DefaultBytesLimit is set to 5GB
+		// DefaultBytesLimit is set to 5GB, used only while the default subscription plan is missing
 		DefaultBytesLimit: 5 * 1024 * 1024 * 1024,
 
 		// DefaultBackupCycle is set to "daily"
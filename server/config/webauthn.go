@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+
+	"bongaquino/server/core/env"
+)
+
+// WebAuthnConfig holds the passkey relying party configuration
+type WebAuthnConfig struct {
+	RPID            string
+	RPName          string
+	RPOrigins       []string
+	ChallengeExpiry time.Duration
+}
+
+func LoadWebAuthnConfig() *WebAuthnConfig {
+	// Load environment variables
+	envVars := env.LoadEnv()
+
+	// Create the configuration from environment variables
+	return &WebAuthnConfig{
+		// RPID is the domain passkeys are bound to, it must match the frontend's host or a parent of it
+		RPID: envVars.WebAuthnRPID,
+
+		// RPName is shown to users by their authenticator
+		RPName: envVars.AppName,
+
+		// RPOrigins lists the origins allowed to run ceremonies, comma separated in the environment
+		RPOrigins: envVars.WebAuthnRPOrigins,
+
+		// ChallengeExpiry is set to 5 minutes
+		ChallengeExpiry: 5 * time.Minute,
+	}
+}
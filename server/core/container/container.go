@@ -2,21 +2,41 @@ package container
 
 import (
 	"bongaquino/server/app/controller/admin/organizations"
+	adminOrgLimits "bongaquino/server/app/controller/admin/organizations/limits"
 	"bongaquino/server/app/controller/admin/organizations/members"
+	adminOrgSubscription "bongaquino/server/app/controller/admin/organizations/subscription"
+	"bongaquino/server/app/controller/admin/plans"
+	"bongaquino/server/app/controller/admin/policies"
+	adminUsage "bongaquino/server/app/controller/admin/usage"
 	adminUsers "bongaquino/server/app/controller/admin/users"
 	adminUserLimits "bongaquino/server/app/controller/admin/users/limits"
+	adminUserSubscription "bongaquino/server/app/controller/admin/users/subscription"
 	"bongaquino/server/app/controller/clients/directories"
 	"bongaquino/server/app/controller/clients/files"
 	"bongaquino/server/app/controller/clients/peers"
 	"bongaquino/server/app/controller/constants"
 	"bongaquino/server/app/controller/dashboard"
 	"bongaquino/server/app/controller/health"
+	"bongaquino/server/app/controller/invitations"
 	"bongaquino/server/app/controller/network"
+	"bongaquino/server/app/controller/oauth"
+	orgs "bongaquino/server/app/controller/organizations"
+	orgDomain "bongaquino/server/app/controller/organizations/domain"
+	orgDrives "bongaquino/server/app/controller/organizations/drives"
+	orgInvitations "bongaquino/server/app/controller/organizations/invitations"
+	orgJoinRequests "bongaquino/server/app/controller/organizations/joinrequests"
+	orgMembers "bongaquino/server/app/controller/organizations/members"
+	orgServiceAccounts "bongaquino/server/app/controller/organizations/serviceaccounts"
+	orgSSO "bongaquino/server/app/controller/organizations/sso"
+	orgStorage "bongaquino/server/app/controller/organizations/storage"
 	"bongaquino/server/app/controller/profile"
+	publicExports "bongaquino/server/app/controller/public/exports"
 	publicFiles "bongaquino/server/app/controller/public/files"
 	"bongaquino/server/app/controller/serviceaccounts"
 	"bongaquino/server/app/controller/settings"
+	"bongaquino/server/app/controller/settings/accesstokens"
 	"bongaquino/server/app/controller/settings/mfa"
+	"bongaquino/server/app/controller/sso"
 	"bongaquino/server/app/controller/tokens"
 	"bongaquino/server/app/controller/users"
 	"bongaquino/server/app/middleware"
@@ -32,44 +52,82 @@ type Providers struct {
 	JWT      *provider.JWTProvider
 	Postmark *provider.PostmarkProvider
 	IPFS     *provider.IPFSProvider
+	DNS      *provider.DNSProvider
+	OIDC     *provider.OIDCProvider
 }
 
 type Repositories struct {
-	Permission           *repository.PermissionRepository
-	Policy               *repository.PolicyRepository
-	PolicyPermission     *repository.PolicyPermissionRepository
-	Profile              *repository.ProfileRepository
-	Role                 *repository.RoleRepository
-	RolePermission       *repository.RolePermissionRepository
-	ServiceAccount       *repository.ServiceAccountRepository
-	User                 *repository.UserRepository
-	UserRole             *repository.UserRoleRepository
-	Organization         *repository.OrganizationRepository
-	OrganizationUserRole *repository.OrganizationUserRoleRepository
-	Limit                *repository.LimitRepository
-	Directory            *repository.DirectoryRepository
-	File                 *repository.FileRepository
-	Setting              *repository.SettingRepository
-	FileAccess           *repository.FileAccessRepository
+	Permission              *repository.PermissionRepository
+	Policy                  *repository.PolicyRepository
+	PolicyPermission        *repository.PolicyPermissionRepository
+	Profile                 *repository.ProfileRepository
+	Role                    *repository.RoleRepository
+	RolePermission          *repository.RolePermissionRepository
+	ServiceAccount          *repository.ServiceAccountRepository
+	User                    *repository.UserRepository
+	UserRole                *repository.UserRoleRepository
+	Organization            *repository.OrganizationRepository
+	OrganizationUserRole    *repository.OrganizationUserRoleRepository
+	OrganizationInvitation  *repository.OrganizationInvitationRepository
+	OrganizationJoinRequest *repository.OrganizationJoinRequestRepository
+	OrganizationSSO         *repository.OrganizationSSORepository
+	MFARecoveryCode         *repository.MFARecoveryCodeRepository
+	WebAuthnCredential      *repository.WebAuthnCredentialRepository
+	LoginEvent              *repository.LoginEventRepository
+	TrustedDevice           *repository.TrustedDeviceRepository
+	PersonalAccessToken     *repository.PersonalAccessTokenRepository
+	Limit                   *repository.LimitRepository
+	Directory               *repository.DirectoryRepository
+	File                    *repository.FileRepository
+	Setting                 *repository.SettingRepository
+	FileAccess              *repository.FileAccessRepository
+	DataExport              *repository.DataExportRepository
+	AuditLog                *repository.AuditLogRepository
+	TeamDrive               *repository.TeamDriveRepository
+	UsageSnapshot           *repository.UsageSnapshotRepository
+	SubscriptionPlan        *repository.SubscriptionPlanRepository
+	SubscriptionStatus      *repository.SubscriptionStatusRepository
 }
 
 type Services struct {
-	User           *service.UserService
-	Token          *service.TokenService
-	MFA            *service.MFAService
-	Email          *service.EmailService
-	IPFS           *service.IPFSService
-	Organization   *service.OrganizationService
-	ServiceAccount *service.ServiceAccountService
-	FS             *service.FSService
+	User                *service.UserService
+	Token               *service.TokenService
+	MFA                 *service.MFAService
+	Email               *service.EmailService
+	IPFS                *service.IPFSService
+	Organization        *service.OrganizationService
+	ServiceAccount      *service.ServiceAccountService
+	FS                  *service.FSService
+	Policy              *service.PolicyService
+	Permission          *service.PermissionService
+	WebAuthn            *service.WebAuthnService
+	Lockout             *service.LockoutService
+	RateLimit           *service.RateLimitService
+	SSO                 *service.SSOService
+	LoginEvent          *service.LoginEventService
+	PersonalAccessToken *service.PersonalAccessTokenService
+	EmailChange         *service.EmailChangeService
+	DataExport          *service.DataExportService
+	AccountDeletion     *service.AccountDeletionService
+	Quota               *service.QuotaService
+	TeamDrive           *service.TeamDriveService
+	Subscription        *service.SubscriptionService
+	Usage               *service.UsageService
 }
 
 type Middleware struct {
-	Authn    *middleware.AuthnMiddleware
-	Authz    *middleware.AuthzMiddleware
-	Verified *middleware.VerifiedMiddleware
-	Locked   *middleware.LockedMiddleware
-	API      *middleware.APIMiddleware
+	Authn        *middleware.AuthnMiddleware
+	Authz        *middleware.AuthzMiddleware
+	Verified     *middleware.VerifiedMiddleware
+	Locked       *middleware.LockedMiddleware
+	API          *middleware.APIMiddleware
+	Policy       *middleware.PolicyMiddleware
+	Organization *middleware.OrganizationMiddleware
+	Drive        *middleware.DriveMiddleware
+	Subscription *middleware.SubscriptionMiddleware
+	RateLimit    *middleware.RateLimitMiddleware
+	Usage        *middleware.UsageMiddleware
+	ClientInfo   *middleware.ClientInfoMiddleware
 }
 
 type Controllers struct {
@@ -88,24 +146,64 @@ type Controllers struct {
 		ResetPassword          *users.ResetPasswordController
 		VerifyAccount          *users.VerifyAccountController
 		ResendVerificationCode *users.ResendVerificationCodeController
+		UnlockAccount          *users.UnlockAccountController
+		CancelEmailChange      *users.CancelEmailChangeController
 	}
 	Tokens struct {
-		Request *tokens.RequestController
-		Verify  *tokens.VerifyOTPController
-		Refresh *tokens.RefreshController
-		Revoke  *tokens.RevokeController
+		Request         *tokens.RequestController
+		Verify          *tokens.VerifyOTPController
+		PasskeyOptions  *tokens.PasskeyOptionsController
+		BeginPasskey    *tokens.BeginPasskeyController
+		FinishPasskey   *tokens.FinishPasskeyController
+		MagicLink       *tokens.MagicLinkController
+		VerifyMagicLink *tokens.VerifyMagicLinkController
+		Refresh         *tokens.RefreshController
+		Revoke          *tokens.RevokeController
+	}
+	OAuth struct {
+		Token *oauth.TokenController
+	}
+	SSO struct {
+		Begin    *sso.BeginController
+		Callback *sso.CallbackController
 	}
 	Settings struct {
-		Update         *settings.UpdateController
-		ChangePassword *settings.ChangePasswordController
-		MFA            struct {
-			Generate *mfa.GenerateOTPController
-			Enable   *mfa.EnableMFAController
-			Disable  *mfa.DisableMFAController
+		Update             *settings.UpdateController
+		ChangePassword     *settings.ChangePasswordController
+		ChangeEmail        *settings.ChangeEmailController
+		ConfirmEmailChange *settings.ConfirmEmailChangeController
+		AccessTokens       struct {
+			List   *accesstokens.ListController
+			Create *accesstokens.CreateController
+			Revoke *accesstokens.RevokeController
+		}
+		MFA struct {
+			Generate      *mfa.GenerateOTPController
+			Enable        *mfa.EnableMFAController
+			Disable       *mfa.DisableMFAController
+			RecoveryCodes struct {
+				Read       *mfa.ReadRecoveryCodesController
+				Regenerate *mfa.RegenerateRecoveryCodesController
+			}
+			Passkeys struct {
+				List               *mfa.ListPasskeysController
+				BeginRegistration  *mfa.BeginPasskeyRegistrationController
+				FinishRegistration *mfa.FinishPasskeyRegistrationController
+				Delete             *mfa.DeletePasskeyController
+			}
+			Devices struct {
+				List   *mfa.ListTrustedDevicesController
+				Revoke *mfa.RevokeTrustedDeviceController
+			}
 		}
 	}
 	Profile struct {
-		Me *profile.MeController
+		Me             *profile.MeController
+		LoginHistory   *profile.LoginHistoryController
+		CreateExport   *profile.CreateExportController
+		ListExports    *profile.ListExportsController
+		DeleteAccount  *profile.DeleteAccountController
+		CancelDeletion *profile.CancelDeletionController
 	}
 	Network struct {
 		GetSwarmAddress *network.GetSwarmAddressController
@@ -114,6 +212,54 @@ type Controllers struct {
 		Browse   *serviceaccounts.BrowseController
 		Generate *serviceaccounts.GenerateController
 		Revoke   *serviceaccounts.RevokeController
+		Rotate   *serviceaccounts.RotateController
+	}
+	Organizations struct {
+		Read    *orgs.ReadController
+		Members struct {
+			Invite     *orgMembers.InviteController
+			UpdateRole *orgMembers.UpdateRoleController
+			Remove     *orgMembers.RemoveController
+		}
+		Invitations struct {
+			List   *orgInvitations.ListController
+			Resend *orgInvitations.ResendController
+			Revoke *orgInvitations.RevokeController
+		}
+		Domain struct {
+			Challenge *orgDomain.ChallengeController
+			Verify    *orgDomain.VerifyController
+			AutoJoin  *orgDomain.AutoJoinController
+		}
+		JoinRequests struct {
+			List    *orgJoinRequests.ListController
+			Approve *orgJoinRequests.ApproveController
+			Reject  *orgJoinRequests.RejectController
+		}
+		SSO struct {
+			Read   *orgSSO.ReadController
+			Update *orgSSO.UpdateController
+			Delete *orgSSO.DeleteController
+		}
+		ServiceAccounts struct {
+			Browse   *orgServiceAccounts.BrowseController
+			Generate *orgServiceAccounts.GenerateController
+			Revoke   *orgServiceAccounts.RevokeController
+			Rotate   *orgServiceAccounts.RotateController
+		}
+		Storage struct {
+			Usage             *orgStorage.UsageController
+			UpdateMemberLimit *orgStorage.UpdateMemberLimitController
+		}
+		Drives struct {
+			List   *orgDrives.ListController
+			Create *orgDrives.CreateController
+			Update *orgDrives.UpdateController
+			Delete *orgDrives.DeleteController
+		}
+	}
+	Invitations struct {
+		Accept *invitations.AcceptController
 	}
 	Clients struct {
 		Peers struct {
@@ -140,22 +286,52 @@ type Controllers struct {
 			Limits struct {
 				Update *adminUserLimits.UpdateController
 			}
-			List   *adminUsers.ListController
-			Create *adminUsers.CreateController
-			Read   *adminUsers.ReadController
-			Update *adminUsers.UpdateController
-			Search *adminUsers.SearchController
+			Subscription struct {
+				Update *adminUserSubscription.UpdateController
+			}
+			List         *adminUsers.ListController
+			Create       *adminUsers.CreateController
+			Read         *adminUsers.ReadController
+			Update       *adminUsers.UpdateController
+			Search       *adminUsers.SearchController
+			Unlock       *adminUsers.UnlockController
+			LoginHistory *adminUsers.LoginHistoryController
+			Usage        *adminUsers.UsageController
 		}
 		Organizations struct {
 			List    *organizations.ListController
 			Create  *organizations.CreateController
 			Read    *organizations.ReadController
 			Update  *organizations.UpdateController
+			Usage   *organizations.UsageController
 			Members struct {
 				Add        *members.AddController
 				UpdateRole *members.UpdateRoleController
 				Remove     *members.RemoveController
 			}
+			Limits struct {
+				Update *adminOrgLimits.UpdateController
+			}
+			Subscription struct {
+				Update *adminOrgSubscription.UpdateController
+			}
+		}
+		Policies struct {
+			List     *policies.ListController
+			Create   *policies.CreateController
+			Read     *policies.ReadController
+			Update   *policies.UpdateController
+			Simulate *policies.SimulateController
+		}
+		Plans struct {
+			List   *plans.ListController
+			Create *plans.CreateController
+			Read   *plans.ReadController
+			Update *plans.UpdateController
+			Delete *plans.DeleteController
+		}
+		Usage struct {
+			Export *adminUsage.ExportController
 		}
 	}
 	Public struct {
@@ -163,6 +339,9 @@ type Controllers struct {
 			Download *publicFiles.DownloadController
 			Read     *publicFiles.ReadController
 		}
+		Exports struct {
+			Download *publicExports.DownloadController
+		}
 	}
 }
 
@@ -180,51 +359,89 @@ func initProviders() Providers {
 	postmark := provider.NewPostmarkProvider()
 	jwt := provider.NewJWTProvider(redis)
 	ipfs := provider.NewIPFSProvider()
-	return Providers{mongo, redis, jwt, postmark, ipfs}
+	dns := provider.NewDNSProvider()
+	oidc := provider.NewOIDCProvider()
+	return Providers{mongo, redis, jwt, postmark, ipfs, dns, oidc}
 }
 
 func initRepositories(p Providers) Repositories {
 	return Repositories{
-		Permission:           repository.NewPermissionRepository(p.Mongo),
-		Policy:               repository.NewPolicyRepository(p.Mongo),
-		PolicyPermission:     repository.NewPolicyPermissionRepository(p.Mongo),
-		Profile:              repository.NewProfileRepository(p.Mongo),
-		Role:                 repository.NewRoleRepository(p.Mongo),
-		Setting:              repository.NewSettingRepository(p.Mongo),
-		RolePermission:       repository.NewRolePermissionRepository(p.Mongo),
-		ServiceAccount:       repository.NewServiceAccountRepository(p.Mongo),
-		User:                 repository.NewUserRepository(p.Mongo),
-		UserRole:             repository.NewUserRoleRepository(p.Mongo),
-		Organization:         repository.NewOrganizationRepository(p.Mongo),
-		OrganizationUserRole: repository.NewOrganizationUserRoleRepository(p.Mongo),
-		Limit:                repository.NewLimitRepository(p.Mongo),
-		Directory:            repository.NewDirectoryRepository(p.Mongo),
-		File:                 repository.NewFileRepository(p.Mongo),
-		FileAccess:           repository.NewFileAccessRepository(p.Mongo),
+		Permission:              repository.NewPermissionRepository(p.Mongo),
+		Policy:                  repository.NewPolicyRepository(p.Mongo),
+		PolicyPermission:        repository.NewPolicyPermissionRepository(p.Mongo),
+		Profile:                 repository.NewProfileRepository(p.Mongo),
+		Role:                    repository.NewRoleRepository(p.Mongo),
+		Setting:                 repository.NewSettingRepository(p.Mongo),
+		RolePermission:          repository.NewRolePermissionRepository(p.Mongo),
+		ServiceAccount:          repository.NewServiceAccountRepository(p.Mongo),
+		User:                    repository.NewUserRepository(p.Mongo),
+		UserRole:                repository.NewUserRoleRepository(p.Mongo),
+		Organization:            repository.NewOrganizationRepository(p.Mongo),
+		OrganizationUserRole:    repository.NewOrganizationUserRoleRepository(p.Mongo),
+		OrganizationInvitation:  repository.NewOrganizationInvitationRepository(p.Mongo),
+		OrganizationJoinRequest: repository.NewOrganizationJoinRequestRepository(p.Mongo),
+		OrganizationSSO:         repository.NewOrganizationSSORepository(p.Mongo),
+		MFARecoveryCode:         repository.NewMFARecoveryCodeRepository(p.Mongo),
+		WebAuthnCredential:      repository.NewWebAuthnCredentialRepository(p.Mongo),
+		LoginEvent:              repository.NewLoginEventRepository(p.Mongo),
+		TrustedDevice:           repository.NewTrustedDeviceRepository(p.Mongo),
+		PersonalAccessToken:     repository.NewPersonalAccessTokenRepository(p.Mongo),
+		Limit:                   repository.NewLimitRepository(p.Mongo),
+		Directory:               repository.NewDirectoryRepository(p.Mongo),
+		File:                    repository.NewFileRepository(p.Mongo),
+		FileAccess:              repository.NewFileAccessRepository(p.Mongo),
+		DataExport:              repository.NewDataExportRepository(p.Mongo),
+		AuditLog:                repository.NewAuditLogRepository(p.Mongo),
+		TeamDrive:               repository.NewTeamDriveRepository(p.Mongo),
+		UsageSnapshot:           repository.NewUsageSnapshotRepository(p.Mongo),
+		SubscriptionPlan:        repository.NewSubscriptionPlanRepository(p.Mongo),
+		SubscriptionStatus:      repository.NewSubscriptionStatusRepository(p.Mongo),
 	}
 }
 
 func initServices(p Providers, r Repositories) Services {
+	permission := service.NewPermissionService(r.UserRole, r.OrganizationUserRole, r.Role, r.RolePermission, r.Permission, p.Redis)
 	user := service.NewUserService(r.User, r.Profile, r.Setting, r.Role, r.UserRole,
-		r.Limit, r.Directory, r.File, r.ServiceAccount, p.Redis)
+		r.Limit, r.SubscriptionPlan, r.Directory, r.File, r.ServiceAccount, r.TrustedDevice, p.Redis, permission)
 	email := service.NewEmailService(p.Postmark)
-	mfa := service.NewMFAService(r.User, r.Setting, p.Redis)
+	loginEvent := service.NewLoginEventService(r.LoginEvent, r.User, email)
+	webauthn := service.NewWebAuthnService(r.WebAuthnCredential, r.User, p.Redis)
+	lockout := service.NewLockoutService(r.User, email, p.Redis)
+	rateLimit := service.NewRateLimitService(p.Redis)
+	mfa := service.NewMFAService(r.User, r.Setting, r.MFARecoveryCode, r.TrustedDevice, email, webauthn, p.Redis)
 	ipfs := service.NewIPFSService(p.IPFS)
-	token := service.NewTokenService(r.User, p.JWT, mfa, p.Redis)
+	policy := service.NewPolicyService(r.Policy, r.PolicyPermission, r.Permission, r.Directory, r.File, p.Redis)
+	quota := service.NewQuotaService(r.Limit, r.Organization, r.OrganizationUserRole, r.User)
+	subscription := service.NewSubscriptionService(r.SubscriptionPlan, r.SubscriptionStatus, r.User, r.Organization, r.OrganizationUserRole, r.TeamDrive, r.ServiceAccount, quota, p.Redis)
+	serviceAccount := service.NewServiceAccountService(r.ServiceAccount, r.User, r.Limit, r.Directory, r.TeamDrive, policy, subscription, email, p.JWT, p.Redis)
+	token := service.NewTokenService(r.User, serviceAccount, p.JWT, mfa, webauthn, lockout, loginEvent, policy, p.Redis)
+	teamDrive := service.NewTeamDriveService(r.TeamDrive, r.OrganizationUserRole, r.Role, r.Directory, r.File, r.Limit)
 	organization := service.NewOrganizationService(r.Organization, r.Policy, r.Permission,
-		r.OrganizationUserRole, r.User, r.Role)
-	serviceAccount := service.NewServiceAccountService(r.ServiceAccount, r.User, r.Limit)
+		r.OrganizationUserRole, r.OrganizationInvitation, r.OrganizationJoinRequest, r.User, r.Role, p.DNS, permission, quota)
 	fs := service.NewFSService(p.Redis, r.Directory, r.File, r.FileAccess)
-	return Services{user, token, mfa, email, ipfs, organization, serviceAccount, fs}
+	personalAccessToken := service.NewPersonalAccessTokenService(r.PersonalAccessToken, r.User)
+	emailChange := service.NewEmailChangeService(r.User, email, p.JWT, p.Redis)
+	dataExport := service.NewDataExportService(r.DataExport, r.User, r.Profile, r.Setting, r.Directory, r.File, r.FileAccess, r.AuditLog, ipfs, email)
+	accountDeletion := service.NewAccountDeletionService(r.User, r.Profile, r.Setting, r.UserRole, r.Limit, r.Directory, r.File, r.FileAccess, r.ServiceAccount, r.PersonalAccessToken, r.TrustedDevice, r.MFARecoveryCode, r.WebAuthnCredential, r.OrganizationUserRole, r.LoginEvent, r.DataExport, r.AuditLog, email, quota, p.JWT, p.Redis)
+	usage := service.NewUsageService(r.UsageSnapshot, r.User, r.Organization, r.Limit, r.File, user, p.Redis)
+	sso := service.NewSSOService(r.OrganizationSSO, r.Organization, r.OrganizationUserRole, r.User, r.Role, user, organization, loginEvent, p.OIDC, p.JWT, p.Redis)
+	return Services{user, token, mfa, email, ipfs, organization, serviceAccount, fs, policy, permission, webauthn, lockout, rateLimit, sso, loginEvent, personalAccessToken, emailChange, dataExport, accountDeletion, quota, teamDrive, subscription, usage}
 }
 
-func initMiddleware(p Providers, r Repositories) Middleware {
+func initMiddleware(p Providers, r Repositories, s Services) Middleware {
 	return Middleware{
-		Authn:    middleware.NewAuthnMiddleware(p.JWT),
-		Authz:    middleware.NewAuthzMiddleware(r.UserRole, r.Role),
-		Verified: middleware.NewVerifiedMiddleware(r.User),
-		Locked:   middleware.NewLockedMiddleware(r.User),
-		API:      middleware.NewAPIMiddleware(r.ServiceAccount),
+		Authn:        middleware.NewAuthnMiddleware(p.JWT, s.PersonalAccessToken),
+		Authz:        middleware.NewAuthzMiddleware(r.UserRole, r.Role, s.Permission),
+		Verified:     middleware.NewVerifiedMiddleware(r.User),
+		Locked:       middleware.NewLockedMiddleware(r.User),
+		API:          middleware.NewAPIMiddleware(s.ServiceAccount, p.JWT),
+		Policy:       middleware.NewPolicyMiddleware(s.Policy, s.ServiceAccount),
+		Organization: middleware.NewOrganizationMiddleware(r.OrganizationUserRole, r.Role),
+		Drive:        middleware.NewDriveMiddleware(s.TeamDrive),
+		Subscription: middleware.NewSubscriptionMiddleware(s.Subscription),
+		RateLimit:    middleware.NewRateLimitMiddleware(s.RateLimit),
+		Usage:        middleware.NewUsageMiddleware(s.Usage),
+		ClientInfo:   middleware.NewClientInfoMiddleware(),
 	}
 }
 
@@ -251,49 +468,156 @@ func initControllers(s Services) Controllers {
 			ResetPassword          *users.ResetPasswordController
 			VerifyAccount          *users.VerifyAccountController
 			ResendVerificationCode *users.ResendVerificationCodeController
+			UnlockAccount          *users.UnlockAccountController
+			CancelEmailChange      *users.CancelEmailChangeController
 		}{
 			Register:               users.NewRegisterController(s.User, s.Token, s.Email),
 			ForgotPassword:         users.NewForgotPasswordController(s.User, s.Email),
 			ResetPassword:          users.NewResetPasswordController(s.User),
-			VerifyAccount:          users.NewVerifyAccountController(s.User),
+			VerifyAccount:          users.NewVerifyAccountController(s.User, s.Organization),
 			ResendVerificationCode: users.NewResendVerificationCodeController(s.User, s.Email),
+			UnlockAccount:          users.NewUnlockAccountController(s.Lockout),
+			CancelEmailChange:      users.NewCancelEmailChangeController(s.EmailChange),
 		},
 		Tokens: struct {
-			Request *tokens.RequestController
-			Verify  *tokens.VerifyOTPController
-			Refresh *tokens.RefreshController
-			Revoke  *tokens.RevokeController
+			Request         *tokens.RequestController
+			Verify          *tokens.VerifyOTPController
+			PasskeyOptions  *tokens.PasskeyOptionsController
+			BeginPasskey    *tokens.BeginPasskeyController
+			FinishPasskey   *tokens.FinishPasskeyController
+			MagicLink       *tokens.MagicLinkController
+			VerifyMagicLink *tokens.VerifyMagicLinkController
+			Refresh         *tokens.RefreshController
+			Revoke          *tokens.RevokeController
+		}{
+			Request:         tokens.NewRequestController(s.Token, s.User, s.MFA),
+			Verify:          tokens.NewVerifyOTPController(s.Token, s.MFA),
+			PasskeyOptions:  tokens.NewPasskeyOptionsController(s.MFA),
+			BeginPasskey:    tokens.NewBeginPasskeyController(s.Token),
+			FinishPasskey:   tokens.NewFinishPasskeyController(s.Token),
+			MagicLink:       tokens.NewMagicLinkController(s.Token, s.Email),
+			VerifyMagicLink: tokens.NewVerifyMagicLinkController(s.Token, s.User, s.MFA),
+			Refresh:         tokens.NewRefreshController(s.Token),
+			Revoke:          tokens.NewRevokeController(s.Token),
+		},
+		OAuth: struct {
+			Token *oauth.TokenController
+		}{
+			Token: oauth.NewTokenController(s.Token),
+		},
+		SSO: struct {
+			Begin    *sso.BeginController
+			Callback *sso.CallbackController
 		}{
-			Request: tokens.NewRequestController(s.Token, s.User, s.MFA),
-			Verify:  tokens.NewVerifyOTPController(s.Token, s.MFA),
-			Refresh: tokens.NewRefreshController(s.Token),
-			Revoke:  tokens.NewRevokeController(s.Token),
+			Begin:    sso.NewBeginController(s.SSO),
+			Callback: sso.NewCallbackController(s.SSO, s.User, s.MFA),
 		},
 		Settings: struct {
-			Update         *settings.UpdateController
-			ChangePassword *settings.ChangePasswordController
-			MFA            struct {
-				Generate *mfa.GenerateOTPController
-				Enable   *mfa.EnableMFAController
-				Disable  *mfa.DisableMFAController
+			Update             *settings.UpdateController
+			ChangePassword     *settings.ChangePasswordController
+			ChangeEmail        *settings.ChangeEmailController
+			ConfirmEmailChange *settings.ConfirmEmailChangeController
+			AccessTokens       struct {
+				List   *accesstokens.ListController
+				Create *accesstokens.CreateController
+				Revoke *accesstokens.RevokeController
+			}
+			MFA struct {
+				Generate      *mfa.GenerateOTPController
+				Enable        *mfa.EnableMFAController
+				Disable       *mfa.DisableMFAController
+				RecoveryCodes struct {
+					Read       *mfa.ReadRecoveryCodesController
+					Regenerate *mfa.RegenerateRecoveryCodesController
+				}
+				Passkeys struct {
+					List               *mfa.ListPasskeysController
+					BeginRegistration  *mfa.BeginPasskeyRegistrationController
+					FinishRegistration *mfa.FinishPasskeyRegistrationController
+					Delete             *mfa.DeletePasskeyController
+				}
+				Devices struct {
+					List   *mfa.ListTrustedDevicesController
+					Revoke *mfa.RevokeTrustedDeviceController
+				}
 			}
 		}{
-			Update:         settings.NewUpdateController(s.User),
-			ChangePassword: settings.NewChangePasswordController(s.User),
+			Update:             settings.NewUpdateController(s.User),
+			ChangePassword:     settings.NewChangePasswordController(s.User),
+			ChangeEmail:        settings.NewChangeEmailController(s.EmailChange),
+			ConfirmEmailChange: settings.NewConfirmEmailChangeController(s.EmailChange),
+			AccessTokens: struct {
+				List   *accesstokens.ListController
+				Create *accesstokens.CreateController
+				Revoke *accesstokens.RevokeController
+			}{
+				List:   accesstokens.NewListController(s.PersonalAccessToken),
+				Create: accesstokens.NewCreateController(s.PersonalAccessToken),
+				Revoke: accesstokens.NewRevokeController(s.PersonalAccessToken),
+			},
 			MFA: struct {
-				Generate *mfa.GenerateOTPController
-				Enable   *mfa.EnableMFAController
-				Disable  *mfa.DisableMFAController
+				Generate      *mfa.GenerateOTPController
+				Enable        *mfa.EnableMFAController
+				Disable       *mfa.DisableMFAController
+				RecoveryCodes struct {
+					Read       *mfa.ReadRecoveryCodesController
+					Regenerate *mfa.RegenerateRecoveryCodesController
+				}
+				Passkeys struct {
+					List               *mfa.ListPasskeysController
+					BeginRegistration  *mfa.BeginPasskeyRegistrationController
+					FinishRegistration *mfa.FinishPasskeyRegistrationController
+					Delete             *mfa.DeletePasskeyController
+				}
+				Devices struct {
+					List   *mfa.ListTrustedDevicesController
+					Revoke *mfa.RevokeTrustedDeviceController
+				}
 			}{
 				Generate: mfa.NewGenerateOTPController(s.MFA),
 				Enable:   mfa.NewEnableMFAController(s.MFA),
 				Disable:  mfa.NewDisableMFAController(s.MFA, s.User),
+				RecoveryCodes: struct {
+					Read       *mfa.ReadRecoveryCodesController
+					Regenerate *mfa.RegenerateRecoveryCodesController
+				}{
+					Read:       mfa.NewReadRecoveryCodesController(s.MFA),
+					Regenerate: mfa.NewRegenerateRecoveryCodesController(s.MFA, s.User),
+				},
+				Passkeys: struct {
+					List               *mfa.ListPasskeysController
+					BeginRegistration  *mfa.BeginPasskeyRegistrationController
+					FinishRegistration *mfa.FinishPasskeyRegistrationController
+					Delete             *mfa.DeletePasskeyController
+				}{
+					List:               mfa.NewListPasskeysController(s.WebAuthn),
+					BeginRegistration:  mfa.NewBeginPasskeyRegistrationController(s.WebAuthn),
+					FinishRegistration: mfa.NewFinishPasskeyRegistrationController(s.WebAuthn),
+					Delete:             mfa.NewDeletePasskeyController(s.WebAuthn),
+				},
+				Devices: struct {
+					List   *mfa.ListTrustedDevicesController
+					Revoke *mfa.RevokeTrustedDeviceController
+				}{
+					List:   mfa.NewListTrustedDevicesController(s.MFA),
+					Revoke: mfa.NewRevokeTrustedDeviceController(s.MFA),
+				},
 			},
 		},
 		Profile: struct {
-			Me *profile.MeController
+			Me             *profile.MeController
+			LoginHistory   *profile.LoginHistoryController
+			CreateExport   *profile.CreateExportController
+			ListExports    *profile.ListExportsController
+			DeleteAccount  *profile.DeleteAccountController
+			CancelDeletion *profile.CancelDeletionController
 		}{
-			Me: profile.NewMeController(s.User),
+			Me:             profile.NewMeController(s.User),
+			LoginHistory:   profile.NewLoginHistoryController(s.LoginEvent),
+			CreateExport:   profile.NewCreateExportController(s.DataExport),
+			ListExports:    profile.NewListExportsController(s.DataExport),
+			DeleteAccount:  profile.NewDeleteAccountController(s.AccountDeletion),
+			CancelDeletion: profile.NewCancelDeletionController(s.AccountDeletion),
 		},
 		Network: struct {
 			GetSwarmAddress *network.GetSwarmAddressController
@@ -304,10 +628,137 @@ func initControllers(s Services) Controllers {
 			Browse   *serviceaccounts.BrowseController
 			Generate *serviceaccounts.GenerateController
 			Revoke   *serviceaccounts.RevokeController
+			Rotate   *serviceaccounts.RotateController
 		}{
 			Browse:   serviceaccounts.NewBrowseController(s.ServiceAccount),
 			Generate: serviceaccounts.NewGenerateController(s.ServiceAccount),
 			Revoke:   serviceaccounts.NewRevokeController(s.ServiceAccount),
+			Rotate:   serviceaccounts.NewRotateController(s.ServiceAccount),
+		},
+		Organizations: struct {
+			Read    *orgs.ReadController
+			Members struct {
+				Invite     *orgMembers.InviteController
+				UpdateRole *orgMembers.UpdateRoleController
+				Remove     *orgMembers.RemoveController
+			}
+			Invitations struct {
+				List   *orgInvitations.ListController
+				Resend *orgInvitations.ResendController
+				Revoke *orgInvitations.RevokeController
+			}
+			Domain struct {
+				Challenge *orgDomain.ChallengeController
+				Verify    *orgDomain.VerifyController
+				AutoJoin  *orgDomain.AutoJoinController
+			}
+			JoinRequests struct {
+				List    *orgJoinRequests.ListController
+				Approve *orgJoinRequests.ApproveController
+				Reject  *orgJoinRequests.RejectController
+			}
+			SSO struct {
+				Read   *orgSSO.ReadController
+				Update *orgSSO.UpdateController
+				Delete *orgSSO.DeleteController
+			}
+			ServiceAccounts struct {
+				Browse   *orgServiceAccounts.BrowseController
+				Generate *orgServiceAccounts.GenerateController
+				Revoke   *orgServiceAccounts.RevokeController
+				Rotate   *orgServiceAccounts.RotateController
+			}
+			Storage struct {
+				Usage             *orgStorage.UsageController
+				UpdateMemberLimit *orgStorage.UpdateMemberLimitController
+			}
+			Drives struct {
+				List   *orgDrives.ListController
+				Create *orgDrives.CreateController
+				Update *orgDrives.UpdateController
+				Delete *orgDrives.DeleteController
+			}
+		}{
+			Read: orgs.NewReadController(s.Organization),
+			Members: struct {
+				Invite     *orgMembers.InviteController
+				UpdateRole *orgMembers.UpdateRoleController
+				Remove     *orgMembers.RemoveController
+			}{
+				Invite:     orgMembers.NewInviteController(s.Organization, s.Email),
+				UpdateRole: orgMembers.NewUpdateRoleController(s.Organization),
+				Remove:     orgMembers.NewRemoveController(s.Organization),
+			},
+			Invitations: struct {
+				List   *orgInvitations.ListController
+				Resend *orgInvitations.ResendController
+				Revoke *orgInvitations.RevokeController
+			}{
+				List:   orgInvitations.NewListController(s.Organization),
+				Resend: orgInvitations.NewResendController(s.Organization, s.Email),
+				Revoke: orgInvitations.NewRevokeController(s.Organization),
+			},
+			Domain: struct {
+				Challenge *orgDomain.ChallengeController
+				Verify    *orgDomain.VerifyController
+				AutoJoin  *orgDomain.AutoJoinController
+			}{
+				Challenge: orgDomain.NewChallengeController(s.Organization),
+				Verify:    orgDomain.NewVerifyController(s.Organization),
+				AutoJoin:  orgDomain.NewAutoJoinController(s.Organization),
+			},
+			JoinRequests: struct {
+				List    *orgJoinRequests.ListController
+				Approve *orgJoinRequests.ApproveController
+				Reject  *orgJoinRequests.RejectController
+			}{
+				List:    orgJoinRequests.NewListController(s.Organization),
+				Approve: orgJoinRequests.NewApproveController(s.Organization),
+				Reject:  orgJoinRequests.NewRejectController(s.Organization),
+			},
+			SSO: struct {
+				Read   *orgSSO.ReadController
+				Update *orgSSO.UpdateController
+				Delete *orgSSO.DeleteController
+			}{
+				Read:   orgSSO.NewReadController(s.SSO),
+				Update: orgSSO.NewUpdateController(s.SSO),
+				Delete: orgSSO.NewDeleteController(s.SSO),
+			},
+			ServiceAccounts: struct {
+				Browse   *orgServiceAccounts.BrowseController
+				Generate *orgServiceAccounts.GenerateController
+				Revoke   *orgServiceAccounts.RevokeController
+				Rotate   *orgServiceAccounts.RotateController
+			}{
+				Browse:   orgServiceAccounts.NewBrowseController(s.ServiceAccount),
+				Generate: orgServiceAccounts.NewGenerateController(s.ServiceAccount),
+				Revoke:   orgServiceAccounts.NewRevokeController(s.ServiceAccount),
+				Rotate:   orgServiceAccounts.NewRotateController(s.ServiceAccount),
+			},
+			Storage: struct {
+				Usage             *orgStorage.UsageController
+				UpdateMemberLimit *orgStorage.UpdateMemberLimitController
+			}{
+				Usage:             orgStorage.NewUsageController(s.Quota, s.TeamDrive),
+				UpdateMemberLimit: orgStorage.NewUpdateMemberLimitController(s.Quota),
+			},
+			Drives: struct {
+				List   *orgDrives.ListController
+				Create *orgDrives.CreateController
+				Update *orgDrives.UpdateController
+				Delete *orgDrives.DeleteController
+			}{
+				List:   orgDrives.NewListController(s.TeamDrive),
+				Create: orgDrives.NewCreateController(s.TeamDrive),
+				Update: orgDrives.NewUpdateController(s.TeamDrive),
+				Delete: orgDrives.NewDeleteController(s.TeamDrive),
+			},
+		},
+		Invitations: struct {
+			Accept *invitations.AcceptController
+		}{
+			Accept: invitations.NewAcceptController(s.Organization, s.User, s.Token),
 		},
 		Clients: struct {
 			Peers struct {
@@ -343,7 +794,7 @@ func initControllers(s Services) Controllers {
 				Create: directories.NewCreateController(s.FS, s.IPFS),
 				Read:   directories.NewReadController(s.FS, s.IPFS, s.User),
 				Update: directories.NewUpdateController(s.FS, s.IPFS),
-				Delete: directories.NewDeleteController(s.FS, s.IPFS, s.User),
+				Delete: directories.NewDeleteController(s.FS, s.IPFS, s.Quota),
 			},
 			Files: struct {
 				Upload       *files.UploadController
@@ -354,13 +805,13 @@ func initControllers(s Services) Controllers {
 				GenerateLink *files.GenerateLinkController
 				Delete       *files.DeleteController
 			}{
-				Upload:       files.NewUploadController(s.FS, s.IPFS, s.User),
-				Download:     files.NewDownloadController(s.FS, s.IPFS),
+				Upload:       files.NewUploadController(s.FS, s.IPFS, s.Quota, s.Subscription, s.Usage),
+				Download:     files.NewDownloadController(s.FS, s.IPFS, s.Usage),
 				Read:         files.NewReadController(s.FS, s.IPFS, s.User),
 				Update:       files.NewUpdateController(s.FS, s.IPFS),
 				Share:        files.NewShareController(s.FS, s.User, s.Email),
 				GenerateLink: files.NewGenerateLinkController(s.FS),
-				Delete:       files.NewDeleteController(s.FS, s.IPFS, s.User),
+				Delete:       files.NewDeleteController(s.FS, s.IPFS, s.Quota),
 			},
 		},
 		Admin: struct {
@@ -368,60 +819,112 @@ func initControllers(s Services) Controllers {
 				Limits struct {
 					Update *adminUserLimits.UpdateController
 				}
-				List   *adminUsers.ListController
-				Create *adminUsers.CreateController
-				Read   *adminUsers.ReadController
-				Update *adminUsers.UpdateController
-				Search *adminUsers.SearchController
+				Subscription struct {
+					Update *adminUserSubscription.UpdateController
+				}
+				List         *adminUsers.ListController
+				Create       *adminUsers.CreateController
+				Read         *adminUsers.ReadController
+				Update       *adminUsers.UpdateController
+				Search       *adminUsers.SearchController
+				Unlock       *adminUsers.UnlockController
+				LoginHistory *adminUsers.LoginHistoryController
+				Usage        *adminUsers.UsageController
 			}
 			Organizations struct {
 				List    *organizations.ListController
 				Create  *organizations.CreateController
 				Read    *organizations.ReadController
 				Update  *organizations.UpdateController
+				Usage   *organizations.UsageController
 				Members struct {
 					Add        *members.AddController
 					UpdateRole *members.UpdateRoleController
 					Remove     *members.RemoveController
 				}
+				Limits struct {
+					Update *adminOrgLimits.UpdateController
+				}
+				Subscription struct {
+					Update *adminOrgSubscription.UpdateController
+				}
+			}
+			Policies struct {
+				List     *policies.ListController
+				Create   *policies.CreateController
+				Read     *policies.ReadController
+				Update   *policies.UpdateController
+				Simulate *policies.SimulateController
+			}
+			Plans struct {
+				List   *plans.ListController
+				Create *plans.CreateController
+				Read   *plans.ReadController
+				Update *plans.UpdateController
+				Delete *plans.DeleteController
+			}
+			Usage struct {
+				Export *adminUsage.ExportController
 			}
 		}{
 			Users: struct {
 				Limits struct {
 					Update *adminUserLimits.UpdateController
 				}
-				List   *adminUsers.ListController
-				Create *adminUsers.CreateController
-				Read   *adminUsers.ReadController
-				Update *adminUsers.UpdateController
-				Search *adminUsers.SearchController
+				Subscription struct {
+					Update *adminUserSubscription.UpdateController
+				}
+				List         *adminUsers.ListController
+				Create       *adminUsers.CreateController
+				Read         *adminUsers.ReadController
+				Update       *adminUsers.UpdateController
+				Search       *adminUsers.SearchController
+				Unlock       *adminUsers.UnlockController
+				LoginHistory *adminUsers.LoginHistoryController
+				Usage        *adminUsers.UsageController
 			}{
 				Limits: struct {
 					Update *adminUserLimits.UpdateController
 				}{
 					Update: adminUserLimits.NewUpdateController(s.User),
 				},
-				List:   adminUsers.NewListController(s.User),
-				Create: adminUsers.NewCreateController(s.User, s.Token, s.Email),
-				Read:   adminUsers.NewReadController(s.User, s.Organization),
-				Update: adminUsers.NewUpdateController(s.User),
-				Search: adminUsers.NewSearchController(s.User),
+				Subscription: struct {
+					Update *adminUserSubscription.UpdateController
+				}{
+					Update: adminUserSubscription.NewUpdateController(s.Subscription),
+				},
+				List:         adminUsers.NewListController(s.User),
+				Create:       adminUsers.NewCreateController(s.User, s.Token, s.Email, s.Organization),
+				Read:         adminUsers.NewReadController(s.User, s.Organization),
+				Update:       adminUsers.NewUpdateController(s.User),
+				Search:       adminUsers.NewSearchController(s.User),
+				Unlock:       adminUsers.NewUnlockController(s.Lockout),
+				LoginHistory: adminUsers.NewLoginHistoryController(s.LoginEvent),
+				Usage:        adminUsers.NewUsageController(s.Usage),
 			},
 			Organizations: struct {
 				List    *organizations.ListController
 				Create  *organizations.CreateController
 				Read    *organizations.ReadController
 				Update  *organizations.UpdateController
+				Usage   *organizations.UsageController
 				Members struct {
 					Add        *members.AddController
 					UpdateRole *members.UpdateRoleController
 					Remove     *members.RemoveController
 				}
+				Limits struct {
+					Update *adminOrgLimits.UpdateController
+				}
+				Subscription struct {
+					Update *adminOrgSubscription.UpdateController
+				}
 			}{
 				List:   organizations.NewListController(s.Organization),
 				Create: organizations.NewCreateController(s.Organization),
 				Read:   organizations.NewReadController(s.Organization),
 				Update: organizations.NewUpdateController(s.Organization),
+				Usage:  organizations.NewUsageController(s.Usage),
 				Members: struct {
 					Add        *members.AddController
 					UpdateRole *members.UpdateRoleController
@@ -431,6 +934,47 @@ func initControllers(s Services) Controllers {
 					UpdateRole: members.NewUpdateRoleController(s.Organization),
 					Remove:     members.NewRemoveController(s.Organization),
 				},
+				Limits: struct {
+					Update *adminOrgLimits.UpdateController
+				}{
+					Update: adminOrgLimits.NewUpdateController(s.Quota),
+				},
+				Subscription: struct {
+					Update *adminOrgSubscription.UpdateController
+				}{
+					Update: adminOrgSubscription.NewUpdateController(s.Subscription),
+				},
+			},
+			Policies: struct {
+				List     *policies.ListController
+				Create   *policies.CreateController
+				Read     *policies.ReadController
+				Update   *policies.UpdateController
+				Simulate *policies.SimulateController
+			}{
+				List:     policies.NewListController(s.Policy),
+				Create:   policies.NewCreateController(s.Policy),
+				Read:     policies.NewReadController(s.Policy),
+				Update:   policies.NewUpdateController(s.Policy),
+				Simulate: policies.NewSimulateController(s.Policy),
+			},
+			Plans: struct {
+				List   *plans.ListController
+				Create *plans.CreateController
+				Read   *plans.ReadController
+				Update *plans.UpdateController
+				Delete *plans.DeleteController
+			}{
+				List:   plans.NewListController(s.Subscription),
+				Create: plans.NewCreateController(s.Subscription),
+				Read:   plans.NewReadController(s.Subscription),
+				Update: plans.NewUpdateController(s.Subscription),
+				Delete: plans.NewDeleteController(s.Subscription),
+			},
+			Usage: struct {
+				Export *adminUsage.ExportController
+			}{
+				Export: adminUsage.NewExportController(s.Usage),
 			},
 		},
 		Public: struct {
@@ -438,14 +982,22 @@ func initControllers(s Services) Controllers {
 				Download *publicFiles.DownloadController
 				Read     *publicFiles.ReadController
 			}
+			Exports struct {
+				Download *publicExports.DownloadController
+			}
 		}{
 			Files: struct {
 				Download *publicFiles.DownloadController
 				Read     *publicFiles.ReadController
 			}{
-				Download: publicFiles.NewDownloadController(s.FS, s.IPFS),
+				Download: publicFiles.NewDownloadController(s.FS, s.IPFS, s.Usage),
 				Read:     publicFiles.NewReadController(s.FS, s.IPFS),
 			},
+			Exports: struct {
+				Download *publicExports.DownloadController
+			}{
+				Download: publicExports.NewDownloadController(s.DataExport),
+			},
 		},
 	}
 }
@@ -454,7 +1006,7 @@ func NewContainer() *Container {
 	providers := initProviders()
 	repositories := initRepositories(providers)
 	services := initServices(providers, repositories)
-	middlewares := initMiddleware(providers, repositories)
+	middlewares := initMiddleware(providers, repositories, services)
 	controllers := initControllers(services)
 
 	database.MigrateCollections(providers.Mongo)
@@ -465,6 +1017,8 @@ func NewContainer() *Container {
 		repositories.RolePermission,
 		repositories.Policy,
 		repositories.PolicyPermission,
+		repositories.SubscriptionPlan,
+		repositories.SubscriptionStatus,
 	)
 
 	return &Container{
@@ -11,28 +11,42 @@ import (
 
 // Env holds the environment variables
 type Env struct {
-	AppName               string `envconfig:"APP_NAME" default:"bongaquino"`
-	AppVersion            string `envconfig:"APP_VERSION" default:"1.0.0"`
-	AppKey                string `envconfig:"APP_KEY" required:"true"`
-	Port                  int    `envconfig:"PORT" default:"3000"`
-	Mode                  string `envconfig:"MODE" default:"debug"`
-	MongoHost             string `envconfig:"MONGO_HOST" default:"mongo"`
-	MongoPort             int    `envconfig:"MONGO_PORT" default:"27017"`
-	MongoUser             string `envconfig:"MONGO_USER" default:"bongaquino_user"`
-	MongoPassword         string `envconfig:"MONGO_PASSWORD" default:"bongaquino_password"`
-	MongoDatabase         string `envconfig:"MONGO_DATABASE" default:"bongaquino"`
-	MongoConnectionString string `envconfig:"MONGO_CONNECTION_STRING" default:""`
-	RedisHost             string `envconfig:"REDIS_HOST" default:"redis"`
-	RedisPort             int    `envconfig:"REDIS_PORT" default:"6379"`
-	RedisPassword         string `envconfig:"REDIS_PASSWORD"`
-	RedisPrefix           string `envconfig:"REDIS_PREFIX" required:"true"`
-	JWTSecret             string `envconfig:"JWT_SECRET" required:"true"`
-	JWTTokenExpiration    int    `envconfig:"JWT_TOKEN_EXPIRATION" default:"3600"`
-	JWTRefreshExpiration  int    `envconfig:"JWT_REFRESH_EXPIRATION" default:"86400"`
-	PostmarkAPIKey        string `envconfig:"POSTMARK_API_KEY" required:"true"`
-	PostmarkFrom          string `envconfig:"POSTMARK_FROM" required:"true"`
-	IPFSNodeURL           string `envconfig:"IPFS_NODE_URL" required:"true"`
-	IPFSDownloadURL       string `envconfig:"IPFS_DOWNLOAD_URL" required:"true"`
+	AppName                         string   `envconfig:"APP_NAME" default:"bongaquino"`
+	AppVersion                      string   `envconfig:"APP_VERSION" default:"1.0.0"`
+	AppKey                          string   `envconfig:"APP_KEY" required:"true"`
+	Port                            int      `envconfig:"PORT" default:"3000"`
+	Mode                            string   `envconfig:"MODE" default:"debug"`
+	FrontendURL                     string   `envconfig:"FRONTEND_URL" default:"http://localhost:3001"`
+	AppURL                          string   `envconfig:"APP_URL" default:"http://localhost:3000"`
+	MongoHost                       string   `envconfig:"MONGO_HOST" default:"mongo"`
+	MongoPort                       int      `envconfig:"MONGO_PORT" default:"27017"`
+	MongoUser                       string   `envconfig:"MONGO_USER" default:"bongaquino_user"`
+	MongoPassword                   string   `envconfig:"MONGO_PASSWORD" default:"bongaquino_password"`
+	MongoDatabase                   string   `envconfig:"MONGO_DATABASE" default:"bongaquino"`
+	MongoConnectionString           string   `envconfig:"MONGO_CONNECTION_STRING" default:""`
+	RedisHost                       string   `envconfig:"REDIS_HOST" default:"redis"`
+	RedisPort                       int      `envconfig:"REDIS_PORT" default:"6379"`
+	RedisPassword                   string   `envconfig:"REDIS_PASSWORD"`
+	RedisPrefix                     string   `envconfig:"REDIS_PREFIX" required:"true"`
+	JWTSecret                       string   `envconfig:"JWT_SECRET" required:"true"`
+	JWTTokenExpiration              int      `envconfig:"JWT_TOKEN_EXPIRATION" default:"3600"`
+	JWTRefreshExpiration            int      `envconfig:"JWT_REFRESH_EXPIRATION" default:"86400"`
+	JWTClientExpiration             int      `envconfig:"JWT_CLIENT_EXPIRATION" default:"900"`
+	PostmarkAPIKey                  string   `envconfig:"POSTMARK_API_KEY" required:"true"`
+	PostmarkFrom                    string   `envconfig:"POSTMARK_FROM" required:"true"`
+	IPFSNodeURL                     string   `envconfig:"IPFS_NODE_URL" required:"true"`
+	IPFSDownloadURL                 string   `envconfig:"IPFS_DOWNLOAD_URL" required:"true"`
+	WebAuthnRPID                    string   `envconfig:"WEBAUTHN_RP_ID" default:"localhost"`
+	WebAuthnRPOrigins               []string `envconfig:"WEBAUTHN_RP_ORIGINS" default:"http://localhost:3001"`
+	RateLimitEnabled                bool     `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	TrustedProxies                  []string `envconfig:"TRUSTED_PROXIES"`
+	SSOAllowInsecureIssuers         bool     `envconfig:"SSO_ALLOW_INSECURE_ISSUERS" default:"false"`
+	TrustedDeviceDays               int      `envconfig:"TRUSTED_DEVICE_DAYS" default:"30"`
+	ServiceAccountSecretDays        int      `envconfig:"SERVICE_ACCOUNT_SECRET_DAYS" default:"90"`
+	ServiceAccountExpiryWarningDays int      `envconfig:"SERVICE_ACCOUNT_EXPIRY_WARNING_DAYS" default:"7"`
+	ServiceAccountInactiveDays      int      `envconfig:"SERVICE_ACCOUNT_INACTIVE_DAYS" default:"90"`
+	AccountDeletionGraceDays        int      `envconfig:"ACCOUNT_DELETION_GRACE_DAYS" default:"30"`
+	DataExportDays                  int      `envconfig:"DATA_EXPORT_DAYS" default:"7"`
 }
 
 // LoadEnv loads and validates environment variables
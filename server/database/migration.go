@@ -27,13 +27,40 @@ func MigrateCollections(mongoProvider *provider.MongoProvider) {
 		{"roles", generateIndexes(model.Role{}.GetIndexes(), "unique_role_name")},
 		{"permissions", generateIndexes(model.Permission{}.GetIndexes(), "unique_permission_name")},
 		{"policies", generateIndexes(model.Policy{}.GetIndexes(), "unique_policy_name")},
+		{"subscription_plans", generateIndexes(model.SubscriptionPlan{}.GetIndexes(), "unique_plan_name")},
+		{"subscription_statuses", generateIndexes(model.SubscriptionStatus{}.GetIndexes(), "unique_status_name")},
 		{"policy_permission", []mongoDriver.IndexModel{{Keys: bson.D{{Key: "policy_id", Value: 1}, {Key: "permission_id", Value: 1}}, Options: mongoOptions.Index().SetUnique(true).SetName("unique_policy_permission")}}},
 		{"role_permission", []mongoDriver.IndexModel{{Keys: bson.D{{Key: "role_id", Value: 1}, {Key: "permission_id", Value: 1}}, Options: mongoOptions.Index().SetUnique(true).SetName("unique_role_permission")}}},
 		{"user_role", generateIndexes(model.UserRole{}.GetIndexes(), "unique_user_role")},
 		{"service_accounts", generateIndexes(model.ServiceAccount{}.GetIndexes(), "unique_client_id")},
 		{"organizations", generateIndexes(nil, "")},
 		{"organization_user_role", generateIndexes(nil, "")},
-		{"limits", generateIndexes(model.Limit{}.GetIndexes(), "unique_user_id")},
+		{"organization_invitations", generateIndexes(model.OrganizationInvitation{}.GetIndexes(), "unique_token_hash")},
+		{"organization_join_requests", generateIndexes(nil, "")},
+		{"organization_sso", generateIndexes(model.OrganizationSSO{}.GetIndexes(), "unique_organization_id")},
+		{"mfa_recovery_codes", []mongoDriver.IndexModel{
+			{Keys: model.MFARecoveryCode{}.GetIndexes()[0], Options: mongoOptions.Index().SetName("user_id_used_at")},
+		}},
+		{"webauthn_credentials", generateIndexes(model.WebAuthnCredential{}.GetIndexes(), "unique_credential_id")},
+		{"trusted_devices", generateIndexes(model.TrustedDevice{}.GetIndexes(), "unique_token_hash")},
+		{"personal_access_tokens", generateIndexes(model.PersonalAccessToken{}.GetIndexes(), "unique_token_hash")},
+		{"login_events", []mongoDriver.IndexModel{
+			{Keys: model.LoginEvent{}.GetIndexes()[0], Options: mongoOptions.Index().SetName("user_id_created_at")},
+			{Keys: model.LoginEvent{}.GetIndexes()[1], Options: mongoOptions.Index().SetName("user_id_fingerprint")},
+		}},
+		{"data_exports", []mongoDriver.IndexModel{
+			{Keys: model.DataExport{}.GetIndexes()[0], Options: mongoOptions.Index().SetName("user_id_created_at")},
+			{Keys: model.DataExport{}.GetIndexes()[1], Options: mongoOptions.Index().SetName("status")},
+		}},
+		{"audit_logs", []mongoDriver.IndexModel{
+			{Keys: model.AuditLog{}.GetIndexes()[0], Options: mongoOptions.Index().SetName("user_id_created_at")},
+		}},
+		{"usage_snapshots", generateIndexes(model.UsageSnapshot{}.GetIndexes(), "unique_subject_date")},
+		{"team_drives", generateIndexes(model.TeamDrive{}.GetIndexes(), "unique_organization_drive_name")},
+		{"limits", []mongoDriver.IndexModel{
+			{Keys: model.Limit{}.GetIndexes()[0], Options: mongoOptions.Index().SetUnique(true).SetName("unique_user_id_1")},
+			{Keys: model.Limit{}.GetIndexes()[1], Options: mongoOptions.Index().SetName("organization_id")},
+		}},
 		{"directories", generateIndexes(nil, "")},
 		{"files", generateIndexes(nil, "")},
 		{"file_access", generateIndexes(nil, "")},
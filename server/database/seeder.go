@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"bongaquino/server/app/model"
 	"bongaquino/server/app/repository"
+	"bongaquino/server/config"
 	"bongaquino/server/core/logger"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,6 +18,8 @@ func SeedCollections(
 	rolePermissionRepo *repository.RolePermissionRepository,
 	policyRepo *repository.PolicyRepository,
 	policyPermissionRepo *repository.PolicyPermissionRepository,
+	subscriptionPlanRepo *repository.SubscriptionPlanRepository,
+	subscriptionStatusRepo *repository.SubscriptionStatusRepository,
 ) {
 	ctx := context.Background()
 
@@ -33,6 +36,8 @@ func SeedCollections(
 		{"policy_permissions", func(ctx context.Context) error {
 			return seedPolicyPermissions(ctx, policyRepo, permissionRepo, policyPermissionRepo)
 		}},
+		{"subscription_statuses", func(ctx context.Context) error { return seedSubscriptionStatuses(ctx, subscriptionStatusRepo) }},
+		{"subscription_plans", func(ctx context.Context) error { return seedSubscriptionPlans(ctx, subscriptionPlanRepo) }},
 	}
 
 	for _, seeder := range seeders {
@@ -67,6 +72,16 @@ func seedPermissions(ctx context.Context, permissionRepo *repository.PermissionR
 		{Name: "file:read"},
 		{Name: "file:edit"},
 		{Name: "file:delete"},
+		{Name: "policy:browse"},
+		{Name: "policy:read"},
+		{Name: "policy:add"},
+		{Name: "policy:edit"},
+		{Name: "plan:browse"},
+		{Name: "plan:read"},
+		{Name: "plan:add"},
+		{Name: "plan:edit"},
+		{Name: "plan:delete"},
+		{Name: "usage:export"},
 	}
 
 	for _, perm := range permissions {
@@ -124,6 +139,9 @@ func seedRolePermissions(
 			"organization:browse", "organization:add", "organization:read", "organization:edit", "organization:delete",
 			"directory:browse", "directory:add", "directory:read", "directory:edit", "directory:delete",
 			"file:upload", "file:download", "file:read", "file:edit", "file:delete",
+			"policy:browse", "policy:add", "policy:read", "policy:edit",
+			"plan:browse", "plan:add", "plan:read", "plan:edit", "plan:delete",
+			"usage:export",
 		},
 		"system_user": {
 			"directory:browse", "directory:add", "directory:read", "directory:edit", "directory:delete",
@@ -196,6 +214,7 @@ func seedPolicies(ctx context.Context, policyRepo *repository.PolicyRepository)
 	policies := []model.Policy{
 		{Name: "default_organization_policy"},
 		{Name: "default_service_account_policy"},
+		{Name: "read_only_service_account_policy"},
 	}
 
 	for _, policy := range policies {
@@ -255,6 +274,95 @@ func seedPolicyPermissions(
 		logger.Log.Warn("default_service_account_policy not found, skipping")
 	}
 
+	// read_only_service_account_policy → browse, read and download only
+	readOnlyPolicy, err := policyRepo.ReadByName(ctx, "read_only_service_account_policy")
+	if err != nil {
+		return err
+	}
+	if readOnlyPolicy != nil {
+		readOnlyPerms := []string{
+			"directory:browse", "directory:read",
+			"file:download", "file:read",
+		}
+		if err := assignPolicyPermissions(ctx, readOnlyPolicy.ID.Hex(), readOnlyPerms, permissionRepo, policyPermissionRepo); err != nil {
+			return err
+		}
+	} else {
+		logger.Log.Warn("read_only_service_account_policy not found, skipping")
+	}
+
+	return nil
+}
+
+// seedSubscriptionStatuses inserts the stages of the subscription lifecycle
+func seedSubscriptionStatuses(ctx context.Context, subscriptionStatusRepo *repository.SubscriptionStatusRepository) error {
+	subscriptionConfig := config.LoadSubscriptionConfig()
+
+	for _, name := range subscriptionConfig.Statuses {
+		existing, err := subscriptionStatusRepo.ReadByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := subscriptionStatusRepo.Create(ctx, &model.SubscriptionStatus{Name: name}); err != nil {
+				return err
+			}
+		} else {
+			logger.Log.Info(fmt.Sprintf("skipping subscription status: %s (already exists)", name))
+		}
+	}
+	return nil
+}
+
+// seedSubscriptionPlans inserts the initial plan catalog, plans edited by an admin are left alone
+func seedSubscriptionPlans(ctx context.Context, subscriptionPlanRepo *repository.SubscriptionPlanRepository) error {
+	const gigabyte = int64(1024 * 1024 * 1024)
+	const megabyte = int64(1024 * 1024)
+
+	plans := []model.SubscriptionPlan{
+		{
+			Name:                 "free",
+			Description:          "Default plan of every account",
+			BytesLimit:           5 * gigabyte,
+			MaxFileSize:          2 * gigabyte,
+			MaxEncryptedFileSize: 20 * megabyte,
+			MaxServiceAccounts:   2,
+		},
+		{
+			Name:                 "pro",
+			BytesLimit:           100 * gigabyte,
+			MaxFileSize:          10 * gigabyte,
+			MaxEncryptedFileSize: 100 * megabyte,
+			MaxServiceAccounts:   10,
+			RateLimits: map[string]model.PlanRateLimit{
+				"upload": {Limit: 120, WindowSeconds: 60},
+				"api":    {Limit: 1200, WindowSeconds: 60},
+			},
+		},
+		{
+			Name:                 "enterprise",
+			BytesLimit:           1024 * gigabyte,
+			MaxEncryptedFileSize: 500 * megabyte,
+			RateLimits: map[string]model.PlanRateLimit{
+				"upload": {Limit: 600, WindowSeconds: 60},
+				"api":    {Limit: 6000, WindowSeconds: 60},
+			},
+		},
+	}
+
+	for _, plan := range plans {
+		existing, err := subscriptionPlanRepo.ReadByName(ctx, plan.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := subscriptionPlanRepo.Create(ctx, &plan); err != nil {
+				return err
+			}
+		} else {
+			logger.Log.Info(fmt.Sprintf("skipping subscription plan: %s (already exists)", plan.Name))
+		}
+	}
 	return nil
 }
 
@@ -25,12 +25,20 @@ func InitializeKernel() {
 	// Initialize the Gin engine
 	engine := gin.Default()
 
+	// Only honor X-Forwarded-For from our own proxies, otherwise the client IP is the connecting address
+	if err := engine.SetTrustedProxies(env.TrustedProxies); err != nil {
+		logger.Log.Fatal("invalid trusted proxies", logger.Error(err))
+	}
+
 	// Set MaxMultipartMemory to 2GB for large file uploads
 	engine.MaxMultipartMemory = 2 << 30 // 2GB
 
 	// Initialize IoC container
 	container := ioc.NewContainer()
 
+	// Start background jobs
+	StartScheduler(container)
+
 	// Setup CORS
 	SetupCORS(engine)
 
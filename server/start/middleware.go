@@ -9,4 +9,6 @@ import (
 // RegisterMiddleware sets up the middleware for the Gin engine
 func RegisterMiddleware(engine *gin.Engine, container *ioc.Container) {
 	// Global middleware
+	engine.Use(container.Middleware.ClientInfo.Handle)
+	engine.Use(container.Middleware.RateLimit.Handle("global"))
 }
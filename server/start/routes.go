@@ -25,22 +25,46 @@ func RegisterRoutes(engine *gin.Engine, container *ioc.Container) {
 	// User Routes
 	userGroup := engine.Group("/users")
 	{
-		userGroup.POST("/register", container.Controllers.Users.Register.Handle)
-		userGroup.POST("/forgot-password", container.Controllers.Users.ForgotPassword.Handle)
-		userGroup.POST("/reset-password", container.Controllers.Users.ResetPassword.Handle)
+		rateLimit := container.Middleware.RateLimit
+		userGroup.POST("/register", rateLimit.Handle("recovery"), container.Controllers.Users.Register.Handle)
+		userGroup.POST("/forgot-password", rateLimit.Handle("recovery"), container.Controllers.Users.ForgotPassword.Handle)
+		userGroup.POST("/reset-password", rateLimit.Handle("recovery"), container.Controllers.Users.ResetPassword.Handle)
+		userGroup.POST("/unlock-account", rateLimit.Handle("recovery"), container.Controllers.Users.UnlockAccount.Handle)
+		userGroup.POST("/cancel-email-change", rateLimit.Handle("recovery"), container.Controllers.Users.CancelEmailChange.Handle)
 		userGroup.Use(container.Middleware.Authn.Handle).POST("/verify-account", container.Controllers.Users.VerifyAccount.Handle)
 		userGroup.Use(container.Middleware.Authn.Handle).POST("/resend-verification-code", container.Controllers.Users.ResendVerificationCode.Handle)
 	}
 
 	// Token Routes
 	tokenGroup := engine.Group("/tokens")
+	tokenGroup.Use(container.Middleware.RateLimit.Handle("auth"))
 	{
 		tokenGroup.POST("/request", container.Controllers.Tokens.Request.Handle)
 		tokenGroup.POST("/verify-otp", container.Controllers.Tokens.Verify.Handle)
+		tokenGroup.POST("/passkey/options", container.Controllers.Tokens.PasskeyOptions.Handle)
+		tokenGroup.POST("/passkey/begin", container.Controllers.Tokens.BeginPasskey.Handle)
+		tokenGroup.POST("/passkey/finish", container.Controllers.Tokens.FinishPasskey.Handle)
+		tokenGroup.POST("/magic-link", container.Controllers.Tokens.MagicLink.Handle)
+		tokenGroup.POST("/magic-link/verify", container.Controllers.Tokens.VerifyMagicLink.Handle)
 		tokenGroup.POST("/refresh", container.Controllers.Tokens.Refresh.Handle)
 		tokenGroup.DELETE("/revoke", container.Controllers.Tokens.Revoke.Handle)
 	}
 
+	// OAuth Routes
+	oauthGroup := engine.Group("/oauth")
+	oauthGroup.Use(container.Middleware.RateLimit.Handle("auth"))
+	{
+		oauthGroup.POST("/token", container.Controllers.OAuth.Token.Handle)
+	}
+
+	// SSO Routes
+	ssoGroup := engine.Group("/sso")
+	ssoGroup.Use(container.Middleware.RateLimit.Handle("auth"))
+	{
+		ssoGroup.POST("/begin", container.Controllers.SSO.Begin.Handle)
+		ssoGroup.POST("/callback", container.Controllers.SSO.Callback.Handle)
+	}
+
 	// Settings Routes
 	settingsGroup := engine.Group("/settings")
 	settingsGroup.Use(container.Middleware.Authn.Handle, container.Middleware.Verified.Handle)
@@ -51,12 +75,29 @@ func RegisterRoutes(engine *gin.Engine, container *ioc.Container) {
 		// Change Password Route
 		settingsGroup.POST("/change-password", container.Controllers.Settings.ChangePassword.Handle)
 
+		// Change Email Routes
+		settingsGroup.POST("/change-email", container.Middleware.RateLimit.Handle("recovery"), container.Controllers.Settings.ChangeEmail.Handle)
+		settingsGroup.POST("/confirm-email-change", container.Controllers.Settings.ConfirmEmailChange.Handle)
+
+		// Personal Access Token Routes
+		settingsGroup.GET("/access-tokens/list", container.Controllers.Settings.AccessTokens.List.Handle)
+		settingsGroup.POST("/access-tokens/create", container.Controllers.Settings.AccessTokens.Create.Handle)
+		settingsGroup.DELETE("/access-tokens/:tokenID/revoke", container.Controllers.Settings.AccessTokens.Revoke.Handle)
+
 		// MFA Routes
 		mfaGroup := settingsGroup.Group("/mfa")
 		{
 			mfaGroup.POST("/generate-otp", container.Controllers.Settings.MFA.Generate.Handle)
 			mfaGroup.POST("/enable", container.Controllers.Settings.MFA.Enable.Handle)
 			mfaGroup.POST("/disable", container.Controllers.Settings.MFA.Disable.Handle)
+			mfaGroup.GET("/recovery-codes", container.Controllers.Settings.MFA.RecoveryCodes.Read.Handle)
+			mfaGroup.POST("/recovery-codes/regenerate", container.Controllers.Settings.MFA.RecoveryCodes.Regenerate.Handle)
+			mfaGroup.GET("/passkeys/list", container.Controllers.Settings.MFA.Passkeys.List.Handle)
+			mfaGroup.POST("/passkeys/register/begin", container.Controllers.Settings.MFA.Passkeys.BeginRegistration.Handle)
+			mfaGroup.POST("/passkeys/register/finish", container.Controllers.Settings.MFA.Passkeys.FinishRegistration.Handle)
+			mfaGroup.DELETE("/passkeys/:passkeyID/delete", container.Controllers.Settings.MFA.Passkeys.Delete.Handle)
+			mfaGroup.GET("/devices/list", container.Controllers.Settings.MFA.Devices.List.Handle)
+			mfaGroup.DELETE("/devices/:deviceID/delete", container.Controllers.Settings.MFA.Devices.Revoke.Handle)
 		}
 	}
 
@@ -65,6 +106,11 @@ func RegisterRoutes(engine *gin.Engine, container *ioc.Container) {
 	profileGroup.Use(container.Middleware.Authn.Handle)
 	{
 		profileGroup.GET("/me", container.Controllers.Profile.Me.Handle)
+		profileGroup.GET("/login-history", container.Controllers.Profile.LoginHistory.Handle)
+		profileGroup.POST("/exports/create", container.Controllers.Profile.CreateExport.Handle)
+		profileGroup.GET("/exports/list", container.Controllers.Profile.ListExports.Handle)
+		profileGroup.POST("/delete", container.Middleware.RateLimit.Handle("recovery"), container.Controllers.Profile.DeleteAccount.Handle)
+		profileGroup.POST("/delete/cancel", container.Controllers.Profile.CancelDeletion.Handle)
 	}
 
 	// Network Routes
@@ -75,25 +121,29 @@ func RegisterRoutes(engine *gin.Engine, container *ioc.Container) {
 
 	// Directories Routes
 	directoriesGroup := engine.Group("/directories")
-	directoriesGroup.Use(container.Middleware.Authn.Handle, container.Middleware.Verified.Handle)
+	directoriesGroup.Use(container.Middleware.Authn.HandleWithPAT, container.Middleware.Verified.Handle)
 	{
-		directoriesGroup.POST("/create", container.Controllers.Clients.Directories.Create.Handle)
-		directoriesGroup.GET("/:directoryID/read", container.Controllers.Clients.Directories.Read.Handle)
-		directoriesGroup.PUT("/:directoryID/update", container.Controllers.Clients.Directories.Update.Handle)
-		directoriesGroup.DELETE("/:directoryID/delete", container.Controllers.Clients.Directories.Delete.Handle)
+		authz := container.Middleware.Authz
+		drive := container.Middleware.Drive
+		directoriesGroup.POST("/create", drive.Handle("write"), authz.RequirePermission("directory:add"), container.Controllers.Clients.Directories.Create.Handle)
+		directoriesGroup.GET("/:directoryID/read", drive.Handle("read"), authz.RequirePermission("directory:read"), container.Controllers.Clients.Directories.Read.Handle)
+		directoriesGroup.PUT("/:directoryID/update", drive.Handle("write"), authz.RequirePermission("directory:edit"), container.Controllers.Clients.Directories.Update.Handle)
+		directoriesGroup.DELETE("/:directoryID/delete", drive.Handle("manage"), authz.RequirePermission("directory:delete"), container.Controllers.Clients.Directories.Delete.Handle)
 	}
 
 	// Files Routes
 	filesGroup := engine.Group("/files")
-	filesGroup.Use(container.Middleware.Authn.Handle, container.Middleware.Verified.Handle)
+	filesGroup.Use(container.Middleware.Authn.HandleWithPAT, container.Middleware.Verified.Handle, container.Middleware.Subscription.Handle)
 	{
-		filesGroup.POST("/upload", container.Controllers.Clients.Files.Upload.Handle)
-		filesGroup.GET("/:fileID/download", container.Controllers.Clients.Files.Download.Handle)
-		filesGroup.GET("/:fileID/read", container.Controllers.Clients.Files.Read.Handle)
-		filesGroup.PUT("/:fileID/update", container.Controllers.Clients.Files.Update.Handle)
-		filesGroup.POST("/:fileID/share", container.Controllers.Clients.Files.Share.Handle)
-		filesGroup.POST("/:fileID/generate-link", container.Controllers.Clients.Files.GenerateLink.Handle)
-		filesGroup.DELETE("/:fileID/delete", container.Controllers.Clients.Files.Delete.Handle)
+		authz := container.Middleware.Authz
+		drive := container.Middleware.Drive
+		filesGroup.POST("/upload", container.Middleware.RateLimit.Handle("upload"), drive.Handle("write"), authz.RequirePermission("file:upload"), container.Controllers.Clients.Files.Upload.Handle)
+		filesGroup.GET("/:fileID/download", drive.Handle("read"), authz.RequirePermission("file:download"), container.Controllers.Clients.Files.Download.Handle)
+		filesGroup.GET("/:fileID/read", drive.Handle("read"), authz.RequirePermission("file:read"), container.Controllers.Clients.Files.Read.Handle)
+		filesGroup.PUT("/:fileID/update", drive.Handle("write"), authz.RequirePermission("file:edit"), container.Controllers.Clients.Files.Update.Handle)
+		filesGroup.POST("/:fileID/share", drive.Handle("manage"), authz.RequirePermission("file:edit"), container.Controllers.Clients.Files.Share.Handle)
+		filesGroup.POST("/:fileID/generate-link", drive.Handle("manage"), authz.RequirePermission("file:edit"), container.Controllers.Clients.Files.GenerateLink.Handle)
+		filesGroup.DELETE("/:fileID/delete", drive.Handle("manage"), authz.RequirePermission("file:delete"), container.Controllers.Clients.Files.Delete.Handle)
 	}
 
 	// Service Account Routes
@@ -103,56 +153,132 @@ func RegisterRoutes(engine *gin.Engine, container *ioc.Container) {
 		serviceAccountGroup.GET("/browse", container.Controllers.ServiceAccounts.Browse.Handle)
 		serviceAccountGroup.POST("/generate", container.Controllers.ServiceAccounts.Generate.Handle)
 		serviceAccountGroup.DELETE("/revoke", container.Controllers.ServiceAccounts.Revoke.Handle)
+		serviceAccountGroup.POST("/rotate", container.Controllers.ServiceAccounts.Rotate.Handle)
+	}
+
+	// Organization Routes
+	organizationGroup := engine.Group("/organizations/:orgID")
+	organizationGroup.Use(container.Middleware.Authn.Handle, container.Middleware.Verified.Handle)
+	{
+		org := container.Middleware.Organization
+		members := []string{"organization_admin", "organization_user", "organization_viewer"}
+		admins := []string{"organization_admin"}
+		organizationGroup.GET("/read", org.Handle(members), container.Controllers.Organizations.Read.Handle)
+		// Member Routes
+		organizationGroup.POST("/members/invite", org.Handle(admins), container.Controllers.Organizations.Members.Invite.Handle)
+		organizationGroup.PUT("/members/:userID/update-role", org.Handle(admins), container.Controllers.Organizations.Members.UpdateRole.Handle)
+		organizationGroup.DELETE("/members/:userID/remove", org.Handle(admins), container.Controllers.Organizations.Members.Remove.Handle)
+		// Invitation Routes
+		organizationGroup.GET("/invitations/list", org.Handle(admins), container.Controllers.Organizations.Invitations.List.Handle)
+		organizationGroup.POST("/invitations/:invitationID/resend", org.Handle(admins), container.Controllers.Organizations.Invitations.Resend.Handle)
+		organizationGroup.DELETE("/invitations/:invitationID/revoke", org.Handle(admins), container.Controllers.Organizations.Invitations.Revoke.Handle)
+		// Domain Routes
+		organizationGroup.POST("/domain/challenge", org.Handle(admins), container.Controllers.Organizations.Domain.Challenge.Handle)
+		organizationGroup.POST("/domain/verify", org.Handle(admins), container.Controllers.Organizations.Domain.Verify.Handle)
+		organizationGroup.PUT("/domain/auto-join", org.Handle(admins), container.Controllers.Organizations.Domain.AutoJoin.Handle)
+		// Join Request Routes
+		organizationGroup.GET("/join-requests/list", org.Handle(admins), container.Controllers.Organizations.JoinRequests.List.Handle)
+		organizationGroup.POST("/join-requests/:requestID/approve", org.Handle(admins), container.Controllers.Organizations.JoinRequests.Approve.Handle)
+		organizationGroup.POST("/join-requests/:requestID/reject", org.Handle(admins), container.Controllers.Organizations.JoinRequests.Reject.Handle)
+		// SSO Routes
+		organizationGroup.GET("/sso/read", org.Handle(admins), container.Controllers.Organizations.SSO.Read.Handle)
+		organizationGroup.PUT("/sso/update", org.Handle(admins), container.Controllers.Organizations.SSO.Update.Handle)
+		organizationGroup.DELETE("/sso/delete", org.Handle(admins), container.Controllers.Organizations.SSO.Delete.Handle)
+		// Service Account Routes
+		organizationGroup.GET("/service-accounts/browse", org.Handle(admins), container.Controllers.Organizations.ServiceAccounts.Browse.Handle)
+		organizationGroup.POST("/service-accounts/generate", org.Handle(admins), container.Controllers.Organizations.ServiceAccounts.Generate.Handle)
+		organizationGroup.DELETE("/service-accounts/revoke", org.Handle(admins), container.Controllers.Organizations.ServiceAccounts.Revoke.Handle)
+		organizationGroup.POST("/service-accounts/rotate", org.Handle(admins), container.Controllers.Organizations.ServiceAccounts.Rotate.Handle)
+		// Storage Routes
+		organizationGroup.GET("/storage/usage", org.Handle(admins), container.Controllers.Organizations.Storage.Usage.Handle)
+		organizationGroup.PUT("/storage/members/:userID/limit", org.Handle(admins), container.Controllers.Organizations.Storage.UpdateMemberLimit.Handle)
+		// Team Drive Routes
+		organizationGroup.GET("/drives/list", org.Handle(members), container.Controllers.Organizations.Drives.List.Handle)
+		organizationGroup.POST("/drives/create", org.Handle(admins), container.Controllers.Organizations.Drives.Create.Handle)
+		organizationGroup.PUT("/drives/:driveID/update", org.Handle(admins), container.Controllers.Organizations.Drives.Update.Handle)
+		organizationGroup.DELETE("/drives/:driveID/delete", org.Handle(admins), container.Controllers.Organizations.Drives.Delete.Handle)
+	}
+
+	// Invitation Routes
+	invitationGroup := engine.Group("/invitations")
+	{
+		invitationGroup.POST("/accept", container.Controllers.Invitations.Accept.Handle)
 	}
 
 	// Clients v1 Routes
 	clientsGroup := engine.Group("/clients/v1")
-	clientsGroup.Use(container.Middleware.API.Handle)
+	clientsGroup.Use(container.Middleware.API.Handle, container.Middleware.Subscription.Handle, container.Middleware.RateLimit.Handle("api"), container.Middleware.Usage.Handle)
 	{
+		policy := container.Middleware.Policy
 		// Peer Routes
 		clientsGroup.GET("/peers", container.Controllers.Clients.Peers.Fetch.Handle)
 		// Directory Routes
-		clientsGroup.POST("/directories", container.Controllers.Clients.Directories.Create.Handle)
-		clientsGroup.GET("/directories/:directoryID", container.Controllers.Clients.Directories.Read.Handle)
-		clientsGroup.PUT("/directories/:directoryID", container.Controllers.Clients.Directories.Update.Handle)
-		clientsGroup.DELETE("/directories/:directoryID", container.Controllers.Clients.Directories.Delete.Handle)
+		clientsGroup.POST("/directories", policy.Handle("directory:add"), container.Controllers.Clients.Directories.Create.Handle)
+		clientsGroup.GET("/directories/:directoryID", policy.Handle("directory:read"), container.Controllers.Clients.Directories.Read.Handle)
+		clientsGroup.PUT("/directories/:directoryID", policy.Handle("directory:edit"), container.Controllers.Clients.Directories.Update.Handle)
+		clientsGroup.DELETE("/directories/:directoryID", policy.Handle("directory:delete"), container.Controllers.Clients.Directories.Delete.Handle)
 		// File Routes
-		clientsGroup.POST("/files", container.Controllers.Clients.Files.Upload.Handle)
-		clientsGroup.GET("/files/:fileID/download", container.Controllers.Clients.Files.Download.Handle)
-		clientsGroup.GET("/files/:fileID", container.Controllers.Clients.Files.Read.Handle)
-		clientsGroup.PUT("/files/:fileID", container.Controllers.Clients.Files.Update.Handle)
-		clientsGroup.POST("/files/:fileID/share", container.Controllers.Clients.Files.Share.Handle)
-		clientsGroup.POST("/files/:fileID/generate-link", container.Controllers.Clients.Files.GenerateLink.Handle)
-		clientsGroup.DELETE("/files/:fileID", container.Controllers.Clients.Files.Delete.Handle)
+		clientsGroup.POST("/files", container.Middleware.RateLimit.Handle("upload"), policy.Handle("file:upload"), container.Controllers.Clients.Files.Upload.Handle)
+		clientsGroup.GET("/files/:fileID/download", policy.Handle("file:download"), container.Controllers.Clients.Files.Download.Handle)
+		clientsGroup.GET("/files/:fileID", policy.Handle("file:read"), container.Controllers.Clients.Files.Read.Handle)
+		clientsGroup.PUT("/files/:fileID", policy.Handle("file:edit"), container.Controllers.Clients.Files.Update.Handle)
+		clientsGroup.POST("/files/:fileID/share", policy.Handle("file:edit"), container.Controllers.Clients.Files.Share.Handle)
+		clientsGroup.POST("/files/:fileID/generate-link", policy.Handle("file:edit"), container.Controllers.Clients.Files.GenerateLink.Handle)
+		clientsGroup.DELETE("/files/:fileID", policy.Handle("file:delete"), container.Controllers.Clients.Files.Delete.Handle)
 	}
 
 	// Admin Routes
 	adminGroup := engine.Group("/admin")
 	adminGroup.Use(container.Middleware.Authn.Handle, container.Middleware.Authz.Handle([]string{"system_admin"}))
 	{
+		authz := container.Middleware.Authz
 		// User Management Routes
-		adminGroup.GET("users/list", container.Controllers.Admin.Users.List.Handle)
-		adminGroup.POST("users/create", container.Controllers.Admin.Users.Create.Handle)
-		adminGroup.GET("users/:userID/read", container.Controllers.Admin.Users.Read.Handle)
-		adminGroup.PUT("users/:userID/update", container.Controllers.Admin.Users.Update.Handle)
-		adminGroup.GET("users/search", container.Controllers.Admin.Users.Search.Handle)
+		adminGroup.GET("users/list", authz.RequirePermission("user:browse"), container.Controllers.Admin.Users.List.Handle)
+		adminGroup.POST("users/create", authz.RequirePermission("user:add"), container.Controllers.Admin.Users.Create.Handle)
+		adminGroup.GET("users/:userID/read", authz.RequirePermission("user:read"), container.Controllers.Admin.Users.Read.Handle)
+		adminGroup.PUT("users/:userID/update", authz.RequirePermission("user:edit"), container.Controllers.Admin.Users.Update.Handle)
+		adminGroup.GET("users/search", authz.RequirePermission("user:browse"), container.Controllers.Admin.Users.Search.Handle)
+		adminGroup.POST("users/:userID/unlock", authz.RequirePermission("user:edit"), container.Controllers.Admin.Users.Unlock.Handle)
+		adminGroup.GET("users/:userID/login-history", authz.RequirePermission("user:read"), container.Controllers.Admin.Users.LoginHistory.Handle)
+		adminGroup.GET("users/:userID/usage", authz.RequirePermission("user:read"), container.Controllers.Admin.Users.Usage.Handle)
 		// User Limits Management Routes
-		adminGroup.PUT("users/:userID/limits/update", container.Controllers.Admin.Users.Limits.Update.Handle)
+		adminGroup.PUT("users/:userID/limits/update", authz.RequirePermission("user:edit"), container.Controllers.Admin.Users.Limits.Update.Handle)
+		// User Subscription Management Routes
+		adminGroup.PUT("users/:userID/subscription/update", authz.RequirePermission("user:edit"), container.Controllers.Admin.Users.Subscription.Update.Handle)
 		// Organization Management Routes
-		adminGroup.GET("organizations/list", container.Controllers.Admin.Organizations.List.Handle)
-		adminGroup.POST("organizations/create", container.Controllers.Admin.Organizations.Create.Handle)
-		adminGroup.GET("organizations/:orgID/read", container.Controllers.Admin.Organizations.Read.Handle)
-		adminGroup.PUT("organizations/:orgID/update", container.Controllers.Admin.Organizations.Update.Handle)
+		adminGroup.GET("organizations/list", authz.RequirePermission("organization:browse"), container.Controllers.Admin.Organizations.List.Handle)
+		adminGroup.POST("organizations/create", authz.RequirePermission("organization:add"), container.Controllers.Admin.Organizations.Create.Handle)
+		adminGroup.GET("organizations/:orgID/read", authz.RequirePermission("organization:read"), container.Controllers.Admin.Organizations.Read.Handle)
+		adminGroup.PUT("organizations/:orgID/update", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Update.Handle)
+		adminGroup.GET("organizations/:orgID/usage", authz.RequirePermission("organization:read"), container.Controllers.Admin.Organizations.Usage.Handle)
 		// Organization Members Management Routes
-		adminGroup.POST("organizations/:orgID/members/add", container.Controllers.Admin.Organizations.Members.Add.Handle)
-		adminGroup.PUT("organizations/:orgID/members/:userID/update-role", container.Controllers.Admin.Organizations.Members.UpdateRole.Handle)
-		adminGroup.DELETE("organizations/:orgID/members/:userID/remove", container.Controllers.Admin.Organizations.Members.Remove.Handle)
+		adminGroup.POST("organizations/:orgID/members/add", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Members.Add.Handle)
+		adminGroup.PUT("organizations/:orgID/members/:userID/update-role", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Members.UpdateRole.Handle)
+		adminGroup.DELETE("organizations/:orgID/members/:userID/remove", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Members.Remove.Handle)
+		adminGroup.PUT("organizations/:orgID/limits/update", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Limits.Update.Handle)
+		adminGroup.PUT("organizations/:orgID/subscription/update", authz.RequirePermission("organization:edit"), container.Controllers.Admin.Organizations.Subscription.Update.Handle)
+		// Policy Management Routes
+		adminGroup.GET("policies/list", authz.RequirePermission("policy:browse"), container.Controllers.Admin.Policies.List.Handle)
+		adminGroup.POST("policies/create", authz.RequirePermission("policy:add"), container.Controllers.Admin.Policies.Create.Handle)
+		adminGroup.GET("policies/:policyID/read", authz.RequirePermission("policy:read"), container.Controllers.Admin.Policies.Read.Handle)
+		adminGroup.PUT("policies/:policyID/update", authz.RequirePermission("policy:edit"), container.Controllers.Admin.Policies.Update.Handle)
+		adminGroup.POST("policies/:policyID/simulate", authz.RequirePermission("policy:read"), container.Controllers.Admin.Policies.Simulate.Handle)
+		// Subscription Plan Management Routes
+		adminGroup.GET("plans/list", authz.RequirePermission("plan:browse"), container.Controllers.Admin.Plans.List.Handle)
+		adminGroup.POST("plans/create", authz.RequirePermission("plan:add"), container.Controllers.Admin.Plans.Create.Handle)
+		adminGroup.GET("plans/:planID/read", authz.RequirePermission("plan:read"), container.Controllers.Admin.Plans.Read.Handle)
+		adminGroup.PUT("plans/:planID/update", authz.RequirePermission("plan:edit"), container.Controllers.Admin.Plans.Update.Handle)
+		adminGroup.DELETE("plans/:planID/delete", authz.RequirePermission("plan:delete"), container.Controllers.Admin.Plans.Delete.Handle)
+		// Usage Export Routes
+		adminGroup.GET("usage/export", authz.RequirePermission("usage:export"), container.Controllers.Admin.Usage.Export.Handle)
 	}
 
 	// Public Routes
 	publicGroup := engine.Group("/public")
+	publicGroup.Use(container.Middleware.RateLimit.Handle("public"))
 	{
 		publicGroup.GET("/files/:fileID/download", container.Controllers.Public.Files.Download.Handle)
 		publicGroup.GET("/files/:fileID/read", container.Controllers.Public.Files.Read.Handle)
+		publicGroup.GET("/exports/download", container.Controllers.Public.Exports.Download.Handle)
 	}
 }
@@ -0,0 +1,63 @@
+package start
+
+import (
+	"context"
+	"time"
+
+	"bongaquino/server/config"
+	ioc "bongaquino/server/core/container"
+	"bongaquino/server/core/logger"
+)
+
+// job is a background task run on a fixed interval
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// StartScheduler runs the background jobs, a Redis lock lets only one server instance run each job per interval
+func StartScheduler(container *ioc.Container) {
+	serviceAccountConfig := config.LoadServiceAccountConfig()
+	accountConfig := config.LoadAccountConfig()
+	usageConfig := config.LoadUsageConfig()
+
+	jobs := []job{
+		{"service_account_expiry_warnings", serviceAccountConfig.JobInterval, container.Services.ServiceAccount.WarnExpiringSecrets},
+		{"service_account_inactivity", serviceAccountConfig.JobInterval, container.Services.ServiceAccount.DisableInactiveAccounts},
+		{"data_exports", accountConfig.ExportJobInterval, container.Services.DataExport.ProcessPendingExports},
+		{"account_deletions", accountConfig.DeletionJobInterval, container.Services.AccountDeletion.DeleteDueAccounts},
+		{"usage_snapshots", usageConfig.SnapshotJobInterval, container.Services.Usage.SnapshotUsage},
+	}
+
+	for _, j := range jobs {
+		go func(j job) {
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				runJob(container, j)
+				<-ticker.C
+			}
+		}(j)
+	}
+}
+
+// runJob runs a job once unless another instance already did within the interval
+func runJob(container *ioc.Container, j job) {
+	ctx, cancel := context.WithTimeout(context.Background(), j.interval)
+	defer cancel()
+
+	acquired, err := container.Providers.Redis.SetNX(ctx, "job_lock:"+j.name, "1", j.interval*9/10)
+	if err != nil {
+		logger.Log.Error("failed to acquire job lock", logger.String("job", j.name), logger.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	if err := j.run(ctx); err != nil {
+		logger.Log.Error("job failed", logger.String("job", j.name), logger.Error(err))
+	}
+}